@@ -161,6 +161,9 @@ func applyDataFrame(ctx context.Context, df *DataFrame, fn ApplyDataFrameFn, opt
 	}
 
 	if !opts[0].InPlace {
+		if df.index != nil {
+			ndf.index = df.index.Copy()
+		}
 		return ndf, nil
 	}
 
@@ -0,0 +1,491 @@
+// Copyright 2018-20 PJ Engineering and Business Solutions Pty. Ltd. All rights reserved.
+
+package dataframe
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// ArithOp is an element-wise arithmetic operation.
+type ArithOp int
+
+const (
+	// OpAdd adds the operands.
+	OpAdd ArithOp = iota
+	// OpSub subtracts the second operand from the first.
+	OpSub
+	// OpMul multiplies the operands.
+	OpMul
+	// OpDiv divides the first operand by the second.
+	OpDiv
+	// OpPow raises the first operand to the power of the second.
+	OpPow
+	// OpMod returns the remainder of dividing the first operand by the second.
+	OpMod
+)
+
+// CompareOp is an element-wise comparison.
+type CompareOp int
+
+const (
+	// OpEq checks if the operands are equal.
+	OpEq CompareOp = iota
+	// OpNe checks if the operands are not equal.
+	OpNe
+	// OpLt checks if the first operand is less than the second.
+	OpLt
+	// OpLe checks if the first operand is less than or equal to the second.
+	OpLe
+	// OpGt checks if the first operand is greater than the second.
+	OpGt
+	// OpGe checks if the first operand is greater than or equal to the second.
+	OpGe
+)
+
+// ArithOptions modifies the behavior of the arithmetic and comparison functions.
+type ArithOptions struct {
+
+	// DontLock can be set to true if the series should not be locked.
+	DontLock bool
+}
+
+// Arither can be implemented by a custom Series to support element-wise arithmetic and comparisons.
+// When either operand of Arith or Compare implements Arither, the operation is delegated to it.
+// If reverse is set, the Arither is the second operand.
+//
+// See: xseries.SeriesComplex128
+type Arither interface {
+	Arith(ctx context.Context, op ArithOp, other interface{}, reverse bool, opts ...ArithOptions) (Series, error)
+	Compare(ctx context.Context, op CompareOp, other interface{}, reverse bool, opts ...ArithOptions) (*SeriesBool, error)
+}
+
+// Add returns a + b. See Arith.
+func Add(ctx context.Context, a, b interface{}, opts ...ArithOptions) (Series, error) {
+	return Arith(ctx, OpAdd, a, b, opts...)
+}
+
+// Sub returns a - b. See Arith.
+func Sub(ctx context.Context, a, b interface{}, opts ...ArithOptions) (Series, error) {
+	return Arith(ctx, OpSub, a, b, opts...)
+}
+
+// Mul returns a * b. See Arith.
+func Mul(ctx context.Context, a, b interface{}, opts ...ArithOptions) (Series, error) {
+	return Arith(ctx, OpMul, a, b, opts...)
+}
+
+// Div returns a / b. See Arith.
+func Div(ctx context.Context, a, b interface{}, opts ...ArithOptions) (Series, error) {
+	return Arith(ctx, OpDiv, a, b, opts...)
+}
+
+// Pow returns a ^ b. See Arith.
+func Pow(ctx context.Context, a, b interface{}, opts ...ArithOptions) (Series, error) {
+	return Arith(ctx, OpPow, a, b, opts...)
+}
+
+// Mod returns a % b. See Arith.
+func Mod(ctx context.Context, a, b interface{}, opts ...ArithOptions) (Series, error) {
+	return Arith(ctx, OpMod, a, b, opts...)
+}
+
+// Eq returns a == b. See Compare.
+func Eq(ctx context.Context, a, b interface{}, opts ...ArithOptions) (*SeriesBool, error) {
+	return Compare(ctx, OpEq, a, b, opts...)
+}
+
+// Ne returns a != b. See Compare.
+func Ne(ctx context.Context, a, b interface{}, opts ...ArithOptions) (*SeriesBool, error) {
+	return Compare(ctx, OpNe, a, b, opts...)
+}
+
+// Lt returns a < b. See Compare.
+func Lt(ctx context.Context, a, b interface{}, opts ...ArithOptions) (*SeriesBool, error) {
+	return Compare(ctx, OpLt, a, b, opts...)
+}
+
+// Le returns a <= b. See Compare.
+func Le(ctx context.Context, a, b interface{}, opts ...ArithOptions) (*SeriesBool, error) {
+	return Compare(ctx, OpLe, a, b, opts...)
+}
+
+// Gt returns a > b. See Compare.
+func Gt(ctx context.Context, a, b interface{}, opts ...ArithOptions) (*SeriesBool, error) {
+	return Compare(ctx, OpGt, a, b, opts...)
+}
+
+// Ge returns a >= b. See Compare.
+func Ge(ctx context.Context, a, b interface{}, opts ...ArithOptions) (*SeriesBool, error) {
+	return Compare(ctx, OpGe, a, b, opts...)
+}
+
+// Arith performs an element-wise arithmetic operation and returns a new Series.
+// Each operand can be a SeriesInt64, SeriesFloat64, a Series implementing Arither (such as xseries.SeriesComplex128)
+// or a scalar (int, int64 or float64). At least one operand must be a Series, and all Series operands
+// must have the same number of rows. The new Series takes the name of the first Series operand.
+//
+// If either value is nil, the result is nil. Division or modulo by zero between int64 operands also yields nil.
+//
+// Operations between two int64 operands return a SeriesInt64, except OpDiv and OpPow which return
+// a SeriesFloat64. Otherwise int64 operands are promoted to float64.
+//
+// Example:
+//
+//  s, _ := dataframe.Add(ctx, sf, si)
+//  s, _ = dataframe.Mul(ctx, s, 2)
+//
+func Arith(ctx context.Context, op ArithOp, a, b interface{}, opts ...ArithOptions) (Series, error) {
+
+	if x, ok := a.(Arither); ok {
+		return x.Arith(ctx, op, b, false, opts...)
+	}
+	if x, ok := b.(Arither); ok {
+		return x.Arith(ctx, op, a, true, opts...)
+	}
+
+	l, r, err := newOperands(a, b, opts...)
+	if err != nil {
+		return nil, err
+	}
+	defer l.unlock()
+	defer r.unlock()
+
+	if l.isInt() && r.isInt() && op != OpDiv && op != OpPow {
+		return arithInt64(ctx, op, l, r)
+	}
+	return arithFloat64(ctx, op, l, r)
+}
+
+// Compare performs an element-wise comparison and returns a new SeriesBool.
+// The operands are treated the same way as Arith. If either value is nil, the result is nil.
+//
+// Example:
+//
+//  mask, _ := dataframe.Gt(ctx, s, 10)
+//
+func Compare(ctx context.Context, op CompareOp, a, b interface{}, opts ...ArithOptions) (*SeriesBool, error) {
+
+	if x, ok := a.(Arither); ok {
+		return x.Compare(ctx, op, b, false, opts...)
+	}
+	if x, ok := b.(Arither); ok {
+		return x.Compare(ctx, op, a, true, opts...)
+	}
+
+	l, r, err := newOperands(a, b, opts...)
+	if err != nil {
+		return nil, err
+	}
+	defer l.unlock()
+	defer r.unlock()
+
+	n := l.nRows(r)
+	out := NewSeriesBool(l.seriesName(r), &SeriesInit{Size: n})
+	bothInt := l.isInt() && r.isInt()
+
+	for row := 0; row < n; row++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		var c int
+		if bothInt {
+			x, xNil := l.int64(row)
+			y, yNil := r.int64(row)
+			if xNil || yNil {
+				continue
+			}
+			c = compareInt64(x, y)
+		} else {
+			x, y := l.float64(row), r.float64(row)
+			if isNaN(x) || isNaN(y) {
+				continue
+			}
+			c = compareFloat64(x, y)
+		}
+
+		var val bool
+		switch op {
+		case OpEq:
+			val = c == 0
+		case OpNe:
+			val = c != 0
+		case OpLt:
+			val = c < 0
+		case OpLe:
+			val = c <= 0
+		case OpGt:
+			val = c > 0
+		case OpGe:
+			val = c >= 0
+		default:
+			return nil, fmt.Errorf("unknown comparison: %d", op)
+		}
+		out.set(row, val, false)
+	}
+
+	return out, nil
+}
+
+// operand is a Series or scalar that arithmetic is performed on.
+type operand struct {
+	ints   *SeriesInt64
+	floats *SeriesFloat64
+
+	scalarInt   *int64
+	scalarFloat *float64
+
+	locked bool
+}
+
+func newOperands(a, b interface{}, opts ...ArithOptions) (*operand, *operand, error) {
+
+	if len(opts) == 0 {
+		opts = append(opts, ArithOptions{})
+	}
+
+	l, err := newOperand(a)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	r, err := newOperand(b)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if l.isScalar() && r.isScalar() {
+		return nil, nil, errors.New("at least one operand must be a series")
+	}
+
+	if !l.isScalar() && !r.isScalar() && l.nRows(nil) != r.nRows(nil) {
+		return nil, nil, errors.New("different number of rows in series")
+	}
+
+	if !opts[0].DontLock {
+		l.lock()
+		if !l.sameSeries(r) {
+			r.lock()
+		}
+	}
+
+	return l, r, nil
+}
+
+func newOperand(v interface{}) (*operand, error) {
+	switch x := v.(type) {
+	case *SeriesInt64:
+		return &operand{ints: x}, nil
+	case *SeriesFloat64:
+		return &operand{floats: x}, nil
+	case int:
+		return &operand{scalarInt: &[]int64{int64(x)}[0]}, nil
+	case int64:
+		return &operand{scalarInt: &x}, nil
+	case float64:
+		return &operand{scalarFloat: &x}, nil
+	default:
+		return nil, fmt.Errorf("unsupported operand type: %T", v)
+	}
+}
+
+func (o *operand) isInt() bool {
+	return o.ints != nil || o.scalarInt != nil
+}
+
+func (o *operand) isScalar() bool {
+	return o.scalarInt != nil || o.scalarFloat != nil
+}
+
+func (o *operand) sameSeries(other *operand) bool {
+	return (o.ints != nil && o.ints == other.ints) || (o.floats != nil && o.floats == other.floats)
+}
+
+func (o *operand) lock() {
+	switch {
+	case o.ints != nil:
+		o.ints.lock.RLock()
+	case o.floats != nil:
+		o.floats.lock.RLock()
+	default:
+		return
+	}
+	o.locked = true
+}
+
+func (o *operand) unlock() {
+	if !o.locked {
+		return
+	}
+	if o.ints != nil {
+		o.ints.lock.RUnlock()
+	} else {
+		o.floats.lock.RUnlock()
+	}
+	o.locked = false
+}
+
+// nRows returns the number of rows of the operands. other can be nil.
+func (o *operand) nRows(other *operand) int {
+	switch {
+	case o.ints != nil:
+		return len(o.ints.values)
+	case o.floats != nil:
+		return len(o.floats.Values)
+	case other != nil:
+		return other.nRows(nil)
+	}
+	return 0
+}
+
+// seriesName returns the name of the first Series operand.
+func (o *operand) seriesName(other *operand) string {
+	switch {
+	case o.ints != nil:
+		return o.ints.name
+	case o.floats != nil:
+		return o.floats.name
+	case other != nil:
+		return other.seriesName(nil)
+	}
+	return ""
+}
+
+// int64 returns the value at row. It must only be called when isInt returns true.
+func (o *operand) int64(row int) (int64, bool) {
+	if o.scalarInt != nil {
+		return *o.scalarInt, false
+	}
+	v := o.ints.values[row]
+	if v == nil {
+		return 0, true
+	}
+	return *v, false
+}
+
+// float64 returns the value at row. A nil value is returned as NaN.
+func (o *operand) float64(row int) float64 {
+	switch {
+	case o.floats != nil:
+		return o.floats.Values[row]
+	case o.scalarFloat != nil:
+		return *o.scalarFloat
+	}
+
+	v, isNil := o.int64(row)
+	if isNil {
+		return nan()
+	}
+	return float64(v)
+}
+
+func arithInt64(ctx context.Context, op ArithOp, l, r *operand) (*SeriesInt64, error) {
+
+	n := l.nRows(r)
+	out := NewSeriesInt64(l.seriesName(r), &SeriesInit{Capacity: n})
+
+	for row := 0; row < n; row++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		x, xNil := l.int64(row)
+		y, yNil := r.int64(row)
+		if xNil || yNil || (op == OpMod && y == 0) {
+			out.values = append(out.values, nil)
+			out.nilCount++
+			continue
+		}
+
+		var val int64
+		switch op {
+		case OpAdd:
+			val = x + y
+		case OpSub:
+			val = x - y
+		case OpMul:
+			val = x * y
+		case OpMod:
+			val = x % y
+		default:
+			return nil, fmt.Errorf("unknown operation: %d", op)
+		}
+		out.values = append(out.values, &val)
+	}
+
+	return out, nil
+}
+
+func arithFloat64(ctx context.Context, op ArithOp, l, r *operand) (*SeriesFloat64, error) {
+
+	n := l.nRows(r)
+	out := NewSeriesFloat64(l.seriesName(r), &SeriesInit{Capacity: n})
+
+	// Integer division by zero yields nil rather than an infinity
+	intDiv := op == OpDiv && l.isInt() && r.isInt()
+
+	// NaN is propagated by floating point operations, so nil values don't need special treatment.
+	for row := 0; row < n; row++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		x, y := l.float64(row), r.float64(row)
+
+		var val float64
+		switch op {
+		case OpAdd:
+			val = x + y
+		case OpSub:
+			val = x - y
+		case OpMul:
+			val = x * y
+		case OpDiv:
+			if intDiv && y == 0 {
+				val = nan()
+			} else {
+				val = x / y
+			}
+		case OpPow:
+			if isNaN(x) || isNaN(y) {
+				val = nan() // math.Pow(1, NaN) returns 1
+			} else {
+				val = math.Pow(x, y)
+			}
+		case OpMod:
+			val = math.Mod(x, y)
+		default:
+			return nil, fmt.Errorf("unknown operation: %d", op)
+		}
+
+		if isNaN(val) {
+			out.nilCount++
+		}
+		out.Values = append(out.Values, val)
+	}
+
+	return out, nil
+}
+
+func compareInt64(x, y int64) int {
+	switch {
+	case x < y:
+		return -1
+	case x > y:
+		return 1
+	}
+	return 0
+}
+
+func compareFloat64(x, y float64) int {
+	switch {
+	case x < y:
+		return -1
+	case x > y:
+		return 1
+	}
+	return 0
+}
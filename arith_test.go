@@ -0,0 +1,96 @@
+// Copyright 2018-20 PJ Engineering and Business Solutions Pty. Ltd. All rights reserved.
+
+package dataframe
+
+import (
+	"context"
+	"math"
+	"testing"
+)
+
+func TestArith(t *testing.T) {
+	ctx := context.Background()
+
+	si := NewSeriesInt64("a", nil, 7, nil, 3, 4)
+	sf := NewSeriesFloat64("b", nil, 2.0, 1.0, nil, 0.5)
+
+	tests := []struct {
+		op       ArithOp
+		a, b     interface{}
+		expected Series
+	}{
+		{OpAdd, si, si, NewSeriesInt64("a", nil, 14, nil, 6, 8)},
+		{OpSub, si, 1, NewSeriesInt64("a", nil, 6, nil, 2, 3)},
+		{OpMul, 2, si, NewSeriesInt64("a", nil, 14, nil, 6, 8)},
+		{OpMod, si, NewSeriesInt64("c", nil, 4, 1, 0, 3), NewSeriesInt64("a", nil, 3, nil, nil, 1)},
+		{OpDiv, si, 2, NewSeriesFloat64("a", nil, 3.5, nil, 1.5, 2.0)},
+		{OpDiv, si, NewSeriesInt64("c", nil, 0, 1, 0, 2), NewSeriesFloat64("a", nil, nil, nil, nil, 2.0)},
+		{OpDiv, 1, NewSeriesInt64("c", nil, 0, 2), NewSeriesFloat64("c", nil, nil, 0.5)},
+		{OpDiv, sf, 0, NewSeriesFloat64("b", nil, math.Inf(1), math.Inf(1), nil, math.Inf(1))},
+		{OpPow, si, 2, NewSeriesFloat64("a", nil, 49.0, nil, 9.0, 16.0)},
+		{OpAdd, si, sf, NewSeriesFloat64("a", nil, 9.0, nil, nil, 4.5)},
+		{OpDiv, sf, si, NewSeriesFloat64("b", nil, 2.0/7, nil, nil, 0.125)},
+		{OpPow, 1.0, sf, NewSeriesFloat64("b", nil, 1.0, 1.0, nil, 1.0)},
+		{OpMod, sf, 2, NewSeriesFloat64("b", nil, 0.0, 1.0, nil, 0.5)},
+	}
+
+	for i, tc := range tests {
+		s, err := Arith(ctx, tc.op, tc.a, tc.b)
+		if err != nil {
+			t.Fatalf("%d: wrong err: expected: %v actual: %v", i, nil, err)
+		}
+
+		if eq, _ := s.IsEqual(ctx, tc.expected, IsEqualOptions{CheckName: true}); !eq {
+			t.Errorf("%d: wrong val: expected: %v actual: %v", i, tc.expected, s)
+		}
+
+		expectedNC, _ := tc.expected.NilCount()
+		if nc, _ := s.NilCount(); nc != expectedNC {
+			t.Errorf("%d: wrong val: expected: %v actual: %v", i, expectedNC, nc)
+		}
+	}
+
+	// Errors
+	if _, err := Add(ctx, 1, 2); err == nil {
+		t.Errorf("wrong err: expected: %v actual: %v", "error", err)
+	}
+
+	if _, err := Add(ctx, si, NewSeriesInt64("c", nil, 1)); err == nil {
+		t.Errorf("wrong err: expected: %v actual: %v", "error", err)
+	}
+
+	if _, err := Add(ctx, si, NewSeriesString("c", nil, "1", "2", "3", "4")); err == nil {
+		t.Errorf("wrong err: expected: %v actual: %v", "error", err)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	ctx := context.Background()
+
+	si := NewSeriesInt64("a", nil, 7, nil, 3, 4)
+	sf := NewSeriesFloat64("b", nil, 2.0, 1.0, nil, 4.0)
+
+	tests := []struct {
+		op       CompareOp
+		a, b     interface{}
+		expected *SeriesBool
+	}{
+		{OpEq, si, 3, NewSeriesBool("a", nil, false, nil, true, false)},
+		{OpNe, si, 3, NewSeriesBool("a", nil, true, nil, false, true)},
+		{OpLt, si, sf, NewSeriesBool("a", nil, false, nil, nil, false)},
+		{OpLe, si, sf, NewSeriesBool("a", nil, false, nil, nil, true)},
+		{OpGt, 3.5, si, NewSeriesBool("a", nil, false, nil, true, false)},
+		{OpGe, sf, 2, NewSeriesBool("b", nil, true, false, nil, true)},
+	}
+
+	for i, tc := range tests {
+		s, err := Compare(ctx, tc.op, tc.a, tc.b)
+		if err != nil {
+			t.Fatalf("%d: wrong err: expected: %v actual: %v", i, nil, err)
+		}
+
+		if eq, _ := s.IsEqual(ctx, tc.expected, IsEqualOptions{CheckName: true}); !eq {
+			t.Errorf("%d: wrong val: expected: %v actual: %v", i, tc.expected, s)
+		}
+	}
+}
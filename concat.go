@@ -0,0 +1,232 @@
+// Copyright 2018-20 PJ Engineering and Business Solutions Pty. Ltd. All rights reserved.
+
+package dataframe
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ConcatOptions modifies the behavior of the Concat and ConcatSeries functions.
+type ConcatOptions struct {
+
+	// Upcast can be set so that Series with differing types are combined.
+	// SeriesInt64 and SeriesFloat64 are combined into a SeriesFloat64.
+	// All other combinations are combined into a SeriesMixed.
+	// By default, an error is returned if the types differ.
+	Upcast bool
+
+	// DontLock can be set to true if the DataFrames or Series should not be locked.
+	DontLock bool
+}
+
+// Concat stacks DataFrames row-wise into a new DataFrame. Series are aligned by name in order
+// of first appearance. When a DataFrame does not contain a particular Series, the rows are filled with nil.
+//
+// Example:
+//
+//  df, err := dataframe.Concat(ctx, nil, df1, df2, df3)
+//
+func Concat(ctx context.Context, opts *ConcatOptions, dfs ...*DataFrame) (*DataFrame, error) {
+
+	if opts == nil {
+		opts = &ConcatOptions{}
+	}
+
+	if !opts.DontLock {
+		locked := map[*DataFrame]struct{}{}
+		for _, df := range dfs {
+			if _, exists := locked[df]; exists {
+				continue
+			}
+			locked[df] = struct{}{}
+			df.lock.RLock()
+			defer df.lock.RUnlock()
+		}
+	}
+
+	// Align series by name
+	names := []string{}
+	aligned := map[string][]Series{} // For each DataFrame, the series with the name (or nil)
+
+	for i, df := range dfs {
+		for _, s := range df.Series {
+			name := s.Name()
+			if _, exists := aligned[name]; !exists {
+				names = append(names, name)
+				aligned[name] = make([]Series, len(dfs))
+			}
+			aligned[name][i] = s
+		}
+	}
+
+	var nRows int
+	for _, df := range dfs {
+		nRows = nRows + df.n
+	}
+
+	seriess := []Series{}
+
+	for _, name := range names {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		ns, err := concatSeries(ctx, name, nRows, aligned[name], dfs, opts.Upcast)
+		if err != nil {
+			return nil, err
+		}
+		seriess = append(seriess, ns)
+	}
+
+	return NewDataFrame(seriess...), nil
+}
+
+// ConcatSeries stacks Series into a new Series. The name of the first Series is used.
+func ConcatSeries(ctx context.Context, opts *ConcatOptions, ss ...Series) (Series, error) {
+
+	if len(ss) == 0 {
+		return nil, errors.New("at least 1 Series is required")
+	}
+
+	if opts == nil {
+		opts = &ConcatOptions{}
+	}
+
+	if !opts.DontLock {
+		locked := map[Series]struct{}{}
+		for _, s := range ss {
+			if _, exists := locked[s]; exists {
+				continue
+			}
+			locked[s] = struct{}{}
+			s.Lock()
+			defer s.Unlock()
+		}
+	}
+
+	var nRows int
+	for _, s := range ss {
+		nRows = nRows + s.NRows(dontLock)
+	}
+
+	return concatSeries(ctx, ss[0].Name(dontLock), nRows, ss, nil, opts.Upcast)
+}
+
+// concatSeries stacks ss into a new Series. When dfs is provided, a nil entry in ss
+// is filled with the number of rows in the corresponding DataFrame.
+func concatSeries(ctx context.Context, name string, nRows int, ss []Series, dfs []*DataFrame, upcast bool) (Series, error) {
+
+	ns, err := newCommonSeries(ss, name, &SeriesInit{Capacity: nRows}, upcast)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, s := range ss {
+		if s == nil {
+			for row := 0; row < dfs[i].n; row++ {
+				ns.Append(nil, dontLock)
+			}
+			continue
+		}
+
+		iterator := s.ValuesIterator(ValuesOptions{InitialRow: 0, Step: 1, DontReadLock: true})
+		for {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+
+			row, val, _ := iterator()
+			if row == nil {
+				break
+			}
+			ns.Append(val, dontLock)
+		}
+	}
+
+	return ns, nil
+}
+
+// ConcatColumns combines the Series of multiple DataFrames column-wise into a new DataFrame.
+// All DataFrames must have the same number of rows and the names of the Series must be unique.
+// The Series are copied.
+func ConcatColumns(ctx context.Context, opts *ConcatOptions, dfs ...*DataFrame) (*DataFrame, error) {
+
+	if opts == nil || !opts.DontLock {
+		locked := map[*DataFrame]struct{}{}
+		for _, df := range dfs {
+			if _, exists := locked[df]; exists {
+				continue
+			}
+			locked[df] = struct{}{}
+			df.lock.RLock()
+			defer df.lock.RUnlock()
+		}
+	}
+
+	seriess := []Series{}
+	names := map[string]struct{}{}
+
+	for i, df := range dfs {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		if i > 0 && df.n != dfs[0].n {
+			return nil, errors.New("different number of rows in DataFrames")
+		}
+
+		for _, s := range df.Series {
+			name := s.Name()
+			if _, exists := names[name]; exists {
+				return nil, fmt.Errorf("names of series must be unique: %s", name)
+			}
+			names[name] = struct{}{}
+			seriess = append(seriess, s.Copy())
+		}
+	}
+
+	return NewDataFrame(seriess...), nil
+}
+
+// newCommonSeries creates a new initialized Series that can store the values of all
+// the Series in ss. nil entries in ss are ignored. If the Series have different types
+// and upcast is false, an error is returned.
+func newCommonSeries(ss []Series, name string, init *SeriesInit, upcast bool) (Series, error) {
+
+	var template Series
+	var sameType = true
+	var numerical = true
+
+	for _, s := range ss {
+		if s == nil {
+			continue
+		}
+
+		switch s.(type) {
+		case *SeriesInt64, *SeriesFloat64:
+		default:
+			numerical = false
+		}
+
+		if template == nil {
+			template = s
+		} else if fmt.Sprintf("%T", s) != fmt.Sprintf("%T", template) || s.Type() != template.Type() {
+			sameType = false
+		}
+	}
+
+	if template == nil {
+		return NewSeriesMixed(name, init), nil
+	}
+
+	if sameType {
+		return newSeriesLike(template, name, init), nil
+	} else if !upcast {
+		return nil, fmt.Errorf("Series %s have different types", name)
+	} else if numerical {
+		return NewSeriesFloat64(name, init), nil
+	}
+	return NewSeriesMixed(name, init), nil
+}
@@ -0,0 +1,93 @@
+// Copyright 2018-20 PJ Engineering and Business Solutions Pty. Ltd. All rights reserved.
+
+package dataframe
+
+import (
+	"context"
+	"testing"
+)
+
+func TestConcat(t *testing.T) {
+	ctx := context.Background()
+
+	df1 := NewDataFrame(
+		NewSeriesInt64("day", nil, 1, 2),
+		NewSeriesFloat64("sales", nil, 50.3, nil),
+	)
+
+	df2 := NewDataFrame(
+		NewSeriesString("note", nil, "x"),
+		NewSeriesInt64("day", nil, 3),
+	)
+
+	df3 := NewDataFrame(
+		NewSeriesInt64("sales", nil, 7),
+		NewSeriesInt64("day", nil, 4),
+	)
+
+	// Same types
+	out, err := Concat(ctx, nil, df1, df2)
+	if err != nil {
+		t.Fatalf("wrong err: expected: %v actual: %v", nil, err)
+	}
+
+	expected := NewDataFrame(
+		NewSeriesInt64("day", nil, 1, 2, 3),
+		NewSeriesFloat64("sales", nil, 50.3, nil, nil),
+		NewSeriesString("note", nil, nil, nil, "x"),
+	)
+
+	if eq, _ := out.IsEqual(ctx, expected, IsEqualOptions{CheckName: true}); !eq {
+		t.Errorf("wrong val: expected: %v actual: %v", expected.Table(), out.Table())
+	}
+
+	// Different types
+	_, err = Concat(ctx, nil, df1, df3)
+	if err == nil {
+		t.Errorf("wrong err: expected: %v actual: %v", "error", err)
+	}
+
+	out, err = Concat(ctx, &ConcatOptions{Upcast: true}, df1, df3)
+	if err != nil {
+		t.Fatalf("wrong err: expected: %v actual: %v", nil, err)
+	}
+
+	expected = NewDataFrame(
+		NewSeriesInt64("day", nil, 1, 2, 4),
+		NewSeriesFloat64("sales", nil, 50.3, nil, 7.0),
+	)
+
+	if eq, _ := out.IsEqual(ctx, expected, IsEqualOptions{CheckName: true}); !eq {
+		t.Errorf("wrong val: expected: %v actual: %v", expected.Table(), out.Table())
+	}
+
+	// Column-wise
+	_, err = ConcatColumns(ctx, nil, df1, df2)
+	if err == nil {
+		t.Errorf("wrong err: expected: %v actual: %v", "error", err)
+	}
+
+	out, err = ConcatColumns(ctx, nil, df1, NewDataFrame(NewSeriesString("note", nil, "x", "y")))
+	if err != nil {
+		t.Fatalf("wrong err: expected: %v actual: %v", nil, err)
+	}
+
+	if out.NRows() != 2 || len(out.Series) != 3 {
+		t.Errorf("wrong val: expected: %v actual: %v", "2x3", out.Table())
+	}
+
+	// Same DataFrame more than once
+	out, err = Concat(ctx, nil, df1, df1)
+	if err != nil {
+		t.Fatalf("wrong err: expected: %v actual: %v", nil, err)
+	}
+
+	expected = NewDataFrame(
+		NewSeriesInt64("day", nil, 1, 2, 1, 2),
+		NewSeriesFloat64("sales", nil, 50.3, nil, 50.3, nil),
+	)
+
+	if eq, _ := out.IsEqual(ctx, expected, IsEqualOptions{CheckName: true}); !eq {
+		t.Errorf("wrong val: expected: %v actual: %v", expected.Table(), out.Table())
+	}
+}
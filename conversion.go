@@ -57,3 +57,16 @@ type ToSeriesMixed interface {
 	// NOTE: The returned ErrorCollection should contain RowError objects.
 	ToSeriesMixed(context.Context, bool, ...func(interface{}) (interface{}, error)) (*SeriesMixed, error)
 }
+
+// ToSeriesCategorical is an interface used by the Dataframe to know if a particular
+// Series can be converted to a SeriesCategorical Series.
+type ToSeriesCategorical interface {
+
+	// ToSeriesCategorical is used to convert a particular Series to a SeriesCategorical.
+	// If the returned Series is not nil but an error is still provided,
+	// it means that some rows were not able to be converted. You can inspect
+	// the error to determine which rows were unconverted.
+	//
+	// NOTE: The returned ErrorCollection should contain RowError objects.
+	ToSeriesCategorical(context.Context, bool, ...func(interface{}) (*string, error)) (*SeriesCategorical, error)
+}
@@ -15,7 +15,8 @@ import (
 type DataFrame struct {
 	lock   sync.RWMutex
 	Series []Series
-	n      int // Number of rows
+	n      int    // Number of rows
+	index  Series // nil if rows are labelled by position
 }
 
 // NewDataFrame creates a new dataframe.
@@ -271,6 +272,11 @@ func (df *DataFrame) insert(row int, vals ...interface{}) {
 			}
 		}
 
+		if df.index != nil {
+			// New rows are not labelled
+			df.index.Insert(row, nil)
+		}
+
 		df.n++
 	}
 }
@@ -297,6 +303,9 @@ func (df *DataFrame) Remove(row int, opts ...Options) {
 	for i := range df.Series {
 		df.Series[i].Remove(row)
 	}
+	if df.index != nil {
+		df.index.Remove(row)
+	}
 	df.n--
 }
 
@@ -496,6 +505,9 @@ func (df *DataFrame) Swap(row1, row2 int, opts ...Options) {
 	for idx := range df.Series {
 		df.Series[idx].Swap(row1, row2)
 	}
+	if df.index != nil {
+		df.index.Swap(row1, row2)
+	}
 }
 
 // Lock will lock the Dataframe allowing you to directly manipulate
@@ -543,6 +555,10 @@ func (df *DataFrame) Copy(r ...Range) *DataFrame {
 		newDF.n = seriess[0].NRows(dontLock)
 	}
 
+	if df.index != nil {
+		newDF.index = df.index.Copy(r...)
+	}
+
 	return newDF
 }
 
@@ -52,7 +52,7 @@ func (df *DataFrame) Table(opts ...TableOptions) string {
 
 	data := [][]string{}
 
-	headers := []string{""} // row header is blank
+	headers := []string{df.rowHeader()}
 	footers := []string{fmt.Sprintf("%dx%d", df.n, len(df.Series))}
 	for idx, aSeries := range df.Series {
 		if len(columns) == 0 {
@@ -85,7 +85,7 @@ func (df *DataFrame) Table(opts ...TableOptions) string {
 
 		for row := s; row <= e; row++ {
 
-			sVals := []string{fmt.Sprintf("%d:", row)}
+			sVals := []string{df.rowLabel(row)}
 
 			for idx, aSeries := range df.Series {
 				if len(columns) == 0 {
@@ -137,7 +137,7 @@ func (df *DataFrame) String() string {
 
 	data := [][]string{}
 
-	headers := []string{""} // row header is blank
+	headers := []string{df.rowHeader()}
 	footers := []string{fmt.Sprintf("%dx%d", df.n, len(df.Series))}
 	for _, aSeries := range df.Series {
 		headers = append(headers, aSeries.Name())
@@ -156,7 +156,7 @@ func (df *DataFrame) String() string {
 			data = append(data, sVals)
 		}
 
-		sVals := []string{fmt.Sprintf("%d:", row)}
+		sVals := []string{df.rowLabel(row)}
 
 		for _, aSeries := range df.Series {
 			sVals = append(sVals, aSeries.ValueString(row))
@@ -179,3 +179,19 @@ func (df *DataFrame) String() string {
 
 	return buf.String()
 }
+
+// rowHeader returns the header of the row labels. It is the name of the index or blank.
+func (df *DataFrame) rowHeader() string {
+	if df.index == nil {
+		return ""
+	}
+	return df.index.Name()
+}
+
+// rowLabel returns the label of a row. It is the index's value or the row position.
+func (df *DataFrame) rowLabel(row int) string {
+	if df.index == nil {
+		return fmt.Sprintf("%d:", row)
+	}
+	return df.index.ValueString(row)
+}
@@ -0,0 +1,226 @@
+// Copyright 2018-20 PJ Engineering and Business Solutions Pty. Ltd. All rights reserved.
+
+package exports
+
+import (
+	"context"
+	"io"
+	"math"
+	"time"
+
+	"github.com/apache/arrow/go/arrow"
+	"github.com/apache/arrow/go/arrow/array"
+	"github.com/apache/arrow/go/arrow/bitutil"
+	"github.com/apache/arrow/go/arrow/ipc"
+	"github.com/apache/arrow/go/arrow/memory"
+	dataframe "github.com/rocketlaunchr/dataframe-go"
+)
+
+// ArrowExportOptions contains options for ExportToArrow and ExportToArrowFile functions.
+type ArrowExportOptions struct {
+
+	// Range is used to export a subset of rows from the dataframe.
+	Range dataframe.Range
+
+	// ZeroCopy will use the memory of a series directly where the layouts match, rather than copying the values.
+	// Currently this applies to SeriesFloat64, whose Values are used as the arrow data buffer.
+	ZeroCopy bool
+}
+
+// ExportToArrow exports a Dataframe as an arrow IPC stream.
+//
+// The series are mapped to the following arrow types:
+//
+//  SeriesBool                    -> BOOL
+//  SeriesInt64                   -> INT64
+//  SeriesFloat64                 -> FLOAT64
+//  SeriesString, other series    -> STRING
+//  SeriesTime                    -> TIMESTAMP (nanoseconds, UTC)
+//
+func ExportToArrow(ctx context.Context, w io.Writer, df *dataframe.DataFrame, options ...ArrowExportOptions) error {
+
+	df.Lock()
+	defer df.Unlock()
+
+	rec, err := arrowRecord(ctx, df, options...)
+	if err != nil {
+		return err
+	}
+	defer rec.Release()
+
+	aw := ipc.NewWriter(w, ipc.WithSchema(rec.Schema()))
+	if err := aw.Write(rec); err != nil {
+		aw.Close()
+		return err
+	}
+	return aw.Close()
+}
+
+// ExportToArrowFile exports a Dataframe as an arrow IPC file.
+// See ExportToArrow for how the series are mapped.
+func ExportToArrowFile(ctx context.Context, w io.WriteSeeker, df *dataframe.DataFrame, options ...ArrowExportOptions) error {
+
+	df.Lock()
+	defer df.Unlock()
+
+	rec, err := arrowRecord(ctx, df, options...)
+	if err != nil {
+		return err
+	}
+	defer rec.Release()
+
+	aw, err := ipc.NewFileWriter(w, ipc.WithSchema(rec.Schema()))
+	if err != nil {
+		return err
+	}
+	if err := aw.Write(rec); err != nil {
+		aw.Close()
+		return err
+	}
+	return aw.Close()
+}
+
+// arrowRecord converts the rows of df into an arrow record.
+func arrowRecord(ctx context.Context, df *dataframe.DataFrame, options ...ArrowExportOptions) (array.Record, error) {
+
+	if len(options) == 0 {
+		options = append(options, ArrowExportOptions{})
+	}
+
+	var s, e int
+
+	nRows := df.NRows(dataframe.DontLock)
+	if nRows > 0 {
+		var err error
+		s, e, err = options[0].Range.Limits(nRows)
+		if err != nil {
+			return nil, err
+		}
+		e++ // exclusive
+	}
+
+	mem := memory.DefaultAllocator
+
+	fields := make([]arrow.Field, 0, len(df.Series))
+	cols := make([]array.Interface, 0, len(df.Series))
+	defer func() {
+		for _, col := range cols {
+			col.Release()
+		}
+	}()
+
+	for _, aSeries := range df.Series {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		var col array.Interface
+
+		switch se := aSeries.(type) {
+		case *dataframe.SeriesFloat64:
+			vals := se.Values[s:e]
+			if options[0].ZeroCopy {
+				col = zeroCopyFloat64(vals)
+				break
+			}
+
+			b := array.NewFloat64Builder(mem)
+			b.Reserve(len(vals))
+			for _, v := range vals {
+				if math.IsNaN(v) {
+					b.AppendNull()
+				} else {
+					b.Append(v)
+				}
+			}
+			col = b.NewArray()
+			b.Release()
+		case *dataframe.SeriesInt64:
+			b := array.NewInt64Builder(mem)
+			b.Reserve(e - s)
+			for row := s; row < e; row++ {
+				if v := se.Value(row, dataframe.DontLock); v != nil {
+					b.Append(v.(int64))
+				} else {
+					b.AppendNull()
+				}
+			}
+			col = b.NewArray()
+			b.Release()
+		case *dataframe.SeriesBool:
+			b := array.NewBooleanBuilder(mem)
+			b.Reserve(e - s)
+			for row := s; row < e; row++ {
+				if v := se.Value(row, dataframe.DontLock); v != nil {
+					b.Append(v.(bool))
+				} else {
+					b.AppendNull()
+				}
+			}
+			col = b.NewArray()
+			b.Release()
+		case *dataframe.SeriesTime:
+			b := array.NewTimestampBuilder(mem, arrow.FixedWidthTypes.Timestamp_ns.(*arrow.TimestampType))
+			b.Reserve(e - s)
+			for row := s; row < e; row++ {
+				if v := se.Value(row, dataframe.DontLock); v != nil {
+					b.Append(arrow.Timestamp(v.(time.Time).UnixNano()))
+				} else {
+					b.AppendNull()
+				}
+			}
+			col = b.NewArray()
+			b.Release()
+		default:
+			b := array.NewStringBuilder(mem)
+			b.Reserve(e - s)
+			for row := s; row < e; row++ {
+				if v := aSeries.Value(row, dataframe.DontLock); v != nil {
+					b.Append(aSeries.ValueString(row, dataframe.DontLock))
+				} else {
+					b.AppendNull()
+				}
+			}
+			col = b.NewArray()
+			b.Release()
+		}
+
+		cols = append(cols, col)
+		fields = append(fields, arrow.Field{Name: aSeries.Name(dataframe.DontLock), Type: col.DataType(), Nullable: true})
+	}
+
+	schema := arrow.NewSchema(fields, nil)
+	return array.NewRecord(schema, cols, int64(e-s)), nil
+}
+
+// zeroCopyFloat64 creates an arrow array that uses vals as its data buffer.
+// NaN values are marked as null in a separate validity bitmap.
+func zeroCopyFloat64(vals []float64) array.Interface {
+
+	var (
+		nulls    int
+		nullsBuf *memory.Buffer
+	)
+
+	for i, v := range vals {
+		if !math.IsNaN(v) {
+			continue
+		}
+
+		if nullsBuf == nil {
+			bitmap := make([]byte, bitutil.CeilByte(len(vals))/8)
+			for j := range bitmap {
+				bitmap[j] = 0xFF
+			}
+			nullsBuf = memory.NewBufferBytes(bitmap)
+		}
+		bitutil.ClearBit(nullsBuf.Bytes(), i)
+		nulls++
+	}
+
+	data := array.NewData(arrow.PrimitiveTypes.Float64, len(vals),
+		[]*memory.Buffer{nullsBuf, memory.NewBufferBytes(arrow.Float64Traits.CastToBytes(vals))}, nil, nulls, 0)
+	defer data.Release()
+
+	return array.NewFloat64Data(data)
+}
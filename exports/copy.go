@@ -0,0 +1,383 @@
+// Copyright 2018-20 PJ Engineering and Business Solutions Pty. Ltd. All rights reserved.
+
+package exports
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	dataframe "github.com/rocketlaunchr/dataframe-go"
+)
+
+// CopyFormat is the data format of a PostgreSQL COPY statement.
+type CopyFormat int
+
+const (
+	// CopyText is the tab-delimited text format. nil values are encoded as \N.
+	CopyText CopyFormat = 0
+	// CopyBinary is the binary format. It is faster for PostgreSQL to parse, but the column types
+	// of the table must exactly match the encoded types (see ExportToCopy).
+	CopyBinary CopyFormat = 1
+)
+
+// CopyFromer is implemented by a PostgreSQL connection that can execute a COPY FROM STDIN statement.
+// The data is read from r until io.EOF.
+//
+// Example (pgx):
+//
+//  type copyFromer struct{ conn *pgconn.PgConn }
+//
+//  func (c copyFromer) CopyFrom(ctx context.Context, r io.Reader, sql string) error {
+//  	_, err := c.conn.CopyFrom(ctx, r, sql)
+//  	return err
+//  }
+//
+type CopyFromer interface {
+	CopyFrom(ctx context.Context, r io.Reader, sql string) error
+}
+
+// CopyExportOptions contains options for ExportToCopy function.
+type CopyExportOptions struct {
+
+	// Range is used to export a subset of rows from the Dataframe.
+	Range dataframe.Range
+
+	// Format sets the format of the data.
+	Format CopyFormat
+}
+
+// ExportToCopy encodes a Dataframe in the PostgreSQL COPY format. The output is suitable for the data of a
+// "COPY table FROM STDIN" statement (or "COPY table FROM STDIN (FORMAT binary)" for CopyBinary).
+// No column names are written.
+//
+// The series are encoded as the following PostgreSQL types:
+//
+//  SeriesInt64                   -> BIGINT
+//  SeriesFloat64                 -> DOUBLE PRECISION
+//  SeriesBool                    -> BOOLEAN
+//  SeriesTime                    -> TIMESTAMP
+//  SeriesString, other series    -> TEXT
+//
+// Like ExportToSQL, times are encoded without a time zone (ie. the time's clock reading in its location).
+func ExportToCopy(ctx context.Context, w io.Writer, df *dataframe.DataFrame, options ...CopyExportOptions) error {
+
+	df.Lock()
+	defer df.Unlock()
+
+	if len(options) == 0 {
+		options = append(options, CopyExportOptions{})
+	}
+
+	enc := newCopyEncoder(w, options[0].Format)
+	if err := enc.header(); err != nil {
+		return err
+	}
+
+	nRows := df.NRows(dataframe.DontLock)
+	if nRows > 0 {
+		start, end, err := options[0].Range.Limits(nRows)
+		if err != nil {
+			return err
+		}
+
+		vals := make([]interface{}, len(df.Series))
+		for row := start; row <= end; row++ {
+
+			// context has been canceled
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+
+			for i, aSeries := range df.Series {
+				vals[i] = copyValue(aSeries, row)
+			}
+
+			if err := enc.row(vals); err != nil {
+				return err
+			}
+		}
+	}
+
+	return enc.close()
+}
+
+// copyToSQL exports the rows of df between start and end (inclusive) with a COPY FROM STDIN statement.
+// It is used by ExportToSQL when Copy is set.
+func copyToSQL(ctx context.Context, db execContexter, df *dataframe.DataFrame, tableName string, columnNames []string, start, end int, opts SQLExportOptions) error {
+
+	cf, ok := db.(CopyFromer)
+	if !ok {
+		return errors.New("db does not implement CopyFromer")
+	}
+
+	if opts.Database != PostgreSQL {
+		return errors.New("Copy is only supported for PostgreSQL")
+	}
+
+	if opts.OnConflict != nil {
+		return errors.New("OnConflict is not supported with Copy")
+	}
+
+	pk := opts.PrimaryKey
+	if pk != nil && pk.Value == nil {
+		// Let the database generate the primary key
+		columnNames = columnNames[1:]
+		pk = nil
+	}
+
+	seriess := []dataframe.Series{}
+	for _, aSeries := range df.Series {
+		colName, exists := opts.SeriesToColumn[aSeries.Name(dataframe.DontLock)]
+		if exists && colName == nil {
+			// Ignore column
+			continue
+		}
+		seriess = append(seriess, aSeries)
+	}
+
+	stmt := "COPY " + escapeNames(PostgreSQL, []string{tableName})[0] + " (" + strings.Join(escapeNames(PostgreSQL, columnNames), ",") + ") FROM STDIN"
+	if *opts.Copy == CopyBinary {
+		stmt = stmt + " (FORMAT binary)"
+	}
+
+	nRows := df.NRows(dataframe.DontLock)
+
+	encode := func(w io.Writer) error {
+		enc := newCopyEncoder(w, *opts.Copy)
+		if err := enc.header(); err != nil {
+			return err
+		}
+
+		vals := make([]interface{}, len(columnNames))
+		for row := start; row <= end; row++ {
+
+			// context has been canceled
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+
+			vals = vals[:0]
+			if pk != nil {
+				if v := pk.Value(row, nRows); v != nil {
+					vals = append(vals, *v)
+				} else {
+					vals = append(vals, nil)
+				}
+			}
+
+			for _, aSeries := range seriess {
+				val := copyValue(aSeries, row)
+				if val == nil && opts.NullString != nil {
+					val = *opts.NullString
+				}
+				vals = append(vals, val)
+			}
+
+			if err := enc.row(vals); err != nil {
+				return err
+			}
+		}
+
+		return enc.close()
+	}
+
+	// The data is encoded while it is being sent to the database
+	pr, pw := io.Pipe()
+
+	var encErr error
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		encErr = encode(pw)
+		pw.CloseWithError(encErr)
+	}()
+
+	err := cf.CopyFrom(ctx, pr, stmt)
+	pr.CloseWithError(errCopyFinished) // unblock the encoder if not all the data was read
+	<-done
+
+	// If the database failed, the encoder fails with errCopyFinished
+	if encErr != nil && (err == nil || encErr != errCopyFinished) {
+		return encErr
+	}
+	return err
+}
+
+// errCopyFinished is returned to the encoder when the data is no longer being read.
+var errCopyFinished = errors.New("copy finished")
+
+// copyValue returns the value of a series at row in a form that can be encoded.
+func copyValue(s dataframe.Series, row int) interface{} {
+
+	switch v := s.Value(row, dataframe.DontLock).(type) {
+	case nil, int64, float64, bool, time.Time, string:
+		return v
+	default:
+		return s.ValueString(row, dataframe.DontLock)
+	}
+}
+
+// copySignature is the start of the header of the binary format.
+var copySignature = []byte("PGCOPY\n\377\r\n\x00")
+
+// postgresEpoch is the epoch of times in the binary format.
+var postgresEpoch = time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// copyEncoder encodes rows in the COPY format.
+type copyEncoder struct {
+	w      *bufio.Writer
+	format CopyFormat
+	buf    []byte
+}
+
+func newCopyEncoder(w io.Writer, format CopyFormat) *copyEncoder {
+	return &copyEncoder{w: bufio.NewWriter(w), format: format}
+}
+
+// header writes the header of the binary format.
+func (e *copyEncoder) header() error {
+	if e.format != CopyBinary {
+		return nil
+	}
+
+	e.buf = append(e.buf[:0], copySignature...)
+	e.buf = append(e.buf, 0, 0, 0, 0) // flags
+	e.buf = append(e.buf, 0, 0, 0, 0) // header extension length
+
+	_, err := e.w.Write(e.buf)
+	return err
+}
+
+// row writes a row. The values must be nil, int64, float64, bool, time.Time or string.
+func (e *copyEncoder) row(vals []interface{}) error {
+
+	b := e.buf[:0]
+
+	if e.format == CopyBinary {
+		b = appendUint16(b, uint16(len(vals)))
+		for _, v := range vals {
+			b = appendCopyBinary(b, v)
+		}
+	} else {
+		for i, v := range vals {
+			if i > 0 {
+				b = append(b, '\t')
+			}
+			b = appendCopyText(b, v)
+		}
+		b = append(b, '\n')
+	}
+
+	e.buf = b
+	_, err := e.w.Write(b)
+	return err
+}
+
+// close writes the trailer of the binary format and flushes the data.
+func (e *copyEncoder) close() error {
+	if e.format == CopyBinary {
+		if _, err := e.w.Write([]byte{0xff, 0xff}); err != nil {
+			return err
+		}
+	}
+	return e.w.Flush()
+}
+
+func appendCopyText(b []byte, v interface{}) []byte {
+
+	switch v := v.(type) {
+	case nil:
+		return append(b, `\N`...)
+	case int64:
+		return strconv.AppendInt(b, v, 10)
+	case float64:
+		if math.IsInf(v, 1) {
+			return append(b, "Infinity"...)
+		} else if math.IsInf(v, -1) {
+			return append(b, "-Infinity"...)
+		}
+		return strconv.AppendFloat(b, v, 'g', -1, 64)
+	case bool:
+		if v {
+			return append(b, 't')
+		}
+		return append(b, 'f')
+	case time.Time:
+		return v.AppendFormat(b, "2006-01-02 15:04:05.999999")
+	case string:
+		for i := 0; i < len(v); i++ {
+			switch c := v[i]; c {
+			case '\\':
+				b = append(b, `\\`...)
+			case '\n':
+				b = append(b, `\n`...)
+			case '\r':
+				b = append(b, `\r`...)
+			case '\t':
+				b = append(b, `\t`...)
+			default:
+				b = append(b, c)
+			}
+		}
+		return b
+	}
+
+	return b
+}
+
+func appendCopyBinary(b []byte, v interface{}) []byte {
+
+	switch v := v.(type) {
+	case nil:
+		return appendUint32(b, math.MaxUint32) // -1
+	case int64:
+		b = appendUint32(b, 8)
+		return appendUint64(b, uint64(v))
+	case float64:
+		b = appendUint32(b, 8)
+		return appendUint64(b, math.Float64bits(v))
+	case bool:
+		b = appendUint32(b, 1)
+		if v {
+			return append(b, 1)
+		}
+		return append(b, 0)
+	case time.Time:
+		// Microseconds since the epoch of the clock reading
+		wall := time.Date(v.Year(), v.Month(), v.Day(), v.Hour(), v.Minute(), v.Second(), v.Nanosecond(), time.UTC)
+		micros := (wall.Unix()-postgresEpoch.Unix())*1000000 + int64(wall.Nanosecond()/1000)
+
+		b = appendUint32(b, 8)
+		return appendUint64(b, uint64(micros))
+	case string:
+		b = appendUint32(b, uint32(len(v)))
+		return append(b, v...)
+	}
+
+	return b
+}
+
+func appendUint16(b []byte, v uint16) []byte {
+	var buf [2]byte
+	binary.BigEndian.PutUint16(buf[:], v)
+	return append(b, buf[:]...)
+}
+
+func appendUint32(b []byte, v uint32) []byte {
+	var buf [4]byte
+	binary.BigEndian.PutUint32(buf[:], v)
+	return append(b, buf[:]...)
+}
+
+func appendUint64(b []byte, v uint64) []byte {
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], v)
+	return append(b, buf[:]...)
+}
@@ -4,7 +4,9 @@ package exports
 
 import (
 	"context"
+	"fmt"
 	"io"
+	"time"
 
 	dataframe "github.com/rocketlaunchr/dataframe-go"
 	"github.com/tealeg/xlsx/v3"
@@ -15,6 +17,7 @@ type ExcelExportOptions struct {
 
 	// NullString is used to set what nil values should be encoded to.
 	// Common options are NULL, \N, NaN, NA.
+	// When not set, nil values are written as empty cells.
 	NullString *string
 
 	// Range is used to export a subset of rows from the Dataframe.
@@ -23,56 +26,104 @@ type ExcelExportOptions struct {
 	// WriteSheet is used to specify a sheet name.
 	// When not set, it defaults to "sheet1"
 	WriteSheet *string
+
+	// NumberFormats sets the excel number format of a series' cells. The key is the series name.
+	// eg. "0.00", "#,##0", "0%" or "yyyy-mm-dd".
+	// When not set, times use the "m/d/yy h:mm" format and numbers use the "general" format.
+	NumberFormats map[string]string
+
+	// ColumnWidths sets the width (in characters) of a series' column. The key is the series name.
+	ColumnWidths map[string]float64
+
+	// FreezeHeader will freeze the header row so that it is always visible when scrolling.
+	FreezeHeader bool
+
+	// AutoFilter will add a filter to the header row.
+	AutoFilter bool
 }
 
-// ExportToExcel exports a Dataframe to an excel file.
-func ExportToExcel(ctx context.Context, w io.Writer, df *dataframe.DataFrame, options ...ExcelExportOptions) error {
+// ExcelSheet contains a DataFrame that is written to a sheet by ExportToExcelSheets.
+type ExcelSheet struct {
 
-	df.Lock()
-	defer df.Unlock()
+	// Name is the name of the sheet.
+	Name string
 
-	var (
-		sheetRow *xlsx.Row
-		file     *xlsx.File
-		cell     *xlsx.Cell
-	)
+	// DataFrame is the data that is written to the sheet.
+	DataFrame *dataframe.DataFrame
+
+	// Options modifies how the DataFrame is written. WriteSheet is ignored.
+	Options ExcelExportOptions
+}
 
-	nullString := "NaN"    // Default value
-	writeSheet := "sheet1" // Write to default sheet 1 if a different one is not set
+// ExportToExcel exports a Dataframe to an excel file.
+// Numbers, booleans and times are written as native excel values.
+func ExportToExcel(ctx context.Context, w io.Writer, df *dataframe.DataFrame, options ...ExcelExportOptions) error {
 
-	var r dataframe.Range
+	sheet := ExcelSheet{
+		Name:      "sheet1", // Write to default sheet 1 if a different one is not set
+		DataFrame: df,
+	}
 
 	if len(options) > 0 {
-		r = options[0].Range
-
-		if options[0].NullString != nil {
-			nullString = *options[0].NullString
-		}
+		sheet.Options = options[0]
 
 		if options[0].WriteSheet != nil {
-			writeSheet = *options[0].WriteSheet
+			sheet.Name = *options[0].WriteSheet
 		}
 	}
 
-	file = xlsx.NewFile()
-	sheet, err := file.AddSheet(writeSheet)
-	if err != nil {
-		return err
+	return ExportToExcelSheets(ctx, w, sheet)
+}
+
+// ExportToExcelSheets exports multiple Dataframes to the sheets of an excel file.
+//
+// Example:
+//
+//  err := exports.ExportToExcelSheets(ctx, w,
+//     exports.ExcelSheet{Name: "Sales", DataFrame: sales, Options: exports.ExcelExportOptions{FreezeHeader: true}},
+//     exports.ExcelSheet{Name: "Costs", DataFrame: costs},
+//  )
+//
+func ExportToExcelSheets(ctx context.Context, w io.Writer, sheets ...ExcelSheet) error {
+
+	file := xlsx.NewFile()
+
+	for _, s := range sheets {
+		sheet, err := file.AddSheet(s.Name)
+		if err != nil {
+			return err
+		}
+
+		if err := writeExcelSheet(ctx, sheet, s.DataFrame, s.Options); err != nil {
+			return err
+		}
 	}
 
+	// Save file
+	return file.Write(w)
+}
+
+func writeExcelSheet(ctx context.Context, sheet *xlsx.Sheet, df *dataframe.DataFrame, opts ExcelExportOptions) error {
+
+	df.Lock()
+	defer df.Unlock()
+
 	// Add first row to excel sheet for header fields
-	sheetRow = sheet.AddRow()
+	sheetRow := sheet.AddRow()
 	// Write Header fields first
 	for _, field := range df.Names(dataframe.DontLock) {
-		cell = sheetRow.AddCell() // set column cell
-		cell.Value = field        // assign field to cell
+		cell := sheetRow.AddCell() // set column cell
+		cell.SetString(field)      // assign field to cell
 	}
 
+	nCols := len(df.Series)
+	lastRow := 1
+
 	nRows := df.NRows(dataframe.DontLock)
 
 	if nRows > 0 {
 
-		s, e, err := r.Limits(nRows)
+		s, e, err := opts.Range.Limits(nRows)
 		if err != nil {
 			return err
 		}
@@ -85,26 +136,74 @@ func ExportToExcel(ctx context.Context, w io.Writer, df *dataframe.DataFrame, op
 				return err
 			}
 
-			// Add new role to excel sheet
+			// Add new row to excel sheet
 			sheetRow = sheet.AddRow()
+			lastRow++
 
 			// collecting rows
 			for _, aSeries := range df.Series {
-				val := aSeries.Value(row)
-				cell = sheetRow.AddCell()
-				if val == nil {
-					cell.Value = nullString
-				} else {
-					cell.Value = aSeries.ValueString(row)
+				cell := sheetRow.AddCell()
+				format := opts.NumberFormats[aSeries.Name(dataframe.DontLock)]
+
+				val := aSeries.Value(row, dataframe.DontLock)
+				switch v := val.(type) {
+				case nil:
+					if opts.NullString != nil {
+						cell.SetString(*opts.NullString)
+					}
+				case int64:
+					cell.SetInt64(v)
+					if format != "" {
+						cell.SetFormat(format)
+					}
+				case float64:
+					if format != "" {
+						cell.SetFloatWithFormat(v, format)
+					} else {
+						cell.SetFloat(v)
+					}
+				case bool:
+					cell.SetBool(v)
+				case time.Time:
+					if format != "" {
+						cell.SetDateWithOptions(v, xlsx.DateTimeOptions{Location: time.UTC, ExcelTimeFormat: format})
+					} else {
+						cell.SetDateTime(v)
+					}
+				case string:
+					cell.SetString(v)
+				default:
+					cell.SetString(aSeries.ValueString(row, dataframe.DontLock))
 				}
 			}
 		}
 	}
 
-	// Save file
-	err = file.Write(w)
-	if err != nil {
-		return err
+	// Formatting
+	for i, aSeries := range df.Series {
+		if width, exists := opts.ColumnWidths[aSeries.Name(dataframe.DontLock)]; exists {
+			sheet.SetColWidth(i+1, i+1, width)
+		}
+	}
+
+	if opts.FreezeHeader {
+		sheet.SheetViews = []xlsx.SheetView{
+			{
+				Pane: &xlsx.Pane{
+					YSplit:      1,
+					TopLeftCell: "A2",
+					ActivePane:  "bottomLeft",
+					State:       "frozen",
+				},
+			},
+		}
+	}
+
+	if opts.AutoFilter && nCols > 0 {
+		sheet.AutoFilter = &xlsx.AutoFilter{
+			TopLeftCell:     "A1",
+			BottomRightCell: fmt.Sprintf("%s%d", xlsx.ColIndexToLetters(nCols-1), lastRow),
+		}
 	}
 
 	return nil
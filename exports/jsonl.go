@@ -3,6 +3,7 @@
 package exports
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"io"
@@ -10,6 +11,24 @@ import (
 	dataframe "github.com/rocketlaunchr/dataframe-go"
 )
 
+// JSONOrient sets the layout of the json data.
+type JSONOrient int
+
+const (
+	// JSONLines is the jsonl format. Each line is an object representing a row.
+	//
+	// See: http://jsonlines.org/ for more information.
+	JSONLines JSONOrient = 0
+	// JSONRecords is an array of objects, each representing a row: [{"a":1,"b":2},{"a":3,"b":4}]
+	JSONRecords JSONOrient = 1
+	// JSONColumns is an object of arrays, each representing a column: {"a":[1,3],"b":[2,4]}
+	JSONColumns JSONOrient = 2
+	// JSONSplit is an object containing the column names and an array of rows: {"columns":["a","b"],"data":[[1,2],[3,4]]}
+	JSONSplit JSONOrient = 3
+	// JSONValues is an array of rows without column names: [[1,2],[3,4]]
+	JSONValues JSONOrient = 4
+)
+
 // JSONExportOptions contains options for ExportToJSON function.
 type JSONExportOptions struct {
 
@@ -24,10 +43,13 @@ type JSONExportOptions struct {
 	// SetEscapeHTML specifies whether problematic HTML characters should be escaped inside JSON quoted strings.
 	// See: https://golang.org/pkg/encoding/json/#Encoder.SetEscapeHTML
 	SetEscapeHTML bool
+
+	// Orient sets the layout of the json data. The default is JSONLines.
+	Orient JSONOrient
 }
 
-// ExportToJSON exports a Dataframe in the jsonl format.
-// Each line represents a row from the Dataframe.
+// ExportToJSON exports a Dataframe in the jsonl format (or another layout set by Orient).
+// For JSONLines, each line represents a row from the Dataframe.
 //
 // See: http://jsonlines.org/ for more information.
 func ExportToJSON(ctx context.Context, w io.Writer, df *dataframe.DataFrame, options ...JSONExportOptions) error {
@@ -37,55 +59,157 @@ func ExportToJSON(ctx context.Context, w io.Writer, df *dataframe.DataFrame, opt
 
 	var r dataframe.Range
 	var null *string // default is null
+	var orient JSONOrient
 
-	enc := json.NewEncoder(w)
+	enc := &jsonEncoder{w: w}
+	enc.enc = json.NewEncoder(&enc.buf)
 
 	if len(options) > 0 {
 
 		r = options[0].Range
+		orient = options[0].Orient
 
-		enc.SetEscapeHTML(options[0].SetEscapeHTML)
+		enc.enc.SetEscapeHTML(options[0].SetEscapeHTML)
 
 		if options[0].NullString != nil {
 			null = options[0].NullString
 		}
 	}
 
-	nRows := df.NRows(dataframe.DontLock)
+	var s, e int
 
+	nRows := df.NRows(dataframe.DontLock)
 	if nRows > 0 {
-
-		s, e, err := r.Limits(nRows)
+		var err error
+		s, e, err = r.Limits(nRows)
 		if err != nil {
 			return err
 		}
+		e++ // exclusive
+	}
+
+	value := func(aSeries dataframe.Series, row int) interface{} {
+		val := aSeries.Value(row, dataframe.DontLock)
+		if val == nil && null != nil {
+			return null
+		}
+		return val
+	}
 
-		for row := s; row <= e; row++ {
+	// rowValues writes the values of a row as an array
+	rowValues := func(row int) error {
+		enc.write("[")
+		for i, aSeries := range df.Series {
+			if i > 0 {
+				enc.write(",")
+			}
+			enc.encode(value(aSeries, row))
+		}
+		enc.write("]")
+		return enc.err
+	}
 
+	switch orient {
+	case JSONRecords:
+		enc.write("[")
+	case JSONColumns:
+		enc.write("{")
+		for i, aSeries := range df.Series {
 			if err := ctx.Err(); err != nil {
 				return err
 			}
 
-			record := map[string]interface{}{}
-			for _, aSeries := range df.Series {
+			if i > 0 {
+				enc.write(",")
+			}
+			enc.encode(aSeries.Name(dataframe.DontLock))
+			enc.write(":[")
+			for row := s; row < e; row++ {
+				if row > s {
+					enc.write(",")
+				}
+				enc.encode(value(aSeries, row))
+			}
+			enc.write("]")
+			if enc.err != nil {
+				return enc.err
+			}
+		}
+		enc.write("}\n")
+		return enc.err
+	case JSONSplit:
+		enc.write(`{"columns":`)
+		enc.encode(df.Names(dataframe.DontLock))
+		enc.write(`,"data":[`)
+	case JSONValues:
+		enc.write("[")
+	}
 
-				fieldName := aSeries.Name()
+	for row := s; row < e; row++ {
 
-				val := aSeries.Value(row)
+		if err := ctx.Err(); err != nil {
+			return err
+		}
 
-				if val == nil && null != nil {
-					record[fieldName] = null
-				} else {
-					record[fieldName] = val
-				}
-			}
+		if row > s && orient != JSONLines {
+			enc.write(",")
+		}
 
-			if err := enc.Encode(record); err != nil {
+		if orient == JSONSplit || orient == JSONValues {
+			if err := rowValues(row); err != nil {
 				return err
 			}
+			continue
+		}
 
+		record := map[string]interface{}{}
+		for _, aSeries := range df.Series {
+			record[aSeries.Name(dataframe.DontLock)] = value(aSeries, row)
 		}
+
+		enc.encode(record)
+		if orient == JSONLines {
+			enc.write("\n")
+		}
+		if enc.err != nil {
+			return enc.err
+		}
+	}
+
+	switch orient {
+	case JSONRecords, JSONValues:
+		enc.write("]\n")
+	case JSONSplit:
+		enc.write("]}\n")
 	}
 
-	return nil
+	return enc.err
+}
+
+// jsonEncoder writes json values without the trailing newline added by json.Encoder.
+// The first error encountered is stored in err and subsequent writes are ignored.
+type jsonEncoder struct {
+	w   io.Writer
+	buf bytes.Buffer
+	enc *json.Encoder
+	err error
+}
+
+func (je *jsonEncoder) encode(v interface{}) {
+	if je.err != nil {
+		return
+	}
+
+	je.buf.Reset()
+	if je.err = je.enc.Encode(v); je.err != nil {
+		return
+	}
+	_, je.err = je.w.Write(bytes.TrimSuffix(je.buf.Bytes(), []byte("\n")))
+}
+
+func (je *jsonEncoder) write(s string) {
+	if je.err != nil {
+		return
+	}
+	_, je.err = io.WriteString(je.w, s)
 }
@@ -4,16 +4,18 @@ package exports
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"reflect"
-	"strings"
 	"time"
 
-	dynamicstruct "github.com/ompluscator/dynamic-struct"
 	dataframe "github.com/rocketlaunchr/dataframe-go"
 	"github.com/xitongsys/parquet-go-source/writerfile"
+	"github.com/xitongsys/parquet-go/common"
+	"github.com/xitongsys/parquet-go/layout"
 	"github.com/xitongsys/parquet-go/parquet"
+	"github.com/xitongsys/parquet-go/schema"
 	"github.com/xitongsys/parquet-go/writer"
 )
 
@@ -40,8 +42,32 @@ type ParquetExportOptions struct {
 }
 
 // ExportToParquet exports a Dataframe as a Parquet file.
-// Series names are escaped by replacing spaces with underscores and removing ",;{}()=" (excluding quotes)
-// and then lower-casing for maximum cross-compatibility.
+// Series names are preserved. An error is returned if two names can't be distinguished by the parquet writer
+// (eg. "name" and "Name").
+//
+// The series are mapped to the following parquet types:
+//
+//  SeriesBool                      -> BOOLEAN
+//  SeriesInt64                     -> INT64
+//  SeriesFloat64                   -> DOUBLE
+//  SeriesString, SeriesCategorical -> BYTE_ARRAY (STRING)
+//  SeriesTime                      -> INT64 (TIMESTAMP, microseconds, adjusted to UTC)
+//
+// For SeriesGeneric and SeriesMixed, the type of the values is used:
+//
+//  bool                            -> BOOLEAN
+//  int8, int16, int32              -> INT32 (INT_8, INT_16 or plain)
+//  uint8, uint16, uint32           -> INT32 (UINT_8, UINT_16 or UINT_32)
+//  int, int64                      -> INT64
+//  uint, uint64                    -> INT64 (UINT_64)
+//  float32                         -> FLOAT
+//  float64                         -> DOUBLE
+//  string                          -> BYTE_ARRAY (STRING)
+//  time.Time                       -> INT64 (TIMESTAMP, microseconds, adjusted to UTC)
+//  structs                         -> group containing a column for each exported field
+//
+// Nil pointers in struct fields are written as null values. Other types, and series
+// that contain values of different types, are written as strings.
 func ExportToParquet(ctx context.Context, w io.Writer, df *dataframe.DataFrame, options ...ParquetExportOptions) error {
 
 	df.Lock()
@@ -61,42 +87,61 @@ func ExportToParquet(ctx context.Context, w io.Writer, df *dataframe.DataFrame,
 		offset = options[0].Offset
 	}
 
-	// Create Schema
-	dataSchema := dynamicstruct.NewStruct()
-	for _, aSeries := range df.Series {
-		fieldName := strings.Title(strings.ToLower(aSeries.Name()))
-		seriesName := santizeColumnName(aSeries.Name())
-
-		switch aSeries.(type) {
-		case *dataframe.SeriesFloat64:
-			tag := fmt.Sprintf(`parquet:"name=%s, type=DOUBLE, repetitiontype=OPTIONAL"`, seriesName)
-			dataSchema.AddField(fieldName, (*float64)(nil), tag)
-		case *dataframe.SeriesInt64:
-			tag := fmt.Sprintf(`parquet:"name=%s, type=INT64, repetitiontype=OPTIONAL"`, seriesName)
-			dataSchema.AddField(fieldName, (*int64)(nil), tag)
-		case *dataframe.SeriesTime:
-			tag := fmt.Sprintf(`parquet:"name=%s, type=TIME_MICROS, repetitiontype=OPTIONAL"`, seriesName)
-			dataSchema.AddField(fieldName, (*int64)(nil), tag)
-		case *dataframe.SeriesString:
-			tag := fmt.Sprintf(`parquet:"name=%s, type=UTF8, encoding=PLAIN_DICTIONARY, repetitiontype=OPTIONAL"`, seriesName)
-			dataSchema.AddField(fieldName, (*string)(nil), tag)
-		default:
-			tag := fmt.Sprintf(`parquet:"name=%s, type=UTF8, encoding=PLAIN_DICTIONARY, repetitiontype=OPTIONAL"`, seriesName)
-			dataSchema.AddField(fieldName, (*string)(nil), tag)
+	var s, e int
+
+	nRows := df.NRows(dataframe.DontLock)
+	if nRows > 0 {
+		var err error
+		s, e, err = r.Limits(nRows)
+		if err != nil {
+			return err
 		}
+		e++ // exclusive
+	}
 
+	// Create Schema
+	ps := &parquetSchema{
+		elements: []*parquet.SchemaElement{
+			{
+				Name:           "parquet_go_root",
+				RepetitionType: parquet.FieldRepetitionTypePtr(parquet.FieldRepetitionType_REQUIRED),
+				NumChildren:    int32Ptr(int32(len(df.Series))),
+			},
+		},
 	}
 
-	schemaStruct := dataSchema.Build()
+	// stringify records the series that are written using ValueString
+	stringify := make([]bool, len(df.Series))
+
+	for i, aSeries := range df.Series {
+		typ := parquetSeriesType(aSeries, s, e)
+		if typ == nil || !parquetSupported(typ) {
+			typ = reflect.TypeOf("")
+			stringify[i] = true
+		}
+		ps.add(aSeries.Name(dataframe.DontLock), typ, i, nil, map[reflect.Type]bool{})
+	}
 
 	fw := writerfile.NewWriterFile(w)
 	defer fw.Close()
 
-	pw, err := writer.NewParquetWriter(fw, schemaStruct.New(), 4)
+	pw, err := writer.NewParquetWriter(fw, ps.elements, 4)
 	if err != nil {
 		return err
 	}
 
+	if len(pw.SchemaHandler.MapIndex) != len(ps.elements) {
+		return errors.New("series names must be unique after conversion to parquet column names")
+	}
+
+	for _, leaf := range ps.leaves {
+		if ps.elements[leaf.element].GetType() == parquet.Type_BYTE_ARRAY {
+			pw.SchemaHandler.Infos[leaf.element].Encoding = parquet.Encoding_PLAIN_DICTIONARY
+		}
+	}
+
+	pw.MarshalFunc = ps.marshal
+
 	if compressionType != nil {
 		pw.CompressionType = *compressionType
 	}
@@ -109,49 +154,25 @@ func ExportToParquet(ctx context.Context, w io.Writer, df *dataframe.DataFrame,
 		pw.PageSize = *pageSize
 	}
 
-	nRows := df.NRows(dataframe.DontLock)
-	if nRows > 0 {
-
-		s, e, err := r.Limits(nRows)
-		if err != nil {
+	for row := s; row < e; row++ {
+		if err := ctx.Err(); err != nil {
 			return err
 		}
 
-		for row := s; row <= e; row++ {
-			if err := ctx.Err(); err != nil {
-				return err
+		rec := make([]interface{}, 0, len(df.Series))
+		for i, aSeries := range df.Series {
+			val := aSeries.Value(row, dataframe.DontLock)
+			if val != nil && stringify[i] {
+				val = aSeries.ValueString(row, dataframe.DontLock)
 			}
+			rec = append(rec, val)
+		}
 
-			rec := schemaStruct.New()
-			for _, aSeries := range df.Series {
-				fieldName := strings.Title(strings.ToLower(aSeries.Name()))
-
-				v := reflect.ValueOf(rec).Elem().FieldByName(fieldName)
-				if v.IsValid() {
-					val := aSeries.Value(row) // returns an interface{}
-					if val != nil {
-						switch vl := val.(type) {
-						case float64:
-							v.Set(reflect.ValueOf(&vl))
-						case int64:
-							v.Set(reflect.ValueOf(&vl))
-						case string:
-							v.Set(reflect.ValueOf(&vl))
-						case time.Time:
-							t := vl.UnixNano() / int64(time.Microsecond)
-							v.Set(reflect.ValueOf(&t))
-						default: // interface{}
-							str := aSeries.ValueString(row)
-							v.Set(reflect.ValueOf(&str))
-						}
-					}
-				}
-			}
-			if err := pw.Write(rec); err != nil {
-				return err
-			}
+		if err := pw.Write(rec); err != nil {
+			return err
 		}
 	}
+
 	if err := pw.WriteStop(); err != nil {
 		return err
 	}
@@ -159,8 +180,257 @@ func ExportToParquet(ctx context.Context, w io.Writer, df *dataframe.DataFrame,
 	return nil
 }
 
-// See: https://html.developreference.com/article/11087043/Spark+dataframe+column+naming+conventions+++restrictions
-func santizeColumnName(s string) string {
-	r := strings.NewReplacer(" ", "_", ",", "", ";", "", "{", "", "}", "", "(", "", ")", "", "=", "")
-	return strings.ToLower(r.Replace(s))
+var timeType = reflect.TypeOf(time.Time{})
+
+// parquetSeriesType returns the type of the values in a series.
+// nil is returned if the type can't be determined because the series contains no values in the range [s, e)
+// or the values have different types.
+func parquetSeriesType(aSeries dataframe.Series, s, e int) reflect.Type {
+
+	switch aSeries.(type) {
+	case *dataframe.SeriesBool:
+		return reflect.TypeOf(false)
+	case *dataframe.SeriesFloat64:
+		return reflect.TypeOf(float64(0))
+	case *dataframe.SeriesInt64:
+		return reflect.TypeOf(int64(0))
+	case *dataframe.SeriesTime:
+		return timeType
+	case *dataframe.SeriesString, *dataframe.SeriesCategorical:
+		return reflect.TypeOf("")
+	case *dataframe.SeriesGeneric, *dataframe.SeriesMixed:
+		var typ reflect.Type
+		for row := s; row < e; row++ {
+			val := aSeries.Value(row, dataframe.DontLock)
+			if val == nil {
+				continue
+			}
+
+			if typ == nil {
+				typ = reflect.TypeOf(val)
+			} else if typ != reflect.TypeOf(val) {
+				return nil
+			}
+		}
+		return typ
+	}
+
+	return nil
+}
+
+// parquetSupported reports whether typ is written as a parquet type other than a string.
+func parquetSupported(typ reflect.Type) bool {
+
+	switch typ.Kind() {
+	case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64, reflect.Struct:
+		return true
+	case reflect.Ptr:
+		return parquetSupported(typ.Elem())
+	case reflect.Slice:
+		return typ.Elem().Kind() == reflect.Uint8
+	}
+
+	return false
+}
+
+// parquetSchema is the schema of an exported parquet file.
+type parquetSchema struct {
+	elements []*parquet.SchemaElement
+	leaves   []parquetLeaf
+}
+
+// parquetLeaf is a primitive column of a parquetSchema.
+type parquetLeaf struct {
+	element int32 // index of the column's schema element
+	series  int   // index of the series that the values are sourced from
+	fields  []int // struct field indexes that lead to the value for nested columns
+	conv    func(v reflect.Value) interface{}
+}
+
+// add appends a column for typ to the schema. Struct types (excluding time.Time) are added as groups.
+// seen contains the struct types that are currently being added, so that recursive types are written as strings.
+func (ps *parquetSchema) add(name string, typ reflect.Type, series int, fields []int, seen map[reflect.Type]bool) {
+
+	elem := &parquet.SchemaElement{
+		Name:           name,
+		RepetitionType: parquet.FieldRepetitionTypePtr(parquet.FieldRepetitionType_OPTIONAL),
+	}
+	ps.elements = append(ps.elements, elem)
+
+	if typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+
+	if typ.Kind() == reflect.Struct && typ != timeType && !seen[typ] {
+		var exported []int
+		for i := 0; i < typ.NumField(); i++ {
+			if typ.Field(i).PkgPath == "" {
+				exported = append(exported, i)
+			}
+		}
+
+		if len(exported) > 0 {
+			elem.NumChildren = int32Ptr(int32(len(exported)))
+
+			seen[typ] = true
+			for _, i := range exported {
+				f := typ.Field(i)
+				ps.add(f.Name, f.Type, series, append(fields[:len(fields):len(fields)], i), seen)
+			}
+			delete(seen, typ)
+			return
+		}
+	}
+
+	ps.leaves = append(ps.leaves, parquetLeaf{
+		element: int32(len(ps.elements) - 1),
+		series:  series,
+		fields:  fields,
+		conv:    parquetPrimitive(elem, typ),
+	})
+}
+
+// marshal converts records into the tables of values used by the parquet writer.
+// Each record is a []interface{} containing a value for each series.
+func (ps *parquetSchema) marshal(src []interface{}, bgn int, end int, sh *schema.SchemaHandler) (*map[string]*layout.Table, error) {
+
+	res := make(map[string]*layout.Table, len(ps.leaves))
+
+	for _, leaf := range ps.leaves {
+		path := sh.IndexMap[leaf.element]
+
+		table := layout.NewEmptyTable()
+		table.Path = common.StrToPath(path)
+		table.MaxDefinitionLevel = int32(len(leaf.fields) + 1)
+		table.RepetitionType = parquet.FieldRepetitionType_OPTIONAL
+		table.Schema = sh.SchemaElements[leaf.element]
+		table.Info = sh.Infos[leaf.element]
+		table.Values = make([]interface{}, 0, end-bgn)
+		table.DefinitionLevels = make([]int32, 0, end-bgn)
+		table.RepetitionLevels = make([]int32, end-bgn)
+
+		for _, rec := range src[bgn:end] {
+			val, dl := leaf.value(rec.([]interface{})[leaf.series])
+			table.Values = append(table.Values, val)
+			table.DefinitionLevels = append(table.DefinitionLevels, dl)
+		}
+
+		res[path] = table
+	}
+
+	return &res, nil
+}
+
+// value returns the value of the column for a series value, along with its definition level.
+func (leaf parquetLeaf) value(val interface{}) (interface{}, int32) {
+
+	if val == nil {
+		return nil, 0
+	}
+
+	v := reflect.ValueOf(val)
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil, 0
+		}
+		v = v.Elem()
+	}
+
+	dl := int32(1)
+	for _, i := range leaf.fields {
+		v = v.Field(i)
+		if v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				return nil, dl
+			}
+			v = v.Elem()
+		}
+		dl++
+	}
+
+	return leaf.conv(v), dl
+}
+
+// parquetPrimitive sets the parquet type of a primitive column's schema element.
+// It returns a function that converts a value to the type expected by the parquet writer.
+func parquetPrimitive(elem *parquet.SchemaElement, typ reflect.Type) func(v reflect.Value) interface{} {
+
+	if typ == timeType {
+		elem.Type = parquet.TypePtr(parquet.Type_INT64)
+		elem.ConvertedType = parquet.ConvertedTypePtr(parquet.ConvertedType_TIMESTAMP_MICROS)
+		elem.LogicalType = &parquet.LogicalType{
+			TIMESTAMP: &parquet.TimestampType{
+				IsAdjustedToUTC: true,
+				Unit:            &parquet.TimeUnit{MICROS: &parquet.MicroSeconds{}},
+			},
+		}
+		return func(v reflect.Value) interface{} {
+			t := v.Interface().(time.Time)
+			return t.Unix()*1000000 + int64(t.Nanosecond()/1000)
+		}
+	}
+
+	switch typ.Kind() {
+	case reflect.Bool:
+		elem.Type = parquet.TypePtr(parquet.Type_BOOLEAN)
+		return func(v reflect.Value) interface{} { return v.Bool() }
+	case reflect.Int8, reflect.Int16, reflect.Int32:
+		elem.Type = parquet.TypePtr(parquet.Type_INT32)
+		switch typ.Kind() {
+		case reflect.Int8:
+			setParquetIntType(elem, parquet.ConvertedType_INT_8, 8, true)
+		case reflect.Int16:
+			setParquetIntType(elem, parquet.ConvertedType_INT_16, 16, true)
+		}
+		return func(v reflect.Value) interface{} { return int32(v.Int()) }
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		elem.Type = parquet.TypePtr(parquet.Type_INT32)
+		switch typ.Kind() {
+		case reflect.Uint8:
+			setParquetIntType(elem, parquet.ConvertedType_UINT_8, 8, false)
+		case reflect.Uint16:
+			setParquetIntType(elem, parquet.ConvertedType_UINT_16, 16, false)
+		default:
+			setParquetIntType(elem, parquet.ConvertedType_UINT_32, 32, false)
+		}
+		return func(v reflect.Value) interface{} { return int32(uint32(v.Uint())) }
+	case reflect.Int, reflect.Int64:
+		elem.Type = parquet.TypePtr(parquet.Type_INT64)
+		return func(v reflect.Value) interface{} { return v.Int() }
+	case reflect.Uint, reflect.Uint64:
+		elem.Type = parquet.TypePtr(parquet.Type_INT64)
+		setParquetIntType(elem, parquet.ConvertedType_UINT_64, 64, false)
+		return func(v reflect.Value) interface{} { return int64(v.Uint()) }
+	case reflect.Float32:
+		elem.Type = parquet.TypePtr(parquet.Type_FLOAT)
+		return func(v reflect.Value) interface{} { return float32(v.Float()) }
+	case reflect.Float64:
+		elem.Type = parquet.TypePtr(parquet.Type_DOUBLE)
+		return func(v reflect.Value) interface{} { return v.Float() }
+	case reflect.Slice:
+		if typ.Elem().Kind() == reflect.Uint8 {
+			elem.Type = parquet.TypePtr(parquet.Type_BYTE_ARRAY)
+			return func(v reflect.Value) interface{} { return string(v.Bytes()) }
+		}
+	}
+
+	elem.Type = parquet.TypePtr(parquet.Type_BYTE_ARRAY)
+	elem.ConvertedType = parquet.ConvertedTypePtr(parquet.ConvertedType_UTF8)
+	elem.LogicalType = &parquet.LogicalType{STRING: &parquet.StringType{}}
+
+	if typ.Kind() == reflect.String {
+		return func(v reflect.Value) interface{} { return v.String() }
+	}
+	return func(v reflect.Value) interface{} { return fmt.Sprint(v.Interface()) }
+}
+
+func setParquetIntType(elem *parquet.SchemaElement, ct parquet.ConvertedType, bitWidth int8, signed bool) {
+	elem.ConvertedType = parquet.ConvertedTypePtr(ct)
+	elem.LogicalType = &parquet.LogicalType{INTEGER: &parquet.IntType{BitWidth: bitWidth, IsSigned: signed}}
+}
+
+func int32Ptr(i int32) *int32 {
+	return &i
 }
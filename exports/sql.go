@@ -22,8 +22,69 @@ const (
 	PostgreSQL Database = 0
 	// MySQL database
 	MySQL Database = 1
+	// SQLite database
+	SQLite Database = 2
+	// SQLServer is a Microsoft SQL Server database
+	SQLServer Database = 3
+	// Oracle database. Times are exported as time.Time values rather than strings.
+	Oracle Database = 4
 )
 
+// maxPlaceholders is the maximum number of placeholders allowed in a single statement.
+var maxPlaceholders = map[Database]int{
+	PostgreSQL: 65535,
+	MySQL:      65535,
+	SQLite:     999, // SQLITE_MAX_VARIABLE_NUMBER prior to 3.32.0
+	SQLServer:  2100,
+	Oracle:     65535,
+}
+
+// maxRows is the maximum number of rows allowed in a single INSERT statement (if limited).
+var maxRows = map[Database]int{
+	SQLServer: 1000,
+}
+
+// validDatabase returns true if database is supported.
+func validDatabase(database Database) bool {
+	_, exists := maxPlaceholders[database]
+	return exists
+}
+
+// ConflictAction sets what happens to a row that conflicts with an existing row.
+type ConflictAction int
+
+const (
+	// DoNothing leaves the existing row unchanged.
+	DoNothing ConflictAction = 0
+	// DoUpdate updates the existing row (upsert).
+	DoUpdate ConflictAction = 1
+)
+
+// OnConflict is used to handle rows that conflict with existing rows due to a unique constraint.
+//
+// For PostgreSQL and SQLite, an ON CONFLICT clause is used. For MySQL, an ON DUPLICATE KEY UPDATE clause is used.
+// DoNothing is implemented for MySQL by setting the first column to itself.
+type OnConflict struct {
+
+	// Action sets what happens to a conflicting row.
+	Action ConflictAction
+
+	// Columns are the column names of the unique constraint (ie. the conflict target).
+	// It is required by PostgreSQL and SQLite for DoUpdate. MySQL ignores it since all unique constraints are checked.
+	Columns []string
+
+	// Update are the column names to update when Action is DoUpdate.
+	// If not set, all exported columns are updated except Columns and PrimaryKey.
+	Update []string
+}
+
+// CreateTable is used to create the table before the data is exported.
+type CreateTable struct {
+
+	// IfNotExists will only create the table if it does not already exist.
+	IfNotExists bool
+}
+
 type execContexter interface {
 	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
 }
@@ -45,6 +106,9 @@ type SQLExportOptions struct {
 	// It is recommended a transaction is used so if 1 batch-insert fails, then all
 	// successfully inserted data can be rolled back.
 	// If set, it must not be 0.
+	//
+	// Batches are automatically made smaller (or created when BatchSize is not set) so that the number of
+	// placeholders per statement is within the limit of the Database (eg. 999 for SQLite and 2100 for SQL Server).
 	BatchSize *uint
 
 	// SeriesToColumn is used to map the series name to the table's column name.
@@ -55,6 +119,21 @@ type SQLExportOptions struct {
 
 	// Database is used to set the Database.
 	Database Database
+
+	// OnConflict is used to handle rows that conflict with existing rows.
+	// If not set, a conflict generates an error.
+	// It is not supported for SQLServer and Oracle.
+	OnConflict *OnConflict
+
+	// CreateTable will create the table using the statement generated by CreateTableSQL.
+	// If not set, the table is assumed to already exist.
+	CreateTable *CreateTable
+
+	// Copy will export the data using a PostgreSQL COPY FROM STDIN statement (in the given format)
+	// rather than INSERT statements. It is much faster for large Dataframes.
+	// db must implement CopyFromer. BatchSize is ignored and OnConflict is not supported.
+	// See ExportToCopy for how the series are encoded. NullString is encoded as text.
+	Copy *CopyFormat
 }
 
 // PrimaryKey is used to generate custom values for the primary key
@@ -71,7 +150,9 @@ type PrimaryKey struct {
 
 // ExportToSQL exports a Dataframe to a SQL Database.
 // It is assumed to be a PostgreSQL database (for placeholder purposes), unless
-// otherwise set to MySQL using the Options.
+// otherwise set to MySQL, SQLite, SQLServer or Oracle using the Options.
+// Rows that conflict with existing rows can be updated or skipped using OnConflict.
+// For large Dataframes, PostgreSQL's COPY can be used instead of INSERT statements by setting Copy.
 //
 // Example (gist):
 //
@@ -155,11 +236,23 @@ func ExportToSQL(ctx context.Context, db execContexter, df *dataframe.DataFrame,
 			seriesToColumn = options[0].SeriesToColumn
 		}
 		database = options[0].Database
-		if database != PostgreSQL && database != MySQL {
+		if !validDatabase(database) {
 			return errors.New("invalid database")
 		}
 	}
 
+	if len(options) > 0 && options[0].CreateTable != nil {
+		stmt, err := createTableSQL(df, tableName, options[0])
+		if err != nil {
+			return err
+		}
+
+		_, err = db.ExecContext(ctx, stmt)
+		if err != nil {
+			return err
+		}
+	}
+
 	nRows := df.NRows(dataframe.DontLock)
 	if nRows == 0 {
 		return nil
@@ -194,6 +287,34 @@ func ExportToSQL(ctx context.Context, db execContexter, df *dataframe.DataFrame,
 		}
 	}
 
+	if len(columnNames) == 0 {
+		return errors.New("no columns to export")
+	}
+
+	if len(options) > 0 && options[0].Copy != nil {
+		return copyToSQL(ctx, db, df, tableName, columnNames, start, end, options[0])
+	}
+
+	// Limit the batch size so that the number of placeholders is within the limit of the database
+	limit := uint(maxPlaceholders[database] / len(columnNames))
+	if n, exists := maxRows[database]; exists && uint(n) < limit {
+		limit = uint(n)
+	}
+	if limit == 0 {
+		limit = 1
+	}
+	if batchSize == nil || *batchSize > limit {
+		batchSize = &limit
+	}
+
+	var conflict string
+	if len(options) > 0 && options[0].OnConflict != nil {
+		conflict, err = conflictClause(database, options[0].OnConflict, columnNames, pk)
+		if err != nil {
+			return err
+		}
+	}
+
 	var (
 		batchData  []interface{}
 		batchCount uint
@@ -234,7 +355,14 @@ func ExportToSQL(ctx context.Context, db execContexter, df *dataframe.DataFrame,
 				}
 			} else {
 				switch v := val.(type) {
+				case bool:
+					ival = &[]string{sqlBool(database, v)}[0]
 				case time.Time:
+					if database == Oracle {
+						// Oracle does not implicitly convert strings in this format to dates
+						batchData = append(batchData, v)
+						continue
+					}
 					ival = &[]string{v.Format("2006-01-02 15:04:05")}[0]
 				default:
 					ival = &[]string{series.ValueString(row, dataframe.DontLock)}[0]
@@ -244,9 +372,9 @@ func ExportToSQL(ctx context.Context, db execContexter, df *dataframe.DataFrame,
 			batchData = append(batchData, ival)
 		}
 
-		if batchSize != nil && batchCount == *batchSize {
+		if batchCount == *batchSize {
 			// Now insert data to table
-			err := sqlInsert(ctx, db, database, tableName, columnNames, batchData)
+			err := sqlInsert(ctx, db, database, tableName, columnNames, batchData, conflict)
 			if err != nil {
 				return err
 			}
@@ -259,7 +387,7 @@ func ExportToSQL(ctx context.Context, db execContexter, df *dataframe.DataFrame,
 
 	// Insert the remaining data into table
 	if len(batchData) > 0 {
-		err := sqlInsert(ctx, db, database, tableName, columnNames, batchData)
+		err := sqlInsert(ctx, db, database, tableName, columnNames, batchData, conflict)
 		if err != nil {
 			return err
 		}
@@ -268,13 +396,223 @@ func ExportToSQL(ctx context.Context, db execContexter, df *dataframe.DataFrame,
 	return nil
 }
 
-func sqlInsert(ctx context.Context, db execContexter, database Database, tableName string, columnNames []string, batchData []interface{}) error {
+// CreateTableSQL returns the CREATE TABLE statement used by ExportToSQL when CreateTable is set.
+// It can be used to inspect the statement without executing it (dry-run).
+//
+// The series are mapped to the following column types:
+//
+//  Series          | PostgreSQL       | MySQL        | SQLite  | SQLServer     | Oracle        |
+//  ----------------|------------------|--------------|---------|---------------|---------------|
+//  SeriesInt64     | BIGINT           | BIGINT       | INTEGER | BIGINT        | NUMBER(19)    |
+//  SeriesFloat64   | DOUBLE PRECISION | DOUBLE       | REAL    | FLOAT         | BINARY_DOUBLE |
+//  SeriesBool      | BOOLEAN          | BOOLEAN      | INTEGER | BIT           | NUMBER(1)     |
+//  SeriesTime      | TIMESTAMP        | DATETIME     | TEXT    | DATETIME2     | TIMESTAMP     |
+//  other series    | TEXT             | TEXT         | TEXT    | NVARCHAR(MAX) | CLOB          |
+//
+// IfNotExists is not supported for Oracle.
+//
+// Columns are NOT NULL if the series contains no nil values in Range (or NullString is set).
+// If PrimaryKey is set, the primary key column is an auto-incrementing integer when PrimaryKey.Value is nil.
+// Otherwise it is a string.
+func CreateTableSQL(df *dataframe.DataFrame, tableName string, options ...SQLExportOptions) (string, error) {
+
+	df.Lock()
+	defer df.Unlock()
+
+	if len(options) == 0 {
+		options = append(options, SQLExportOptions{})
+	}
+
+	return createTableSQL(df, tableName, options[0])
+}
+
+func createTableSQL(df *dataframe.DataFrame, tableName string, opts SQLExportOptions) (string, error) {
+
+	if tableName == "" {
+		return "", errors.New("invalid tableName")
+	}
+
+	database := opts.Database
+	if !validDatabase(database) {
+		return "", errors.New("invalid database")
+	}
+
+	pk := opts.PrimaryKey
+	if pk != nil && pk.PrimaryKey == "" {
+		return "", errors.New("invalid primary key name")
+	}
+
+	var start, end int
+
+	nRows := df.NRows(dataframe.DontLock)
+	if nRows > 0 {
+		var err error
+		start, end, err = opts.Range.Limits(nRows)
+		if err != nil {
+			return "", err
+		}
+	} else {
+		end = -1
+	}
+
+	columns := []string{}
+
+	if pk != nil {
+		columns = append(columns, escapeNames(database, []string{pk.PrimaryKey})[0]+" "+sqlPrimaryKeyType(database, pk))
+	}
+
+	for _, series := range df.Series {
+
+		colName, exists := opts.SeriesToColumn[series.Name(dataframe.DontLock)]
+		if exists && colName == nil {
+			// Ignore column
+			continue
+		}
+
+		name := series.Name(dataframe.DontLock)
+		if exists {
+			name = *colName
+		}
+
+		column := escapeNames(database, []string{name})[0] + " " + sqlColumnType(database, series)
+
+		notNull := opts.NullString != nil || nRows > 0
+		if opts.NullString == nil {
+			for row := start; row <= end; row++ {
+				if series.Value(row, dataframe.DontLock) == nil {
+					notNull = false
+					break
+				}
+			}
+		}
+
+		if notNull {
+			column = column + " NOT NULL"
+		}
+
+		columns = append(columns, column)
+	}
+
+	tableName = escapeNames(database, []string{tableName})[0]
+	stmt := "CREATE TABLE " + tableName + " (" + strings.Join(columns, ", ") + ")"
+
+	if opts.CreateTable != nil && opts.CreateTable.IfNotExists {
+		switch database {
+		case SQLServer:
+			stmt = "IF OBJECT_ID(N'" + strings.Replace(tableName, "'", "''", -1) + "', N'U') IS NULL " + stmt
+		case Oracle:
+			return "", errors.New("IfNotExists is not supported for Oracle")
+		default:
+			stmt = "CREATE TABLE IF NOT EXISTS " + strings.TrimPrefix(stmt, "CREATE TABLE ")
+		}
+	}
+
+	return stmt, nil
+}
+
+// sqlColumnType returns the column type used to store the values of a series.
+func sqlColumnType(database Database, series dataframe.Series) string {
+
+	switch series.(type) {
+	case *dataframe.SeriesInt64:
+		switch database {
+		case SQLite:
+			return "INTEGER"
+		case Oracle:
+			return "NUMBER(19)"
+		}
+		return "BIGINT"
+	case *dataframe.SeriesFloat64:
+		switch database {
+		case MySQL:
+			return "DOUBLE"
+		case SQLite:
+			return "REAL"
+		case SQLServer:
+			return "FLOAT"
+		case Oracle:
+			return "BINARY_DOUBLE"
+		}
+		return "DOUBLE PRECISION"
+	case *dataframe.SeriesBool:
+		switch database {
+		case SQLite:
+			return "INTEGER"
+		case SQLServer:
+			return "BIT"
+		case Oracle:
+			return "NUMBER(1)"
+		}
+		return "BOOLEAN"
+	case *dataframe.SeriesTime:
+		switch database {
+		case MySQL:
+			return "DATETIME"
+		case SQLite:
+			return "TEXT"
+		case SQLServer:
+			return "DATETIME2"
+		}
+		return "TIMESTAMP"
+	default:
+		switch database {
+		case SQLServer:
+			return "NVARCHAR(MAX)"
+		case Oracle:
+			return "CLOB"
+		}
+		return "TEXT"
+	}
+}
+
+// sqlPrimaryKeyType returns the column type of the primary key.
+func sqlPrimaryKeyType(database Database, pk *PrimaryKey) string {
+
+	if pk.Value == nil {
+		// Auto-incrementing
+		switch database {
+		case MySQL:
+			return "BIGINT AUTO_INCREMENT PRIMARY KEY"
+		case SQLite:
+			// Alias for the rowid
+			return "INTEGER PRIMARY KEY"
+		case SQLServer:
+			return "BIGINT IDENTITY(1,1) PRIMARY KEY"
+		case Oracle:
+			return "NUMBER(19) GENERATED BY DEFAULT ON NULL AS IDENTITY PRIMARY KEY"
+		}
+		return "BIGSERIAL PRIMARY KEY"
+	}
+
+	switch database {
+	case MySQL:
+		// TEXT columns can't be used as a primary key without a prefix length
+		return "VARCHAR(255) PRIMARY KEY"
+	case SQLServer:
+		return "NVARCHAR(255) PRIMARY KEY"
+	case Oracle:
+		return "VARCHAR2(255) PRIMARY KEY"
+	}
+	return "TEXT PRIMARY KEY"
+}
+
+func sqlInsert(ctx context.Context, db execContexter, database Database, tableName string, columnNames []string, batchData []interface{}, conflict string) error {
 
 	tableName = strings.Join(escapeNames(database, []string{tableName}), ",")
 	columns := strings.Join(escapeNames(database, columnNames), ",")
-	placeholders := placeholders(database, columnNames, len(batchData)/len(columnNames))
-
-	stmt := "INSERT INTO " + tableName + " (" + columns + ") VALUES " + placeholders
+	rows := len(batchData) / len(columnNames)
+
+	var stmt string
+	if database == Oracle {
+		// Oracle does not support inserting multiple rows with VALUES
+		stmt = "INSERT ALL"
+		for i := 0; i < rows; i++ {
+			stmt = stmt + " INTO " + tableName + " (" + columns + ") VALUES " + placeholders(database, columnNames, 1, i*len(columnNames))
+		}
+		stmt = stmt + " SELECT 1 FROM DUAL"
+	} else {
+		stmt = "INSERT INTO " + tableName + " (" + columns + ") VALUES " + placeholders(database, columnNames, rows, 0) + conflict
+	}
 
 	_, err := db.ExecContext(ctx, stmt, batchData...)
 	if err != nil {
@@ -284,20 +622,106 @@ func sqlInsert(ctx context.Context, db execContexter, database Database, tableNa
 	return nil
 }
 
-func placeholders(dbtype Database, fields []string, rows int) string {
+// conflictClause returns the clause appended to an INSERT statement to handle conflicting rows.
+func conflictClause(database Database, oc *OnConflict, columnNames []string, pk *PrimaryKey) (string, error) {
+
+	if database == SQLServer || database == Oracle {
+		return "", errors.New("OnConflict is not supported")
+	}
 
-	if dbtype == MySQL {
+	exported := map[string]bool{}
+	for _, col := range columnNames {
+		exported[col] = true
+	}
+
+	var update []string
+
+	if oc.Action == DoUpdate {
+		if oc.Update != nil {
+			for _, col := range oc.Update {
+				if !exported[col] {
+					return "", fmt.Errorf("update column is not exported: %s", col)
+				}
+			}
+			update = oc.Update
+		} else {
+			excluded := map[string]bool{}
+			for _, col := range oc.Columns {
+				excluded[col] = true
+			}
+			if pk != nil {
+				excluded[pk.PrimaryKey] = true
+			}
+
+			for _, col := range columnNames {
+				if !excluded[col] {
+					update = append(update, col)
+				}
+			}
+		}
+
+		if len(update) == 0 {
+			return "", errors.New("no columns to update")
+		}
+	} else if oc.Action != DoNothing {
+		return "", errors.New("invalid conflict action")
+	}
+
+	if database == MySQL {
+		if oc.Action == DoNothing {
+			// Setting a column to itself leaves the row unchanged
+			col := escapeNames(database, columnNames[:1])[0]
+			return " ON DUPLICATE KEY UPDATE " + col + "=" + col, nil
+		}
+
+		sets := []string{}
+		for _, col := range escapeNames(database, update) {
+			sets = append(sets, col+"=VALUES("+col+")")
+		}
+		return " ON DUPLICATE KEY UPDATE " + strings.Join(sets, ","), nil
+	}
+
+	clause := " ON CONFLICT"
+	if len(oc.Columns) > 0 {
+		clause = clause + " (" + strings.Join(escapeNames(database, oc.Columns), ",") + ")"
+	} else if oc.Action == DoUpdate {
+		return "", errors.New("conflict columns are required")
+	}
+
+	if oc.Action == DoNothing {
+		return clause + " DO NOTHING", nil
+	}
+
+	sets := []string{}
+	for _, col := range escapeNames(database, update) {
+		sets = append(sets, col+"=EXCLUDED."+col)
+	}
+	return clause + " DO UPDATE SET " + strings.Join(sets, ","), nil
+}
+
+// placeholders returns the placeholders for rows of values. Numbered placeholders start after offset.
+func placeholders(dbtype Database, fields []string, rows int, offset int) string {
+
+	if dbtype == MySQL || dbtype == SQLite {
 		inner := "( " + strings.TrimSuffix(strings.Repeat("?,", len(fields)), ",") + " ),"
 		return strings.TrimSuffix(strings.Repeat(inner, rows), ",")
 	}
 
+	format := "$%d,"
+	switch dbtype {
+	case SQLServer:
+		format = "@p%d,"
+	case Oracle:
+		format = ":%d,"
+	}
+
 	var singleValuesStr string
 
-	varCount := 1
+	varCount := offset + 1
 	for i := 1; i <= rows; i++ {
 		singleValuesStr = singleValuesStr + "("
 		for j := 1; j <= len(fields); j++ {
-			singleValuesStr = singleValuesStr + fmt.Sprintf("$%d,", varCount)
+			singleValuesStr = singleValuesStr + fmt.Sprintf(format, varCount)
 			varCount++
 		}
 		singleValuesStr = strings.TrimSuffix(singleValuesStr, ",") + "),"
@@ -306,6 +730,21 @@ func placeholders(dbtype Database, fields []string, rows int) string {
 	return strings.TrimSuffix(singleValuesStr, ",")
 }
 
+// sqlBool encodes a bool. Only PostgreSQL has a true boolean type so 1 and 0 are used for the others.
+func sqlBool(database Database, b bool) string {
+	if database != PostgreSQL {
+		if b {
+			return "1"
+		}
+		return "0"
+	}
+
+	if b {
+		return "true"
+	}
+	return "false"
+}
+
 func escapeNames(database Database, names []string) []string {
 	out := []string{}
 
@@ -314,10 +753,14 @@ func escapeNames(database Database, names []string) []string {
 		for _, v := range names {
 			out = append(out, fmt.Sprintf("`%s`", v))
 		}
-	case PostgreSQL:
+	case PostgreSQL, SQLite, Oracle:
 		for _, v := range names {
 			out = append(out, fmt.Sprintf("\"%s\"", v))
 		}
+	case SQLServer:
+		for _, v := range names {
+			out = append(out, "["+strings.Replace(v, "]", "]]", -1)+"]")
+		}
 	default:
 		out = names
 	}
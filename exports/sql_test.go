@@ -0,0 +1,346 @@
+package exports
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"io"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/rocketlaunchr/dataframe-go"
+	"github.com/stretchr/testify/assert"
+)
+
+var ctx = context.Background()
+
+// execRecorder records the statements that are executed.
+type execRecorder struct {
+	stmts []string
+	args  [][]interface{}
+}
+
+func (e *execRecorder) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	e.stmts = append(e.stmts, query)
+	e.args = append(e.args, args)
+	return nil, nil
+}
+
+// copyRecorder records the COPY statements that are executed and their data.
+type copyRecorder struct {
+	execRecorder
+	data []byte
+}
+
+func (c *copyRecorder) CopyFrom(ctx context.Context, r io.Reader, sql string) error {
+	c.stmts = append(c.stmts, sql)
+	data, err := ioutil.ReadAll(r)
+	c.data = data
+	return err
+}
+
+// copyFailer fails the COPY statement without reading the data.
+type copyFailer struct {
+	execRecorder
+	err error
+}
+
+func (c *copyFailer) CopyFrom(ctx context.Context, r io.Reader, sql string) error {
+	return c.err
+}
+
+func TestExportToSQLOnConflict(t *testing.T) {
+
+	df := dataframe.NewDataFrame(
+		dataframe.NewSeriesInt64("id", nil, 1, 2),
+		dataframe.NewSeriesString("name", nil, "alpha", "beta"),
+		dataframe.NewSeriesFloat64("amount", nil, 1.5, nil),
+	)
+
+	tests := []struct {
+		database   Database
+		onConflict *OnConflict
+		expected   string
+	}{
+		{
+			PostgreSQL,
+			nil,
+			`INSERT INTO "t" ("id","name","amount") VALUES ($1,$2,$3),($4,$5,$6)`,
+		},
+		{
+			PostgreSQL,
+			&OnConflict{Action: DoNothing},
+			`INSERT INTO "t" ("id","name","amount") VALUES ($1,$2,$3),($4,$5,$6) ON CONFLICT DO NOTHING`,
+		},
+		{
+			PostgreSQL,
+			&OnConflict{Action: DoUpdate, Columns: []string{"id"}},
+			`INSERT INTO "t" ("id","name","amount") VALUES ($1,$2,$3),($4,$5,$6) ON CONFLICT ("id") DO UPDATE SET "name"=EXCLUDED."name","amount"=EXCLUDED."amount"`,
+		},
+		{
+			PostgreSQL,
+			&OnConflict{Action: DoUpdate, Columns: []string{"id"}, Update: []string{"amount"}},
+			`INSERT INTO "t" ("id","name","amount") VALUES ($1,$2,$3),($4,$5,$6) ON CONFLICT ("id") DO UPDATE SET "amount"=EXCLUDED."amount"`,
+		},
+		{
+			MySQL,
+			&OnConflict{Action: DoNothing},
+			"INSERT INTO `t` (`id`,`name`,`amount`) VALUES ( ?,?,? ),( ?,?,? ) ON DUPLICATE KEY UPDATE `id`=`id`",
+		},
+		{
+			MySQL,
+			&OnConflict{Action: DoUpdate, Columns: []string{"id"}},
+			"INSERT INTO `t` (`id`,`name`,`amount`) VALUES ( ?,?,? ),( ?,?,? ) ON DUPLICATE KEY UPDATE `name`=VALUES(`name`),`amount`=VALUES(`amount`)",
+		},
+	}
+
+	for _, tt := range tests {
+		rec := &execRecorder{}
+		err := ExportToSQL(ctx, rec, df, "t", SQLExportOptions{Database: tt.database, OnConflict: tt.onConflict})
+		if err != nil {
+			t.Fatalf("sql export error: %v", err)
+		}
+
+		if assert.Len(t, rec.stmts, 1) {
+			assert.Equal(t, tt.expected, rec.stmts[0])
+			assert.Len(t, rec.args[0], 6)
+		}
+	}
+
+	// Errors
+	errOpts := []*OnConflict{
+		{Action: DoUpdate}, // PostgreSQL requires conflict columns
+		{Action: DoUpdate, Columns: []string{"id"}, Update: []string{"age"}}, // unknown column
+		{Action: DoUpdate, Columns: []string{"id", "name", "amount"}},        // nothing to update
+	}
+
+	for _, oc := range errOpts {
+		err := ExportToSQL(ctx, &execRecorder{}, df, "t", SQLExportOptions{OnConflict: oc})
+		assert.Error(t, err)
+	}
+}
+
+func TestCreateTableSQL(t *testing.T) {
+
+	df := dataframe.NewDataFrame(
+		dataframe.NewSeriesInt64("id", nil, 1, 2),
+		dataframe.NewSeriesString("name", nil, "alpha", nil),
+		dataframe.NewSeriesFloat64("amount", nil, 1.5, 2.5),
+		dataframe.NewSeriesBool("active", nil, true, false),
+		dataframe.NewSeriesTime("created", nil, nil, nil),
+		dataframe.NewSeriesString("ignored", nil, "x", "y"),
+	)
+
+	opts := SQLExportOptions{
+		SeriesToColumn: map[string]*string{
+			"name":    &[]string{"full_name"}[0],
+			"ignored": nil,
+		},
+		PrimaryKey:  &PrimaryKey{PrimaryKey: "pk"},
+		CreateTable: &CreateTable{IfNotExists: true},
+	}
+
+	stmt, err := CreateTableSQL(df, "t", opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, `CREATE TABLE IF NOT EXISTS "t" ("pk" BIGSERIAL PRIMARY KEY, "id" BIGINT NOT NULL, "full_name" TEXT, "amount" DOUBLE PRECISION NOT NULL, "active" BOOLEAN NOT NULL, "created" TIMESTAMP)`, stmt)
+
+	opts.Database = MySQL
+	opts.CreateTable = &CreateTable{}
+	opts.Range = dataframe.Range{End: &[]int{0}[0]}
+
+	stmt, err = CreateTableSQL(df, "t", opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "CREATE TABLE `t` (`pk` BIGINT AUTO_INCREMENT PRIMARY KEY, `id` BIGINT NOT NULL, `full_name` TEXT NOT NULL, `amount` DOUBLE NOT NULL, `active` BOOLEAN NOT NULL, `created` DATETIME)", stmt)
+
+	// The table is created before the data is inserted
+	rec := &execRecorder{}
+	err = ExportToSQL(ctx, rec, df, "t", opts)
+	if err != nil {
+		t.Fatalf("sql export error: %v", err)
+	}
+	if assert.Len(t, rec.stmts, 2) {
+		assert.Equal(t, stmt, rec.stmts[0])
+	}
+}
+
+func TestExportToSQLDialects(t *testing.T) {
+
+	ts := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	df := dataframe.NewDataFrame(
+		dataframe.NewSeriesInt64("id", nil, 1, 2),
+		dataframe.NewSeriesString("na]me", nil, "alpha", nil),
+		dataframe.NewSeriesBool("active", nil, true, false),
+		dataframe.NewSeriesTime("created", nil, ts, nil),
+	)
+
+	tests := []struct {
+		database Database
+		expected string
+		created  interface{}
+	}{
+		{
+			SQLite,
+			`INSERT INTO "t" ("id","na]me","active","created") VALUES ( ?,?,?,? ),( ?,?,?,? )`,
+			&[]string{"2020-01-02 03:04:05"}[0],
+		},
+		{
+			SQLServer,
+			`INSERT INTO [t] ([id],[na]]me],[active],[created]) VALUES (@p1,@p2,@p3,@p4),(@p5,@p6,@p7,@p8)`,
+			&[]string{"2020-01-02 03:04:05"}[0],
+		},
+		{
+			Oracle,
+			`INSERT ALL INTO "t" ("id","na]me","active","created") VALUES (:1,:2,:3,:4) INTO "t" ("id","na]me","active","created") VALUES (:5,:6,:7,:8) SELECT 1 FROM DUAL`,
+			ts,
+		},
+	}
+
+	for _, tt := range tests {
+		rec := &execRecorder{}
+		err := ExportToSQL(ctx, rec, df, "t", SQLExportOptions{Database: tt.database})
+		if err != nil {
+			t.Fatalf("sql export error: %v", err)
+		}
+
+		if assert.Len(t, rec.stmts, 1) {
+			assert.Equal(t, tt.expected, rec.stmts[0])
+			assert.Equal(t, &[]string{"1"}[0], rec.args[0][2])
+			assert.Equal(t, tt.created, rec.args[0][3])
+		}
+
+		// OnConflict is only supported by SQLite
+		err = ExportToSQL(ctx, &execRecorder{}, df, "t", SQLExportOptions{Database: tt.database, OnConflict: &OnConflict{Action: DoNothing}})
+		if tt.database == SQLite {
+			assert.NoError(t, err)
+		} else {
+			assert.Error(t, err)
+		}
+	}
+
+	stmt, err := CreateTableSQL(df, "t", SQLExportOptions{Database: SQLServer, CreateTable: &CreateTable{IfNotExists: true}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "IF OBJECT_ID(N'[t]', N'U') IS NULL CREATE TABLE [t] ([id] BIGINT NOT NULL, [na]]me] NVARCHAR(MAX), [active] BIT NOT NULL, [created] DATETIME2)", stmt)
+
+	_, err = CreateTableSQL(df, "t", SQLExportOptions{Database: Oracle, CreateTable: &CreateTable{IfNotExists: true}})
+	assert.Error(t, err)
+}
+
+func TestExportToSQLPlaceholderLimit(t *testing.T) {
+
+	vals := make([]interface{}, 400)
+	for i := range vals {
+		vals[i] = int64(i)
+	}
+
+	df := dataframe.NewDataFrame(
+		dataframe.NewSeriesInt64("a", nil, vals...),
+		dataframe.NewSeriesInt64("b", nil, vals...),
+		dataframe.NewSeriesInt64("c", nil, vals...),
+	)
+
+	tests := []struct {
+		database  Database
+		batchSize *uint
+		expected  []int // number of rows per statement
+	}{
+		{PostgreSQL, nil, []int{400}},
+		{PostgreSQL, &[]uint{300}[0], []int{300, 100}},
+		{SQLite, nil, []int{333, 67}},
+		{SQLite, &[]uint{100}[0], []int{100, 100, 100, 100}},
+		{SQLServer, nil, []int{400}},
+	}
+
+	for _, tt := range tests {
+		rec := &execRecorder{}
+		err := ExportToSQL(ctx, rec, df, "t", SQLExportOptions{Database: tt.database, BatchSize: tt.batchSize})
+		if err != nil {
+			t.Fatalf("sql export error: %v", err)
+		}
+
+		rows := []int{}
+		for _, args := range rec.args {
+			rows = append(rows, len(args)/3)
+		}
+		assert.Equal(t, tt.expected, rows)
+	}
+}
+
+func TestExportToSQLCopy(t *testing.T) {
+
+	ts := time.Date(2020, 1, 2, 3, 4, 5, 600000000, time.UTC)
+
+	df := dataframe.NewDataFrame(
+		dataframe.NewSeriesInt64("id", nil, 1, nil),
+		dataframe.NewSeriesString("name", nil, "a\tb", "c\\d"),
+		dataframe.NewSeriesBool("active", nil, true, false),
+		dataframe.NewSeriesTime("created", nil, ts, nil),
+		dataframe.NewSeriesFloat64("ignored", nil, 1.5, 2.5),
+	)
+
+	opts := SQLExportOptions{
+		SeriesToColumn: map[string]*string{"ignored": nil},
+		PrimaryKey:     &PrimaryKey{PrimaryKey: "pk"},
+		Copy:           &[]CopyFormat{CopyText}[0],
+	}
+
+	rec := &copyRecorder{}
+	err := ExportToSQL(ctx, rec, df, "t", opts)
+	if err != nil {
+		t.Fatalf("sql export error: %v", err)
+	}
+
+	// The auto-incrementing primary key is generated by the database
+	if assert.Len(t, rec.stmts, 1) {
+		assert.Equal(t, `COPY "t" ("id","name","active","created") FROM STDIN`, rec.stmts[0])
+	}
+	assert.Equal(t, "1\ta\\tb\tt\t2020-01-02 03:04:05.6\n\\N\tc\\\\d\tf\t\\N\n", string(rec.data))
+
+	opts.PrimaryKey.Value = func(row int, n int) *string { return &[]string{"k"}[0] }
+	opts.Copy = &[]CopyFormat{CopyBinary}[0]
+
+	rec = &copyRecorder{}
+	err = ExportToSQL(ctx, rec, df, "t", opts)
+	if err != nil {
+		t.Fatalf("sql export error: %v", err)
+	}
+
+	if assert.Len(t, rec.stmts, 1) {
+		assert.Equal(t, `COPY "t" ("pk","id","name","active","created") FROM STDIN (FORMAT binary)`, rec.stmts[0])
+	}
+
+	expected := "PGCOPY\n\377\r\n\x00" + "\x00\x00\x00\x00" + "\x00\x00\x00\x00" +
+		// row 1
+		"\x00\x05" +
+		"\x00\x00\x00\x01k" +
+		"\x00\x00\x00\x08\x00\x00\x00\x00\x00\x00\x00\x01" +
+		"\x00\x00\x00\x03a\tb" +
+		"\x00\x00\x00\x01\x01" +
+		"\x00\x00\x00\x08\x00\x02\x3e\x1e\x36\xf8\x3b\x00" +
+		// row 2
+		"\x00\x05" +
+		"\x00\x00\x00\x01k" +
+		"\xff\xff\xff\xff" +
+		"\x00\x00\x00\x03c\\d" +
+		"\x00\x00\x00\x01\x00" +
+		"\xff\xff\xff\xff" +
+		// trailer
+		"\xff\xff"
+	assert.Equal(t, []byte(expected), rec.data)
+
+	// The database's error is returned if it fails before reading the data
+	failer := &copyFailer{err: errors.New("permission denied for table t")}
+	err = ExportToSQL(ctx, failer, df, "t", opts)
+	assert.Equal(t, failer.err, err)
+
+	// db must implement CopyFromer
+	err = ExportToSQL(ctx, &execRecorder{}, df, "t", opts)
+	assert.Error(t, err)
+}
@@ -4,6 +4,7 @@ package dataframe
 
 import (
 	"context"
+	"errors"
 )
 
 // FilterAction is the return value of FilterSeriesFn and FilterDataFrameFn.
@@ -208,6 +209,7 @@ func filterDataFrame(ctx context.Context, df *DataFrame, fn FilterDataFrameFn, o
 			vals := df.Row(rowToTransfer, true, SeriesName)
 			ndf.Append(&dontLock, vals)
 		}
+		ndf.index = df.indexRows(transfer)
 		return ndf, nil
 	}
 
@@ -219,3 +221,116 @@ func filterDataFrame(ctx context.Context, df *DataFrame, fn FilterDataFrameFn, o
 
 	return nil, nil
 }
+
+// FilterMask is used to select rows in a Series or DataFrame using a boolean mask.
+// mask can be a *SeriesBool or []bool, with the same number of rows as sdf. A row is kept if its mask value is true.
+// Rows with a false or nil mask value are dropped.
+//
+// If the InPlace option is set, the function returns nil. Instead the Series or DataFrame is modified "in place".
+// Alternatively, a new Series or DataFrame is returned.
+//
+// Example:
+//
+//  mask, _ := dataframe.Gt(ctx, df.Series[0], 30)
+//  fdf, _ := dataframe.FilterMask(ctx, df, mask)
+//
+func FilterMask(ctx context.Context, sdf interface{}, mask interface{}, opts ...FilterOptions) (interface{}, error) {
+
+	if len(opts) == 0 {
+		opts = append(opts, FilterOptions{})
+	}
+
+	var (
+		rows     []int
+		maskRows int
+		err      error
+	)
+
+	switch m := mask.(type) {
+	case []bool:
+		maskRows = len(m)
+		rows, err = maskToRows(ctx, func(row int) bool { return m[row] }, maskRows, opts[0].InPlace)
+	case *SeriesBool:
+		// The mask is unlocked before sdf is locked because it may be sdf (or one of its series)
+		if !opts[0].DontLock {
+			m.lock.RLock()
+		}
+		maskRows = m.n
+		rows, err = maskToRows(ctx, func(row int) bool { return bitmapGet(m.values, row) }, maskRows, opts[0].InPlace)
+		if !opts[0].DontLock {
+			m.lock.RUnlock()
+		}
+	default:
+		panic("mask must be a *SeriesBool or []bool")
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	switch typ := sdf.(type) {
+	case Series:
+		if !opts[0].InPlace {
+			if _, ok := typ.(NewSerieser); !ok {
+				panic("s must implement NewSerieser interface if InPlace is false")
+			}
+		}
+
+		if !opts[0].DontLock {
+			typ.Lock()
+			defer typ.Unlock()
+		}
+
+		if typ.NRows(dontLock) != maskRows {
+			return nil, errors.New("mask has different number of rows than series")
+		}
+
+		if !opts[0].InPlace {
+			ns := (typ.(NewSerieser)).NewSeries(typ.Name(dontLock), &SeriesInit{Capacity: len(rows)})
+			for _, row := range rows {
+				ns.Append(typ.Value(row, dontLock), dontLock)
+			}
+			return ns, nil
+		}
+
+		for idx := len(rows) - 1; idx >= 0; idx-- {
+			typ.Remove(rows[idx], dontLock)
+		}
+		return nil, nil
+	case *DataFrame:
+		if !opts[0].DontLock {
+			typ.lock.Lock()
+			defer typ.lock.Unlock()
+		}
+
+		if typ.n != maskRows {
+			return nil, errors.New("mask has different number of rows than dataframe")
+		}
+
+		if !opts[0].InPlace {
+			return typ.take(ctx, rows)
+		}
+
+		for idx := len(rows) - 1; idx >= 0; idx-- {
+			typ.Remove(rows[idx], dontLock)
+		}
+		return nil, nil
+	default:
+		panic("sdf must be a Series or DataFrame")
+	}
+}
+
+// maskToRows returns the rows that are kept. If drop is set, the rows that are dropped are returned instead.
+func maskToRows(ctx context.Context, keep func(row int) bool, nRows int, drop bool) ([]int, error) {
+	rows := []int{}
+	for row := 0; row < nRows; row++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		if keep(row) != drop {
+			rows = append(rows, row)
+		}
+	}
+	return rows, nil
+}
@@ -0,0 +1,92 @@
+// Copyright 2018-20 PJ Engineering and Business Solutions Pty. Ltd. All rights reserved.
+
+package dataframe
+
+import (
+	"context"
+	"testing"
+)
+
+func TestFilterMask(t *testing.T) {
+	ctx := context.Background()
+
+	df := NewDataFrame(
+		NewSeriesString("name", nil, "a", "b", "c", "d"),
+		NewSeriesInt64("age", nil, 35, 28, nil, 41),
+	)
+
+	mask, _ := Gt(ctx, df.Series[1], 30)
+
+	fdf, err := FilterMask(ctx, df, mask)
+	if err != nil {
+		t.Fatalf("wrong err: expected: %v actual: %v", nil, err)
+	}
+
+	expected := NewDataFrame(
+		NewSeriesString("name", nil, "a", "d"),
+		NewSeriesInt64("age", nil, 35, 41),
+	)
+	if eq, _ := fdf.(*DataFrame).IsEqual(ctx, expected, IsEqualOptions{CheckName: true}); !eq {
+		t.Errorf("wrong val: expected: %v actual: %v", expected.Table(), fdf.(*DataFrame).Table())
+	}
+
+	// Series
+	fs, err := FilterMask(ctx, df.Series[0], []bool{false, true, true, false})
+	if err != nil {
+		t.Fatalf("wrong err: expected: %v actual: %v", nil, err)
+	}
+
+	expectedS := NewSeriesString("name", nil, "b", "c")
+	if eq, _ := fs.(Series).IsEqual(ctx, expectedS); !eq {
+		t.Errorf("wrong val: expected: %v actual: %v", expectedS, fs)
+	}
+
+	// Wrong length
+	if _, err := FilterMask(ctx, df, []bool{true}); err == nil {
+		t.Errorf("wrong err: expected: %v actual: %v", "error", err)
+	}
+
+	// InPlace
+	_, err = FilterMask(ctx, df, []bool{false, true, true, false}, FilterOptions{InPlace: true})
+	if err != nil {
+		t.Fatalf("wrong err: expected: %v actual: %v", nil, err)
+	}
+
+	expected = NewDataFrame(
+		NewSeriesString("name", nil, "b", "c"),
+		NewSeriesInt64("age", nil, 28, nil),
+	)
+	if eq, _ := df.IsEqual(ctx, expected, IsEqualOptions{CheckName: true}); !eq {
+		t.Errorf("wrong val: expected: %v actual: %v", expected.Table(), df.Table())
+	}
+
+	// Mask is the series being filtered
+	sb := NewSeriesBool("active", nil, true, nil, false, true)
+	_, err = FilterMask(ctx, sb, sb, FilterOptions{InPlace: true})
+	if err != nil {
+		t.Fatalf("wrong err: expected: %v actual: %v", nil, err)
+	}
+
+	expectedB := NewSeriesBool("active", nil, true, true)
+	if eq, _ := sb.IsEqual(ctx, expectedB); !eq {
+		t.Errorf("wrong val: expected: %v actual: %v", expectedB, sb)
+	}
+
+	// Mask is a series of the dataframe being filtered
+	mdf := NewDataFrame(
+		NewSeriesInt64("id", nil, 1, 2, 3, 4),
+		NewSeriesBool("active", nil, true, nil, false, true),
+	)
+	_, err = FilterMask(ctx, mdf, mdf.Series[1], FilterOptions{InPlace: true})
+	if err != nil {
+		t.Fatalf("wrong err: expected: %v actual: %v", nil, err)
+	}
+
+	expected = NewDataFrame(
+		NewSeriesInt64("id", nil, 1, 4),
+		NewSeriesBool("active", nil, true, true),
+	)
+	if eq, _ := mdf.IsEqual(ctx, expected, IsEqualOptions{CheckName: true}); !eq {
+		t.Errorf("wrong val: expected: %v actual: %v", expected.Table(), mdf.Table())
+	}
+}
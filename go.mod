@@ -8,6 +8,7 @@ require (
 	github.com/DzananGanic/numericalgo v0.0.0-20170804125527-2b389385baf0
 	github.com/Microsoft/go-winio v0.4.14 // indirect
 	github.com/Nvveen/Gotty v0.0.0-20120604004816-cd527374f1e5 // indirect
+	github.com/apache/arrow/go/arrow v0.0.0-20200514110756-ff7ee0602094
 	github.com/blend/go-sdk v1.1.1 // indirect
 	github.com/brianvoe/gofakeit/v4 v4.3.0
 	github.com/cenkalti/backoff v2.2.1+incompatible // indirect
@@ -21,13 +22,13 @@ require (
 	github.com/icza/gox v0.0.0-20200320174535-a6ff52ab3d90
 	github.com/jmoiron/sqlx v1.2.0 // indirect
 	github.com/olekukonko/tablewriter v0.0.4
-	github.com/ompluscator/dynamic-struct v1.2.0
 	github.com/opencontainers/image-spec v1.0.1 // indirect
 	github.com/opencontainers/runc v0.1.1 // indirect
 	github.com/ory/dockertest v3.3.5+incompatible // indirect
 	github.com/rocketlaunchr/mysql-go v1.1.3
 	github.com/sandertv/go-formula/v2 v2.0.0-alpha.7
 	github.com/sirupsen/logrus v1.6.0 // indirect
+	github.com/stretchr/testify v1.5.1
 	github.com/tealeg/xlsx/v3 v3.0.0
 	github.com/wcharczuk/go-chart v2.0.1+incompatible
 	github.com/xitongsys/parquet-go v1.5.2
@@ -0,0 +1,484 @@
+// Copyright 2018-20 PJ Engineering and Business Solutions Pty. Ltd. All rights reserved.
+
+package dataframe
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"time"
+)
+
+// AggregateFunc is used by Aggregate to determine how the values of
+// each group are combined.
+type AggregateFunc int
+
+const (
+	// AggCount returns the number of non-nil values in a group.
+	AggCount AggregateFunc = iota
+
+	// AggSum returns the sum of the non-nil values in a group.
+	AggSum
+
+	// AggMean returns the mean of the non-nil values in a group.
+	AggMean
+
+	// AggMin returns the smallest non-nil value in a group.
+	AggMin
+
+	// AggMax returns the largest non-nil value in a group.
+	AggMax
+
+	// AggFirst returns the first non-nil value in a group.
+	AggFirst
+
+	// AggLast returns the last non-nil value in a group.
+	AggLast
+
+	// AggNUnique returns the number of distinct non-nil values in a group.
+	AggNUnique
+
+	// AggStd returns the sample standard deviation of the non-nil values in a group.
+	AggStd
+
+	// AggCustom uses the Custom function provided in Aggregation.
+	AggCustom
+)
+
+// String implements the fmt.Stringer interface.
+func (f AggregateFunc) String() string {
+	switch f {
+	case AggCount:
+		return "count"
+	case AggSum:
+		return "sum"
+	case AggMean:
+		return "mean"
+	case AggMin:
+		return "min"
+	case AggMax:
+		return "max"
+	case AggFirst:
+		return "first"
+	case AggLast:
+		return "last"
+	case AggNUnique:
+		return "nunique"
+	case AggStd:
+		return "std"
+	case AggCustom:
+		return "custom"
+	default:
+		return fmt.Sprintf("AggregateFunc(%d)", int(f))
+	}
+}
+
+// AggregateFn is a custom aggregation function. vals contains the non-nil values of
+// a group in row order. The returned value is stored in a SeriesMixed.
+type AggregateFn func(ctx context.Context, vals []interface{}) (interface{}, error)
+
+// Aggregation describes an aggregation to perform on each group.
+type Aggregation struct {
+
+	// Key can be an int (position of series) or string (name of series).
+	Key interface{}
+
+	// Func is the aggregation to perform.
+	Func AggregateFunc
+
+	// Custom is required when Func is AggCustom.
+	Custom AggregateFn
+
+	// Name sets the name of the output series.
+	// If not set, it defaults to the series name with the Func appended (i.e. "sales_sum").
+	Name string
+}
+
+// GroupByOptions modifies the behavior of the GroupBy function.
+type GroupByOptions struct {
+
+	// DropNil will exclude rows where any of the keys are nil.
+	// By default, nil is treated as a valid key.
+	DropNil bool
+
+	// DontLock can be set to true if the DataFrame should not be locked.
+	// The setting also applies to Aggregate.
+	DontLock bool
+}
+
+// Groups is returned by GroupBy. It records which rows of the DataFrame belong to each group.
+// The DataFrame must not be modified until Aggregate is called.
+type Groups struct {
+	df       *DataFrame
+	keys     []int
+	rows     [][]int // rows for each group in order of first appearance
+	dontLock bool
+}
+
+// GroupBy splits the DataFrame into groups based on the values of one or more Series.
+// Each key can be an int (position of series) or string (name of series).
+// Groups are ordered by their first appearance in the DataFrame.
+//
+// Example:
+//
+//  g, _ := dataframe.GroupBy(ctx, df, []interface{}{"country"})
+//  out, _ := g.Aggregate(ctx, dataframe.Aggregation{Key: "sales", Func: dataframe.AggSum})
+//
+func GroupBy(ctx context.Context, df *DataFrame, keys []interface{}, opts ...GroupByOptions) (*Groups, error) {
+
+	if len(keys) == 0 {
+		return nil, errors.New("at least 1 key is required")
+	}
+
+	if len(opts) == 0 {
+		opts = append(opts, GroupByOptions{})
+	}
+
+	if !opts[0].DontLock {
+		df.lock.RLock()
+		defer df.lock.RUnlock()
+	}
+
+	g := &Groups{
+		df:       df,
+		dontLock: opts[0].DontLock,
+	}
+
+	for _, k := range keys {
+		col, err := df.keyToColumn(k)
+		if err != nil {
+			return nil, err
+		}
+		g.keys = append(g.keys, col)
+	}
+
+	groupIdx := map[interface{}]int{}
+
+	for row := 0; row < df.n; row++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		key, hasNil := df.rowKey(g.keys, row)
+		if hasNil && opts[0].DropNil {
+			continue
+		}
+
+		idx, exists := groupIdx[key]
+		if !exists {
+			idx = len(g.rows)
+			groupIdx[key] = idx
+			g.rows = append(g.rows, []int{})
+		}
+		g.rows[idx] = append(g.rows[idx], row)
+	}
+
+	return g, nil
+}
+
+// NGroups returns the number of groups.
+func (g *Groups) NGroups() int {
+	return len(g.rows)
+}
+
+// Rows returns the rows of the DataFrame that belong to a particular group.
+func (g *Groups) Rows(group int) []int {
+	return g.rows[group]
+}
+
+// Aggregate returns a new DataFrame with one row per group. The key Series are
+// placed first, followed by a Series for each aggregation.
+func (g *Groups) Aggregate(ctx context.Context, aggs ...Aggregation) (*DataFrame, error) {
+
+	df := g.df
+
+	if !g.dontLock {
+		df.lock.RLock()
+		defer df.lock.RUnlock()
+	}
+
+	nGroups := len(g.rows)
+	seriess := []Series{}
+	names := map[string]struct{}{}
+
+	// Key series
+	for _, col := range g.keys {
+		src := df.Series[col]
+		name := src.Name()
+
+		ns := newSeriesLike(src, name, &SeriesInit{Capacity: nGroups})
+		for _, rows := range g.rows {
+			ns.Append(src.Value(rows[0]), dontLock)
+		}
+
+		names[name] = struct{}{}
+		seriess = append(seriess, ns)
+	}
+
+	// Aggregated series
+	for _, agg := range aggs {
+		col, err := df.keyToColumn(agg.Key)
+		if err != nil {
+			return nil, err
+		}
+		src := df.Series[col]
+
+		name := agg.Name
+		if name == "" {
+			name = src.Name() + "_" + agg.Func.String()
+		}
+		if _, exists := names[name]; exists {
+			return nil, fmt.Errorf("names of series must be unique: %s", name)
+		}
+		names[name] = struct{}{}
+
+		ns, err := g.aggregate(ctx, src, name, agg)
+		if err != nil {
+			return nil, err
+		}
+		seriess = append(seriess, ns)
+	}
+
+	return NewDataFrame(seriess...), nil
+}
+
+func (g *Groups) aggregate(ctx context.Context, src Series, name string, agg Aggregation) (Series, error) {
+
+	ns, err := newAggregateSeries(src, name, &SeriesInit{Capacity: len(g.rows)}, agg)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, rows := range g.rows {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		vals := make([]interface{}, 0, len(rows))
+		for _, row := range rows {
+			if val := src.Value(row); val != nil {
+				vals = append(vals, val)
+			}
+		}
+
+		out, err := aggregateValues(ctx, src, vals, agg)
+		if err != nil {
+			return nil, err
+		}
+		ns.Append(out, dontLock)
+	}
+
+	return ns, nil
+}
+
+// newAggregateSeries creates a new initialized Series that can store the output of agg.
+func newAggregateSeries(src Series, name string, init *SeriesInit, agg Aggregation) (Series, error) {
+	switch agg.Func {
+	case AggCount, AggNUnique:
+		return NewSeriesInt64(name, init), nil
+	case AggSum:
+		if _, ok := src.(*SeriesInt64); ok {
+			return NewSeriesInt64(name, init), nil
+		}
+		return NewSeriesFloat64(name, init), nil
+	case AggMean, AggStd:
+		return NewSeriesFloat64(name, init), nil
+	case AggMin, AggMax, AggFirst, AggLast:
+		return newSeriesLike(src, name, init), nil
+	case AggCustom:
+		if agg.Custom == nil {
+			return nil, errors.New("Custom is required for AggCustom")
+		}
+		return NewSeriesMixed(name, init), nil
+	default:
+		return nil, fmt.Errorf("unknown aggregation: %v", agg.Func)
+	}
+}
+
+// aggregateValues combines the non-nil values of a group.
+func aggregateValues(ctx context.Context, src Series, vals []interface{}, agg Aggregation) (interface{}, error) {
+
+	switch agg.Func {
+	case AggCount:
+		return len(vals), nil
+	case AggNUnique:
+		unique := map[interface{}]struct{}{}
+		for _, v := range vals {
+			unique[hashKey(v)] = struct{}{}
+		}
+		return len(unique), nil
+	case AggFirst:
+		if len(vals) == 0 {
+			return nil, nil
+		}
+		return vals[0], nil
+	case AggLast:
+		if len(vals) == 0 {
+			return nil, nil
+		}
+		return vals[len(vals)-1], nil
+	case AggMin, AggMax:
+		if len(vals) == 0 {
+			return nil, nil
+		}
+		out := vals[0]
+		for _, v := range vals[1:] {
+			if agg.Func == AggMin && src.IsLessThanFunc(v, out) {
+				out = v
+			} else if agg.Func == AggMax && src.IsLessThanFunc(out, v) {
+				out = v
+			}
+		}
+		return out, nil
+	case AggCustom:
+		return agg.Custom(ctx, vals)
+	}
+
+	// Numerical aggregations
+	if len(vals) == 0 {
+		return nil, nil
+	}
+
+	if _, ok := src.(*SeriesInt64); ok && agg.Func == AggSum {
+		var sum int64
+		for _, v := range vals {
+			sum = sum + v.(int64)
+		}
+		return sum, nil
+	}
+
+	fs := make([]float64, 0, len(vals))
+	for _, v := range vals {
+		f, ok := toFloat64(v)
+		if !ok {
+			return nil, fmt.Errorf("%s: %T is not numerical", agg.Func, v)
+		}
+		fs = append(fs, f)
+	}
+
+	var sum float64
+	for _, f := range fs {
+		sum = sum + f
+	}
+
+	switch agg.Func {
+	case AggSum:
+		return sum, nil
+	case AggMean:
+		return sum / float64(len(fs)), nil
+	case AggStd:
+		if len(fs) < 2 {
+			return nil, nil
+		}
+		mean := sum / float64(len(fs))
+		var ss float64
+		for _, f := range fs {
+			ss = ss + (f-mean)*(f-mean)
+		}
+		return math.Sqrt(ss / float64(len(fs)-1)), nil
+	}
+
+	return nil, fmt.Errorf("unknown aggregation: %v", agg.Func)
+}
+
+// keyToColumn converts a key, which can be an int (position of series)
+// or string (name of series), to the position of the series.
+// It does not lock the DataFrame.
+func (df *DataFrame) keyToColumn(key interface{}) (int, error) {
+	switch k := key.(type) {
+	case int:
+		if k < 0 || k >= len(df.Series) {
+			return 0, fmt.Errorf("series index out of range: %d", k)
+		}
+		return k, nil
+	case string:
+		col, err := df.NameToColumn(k, dontLock)
+		if err != nil {
+			return 0, errors.New(err.Error() + ": " + k)
+		}
+		return col, nil
+	default:
+		return 0, fmt.Errorf("unknown key type: %T. Must be an int or string", key)
+	}
+}
+
+// rowKey returns a value that can be used as a map key representing the values
+// of the Series at positions cols for a particular row. Composite keys are built up
+// by pairing the previous key with the next value. It does not lock the DataFrame.
+func (df *DataFrame) rowKey(cols []int, row int) (key interface{}, hasNil bool) {
+	for i, col := range cols {
+		val := df.Series[col].Value(row)
+		if val == nil {
+			hasNil = true
+		}
+
+		if i == 0 {
+			key = hashKey(val)
+		} else {
+			key = [2]interface{}{key, hashKey(val)}
+		}
+	}
+	return
+}
+
+// nilKey is used in place of nil when hashing values.
+type nilKey struct{}
+
+// hashKey converts a value returned by a Series into a value that can be
+// used as a map key. Values that are considered equal by the Series'
+// IsEqualFunc are expected to produce the same key.
+func hashKey(v interface{}) interface{} {
+	switch val := v.(type) {
+	case nil:
+		return nilKey{}
+	case time.Time:
+		// Times with different locations can be equal
+		return [2]int64{val.Unix(), int64(val.Nanosecond())}
+	}
+
+	if !reflect.TypeOf(v).Comparable() {
+		return fmt.Sprintf("%T:%#v", v, v)
+	}
+	return v
+}
+
+// toFloat64 converts a numerical value to a float64.
+func toFloat64(v interface{}) (float64, bool) {
+	switch val := v.(type) {
+	case float64:
+		return val, true
+	case int64:
+		return float64(val), true
+	case int:
+		return float64(val), true
+	case int32:
+		return float64(val), true
+	case float32:
+		return float64(val), true
+	case uint64:
+		return float64(val), true
+	case bool:
+		return float64(B(val)), true
+	default:
+		return 0, false
+	}
+}
+
+// newSeriesLike creates a new initialized Series of the same type as s.
+// If s does not implement the NewSerieser interface, a SeriesMixed is returned.
+func newSeriesLike(s Series, name string, init *SeriesInit) Series {
+	switch typ := s.(type) {
+	case NewSerieser:
+		return typ.NewSeries(name, init)
+	case *SeriesGeneric:
+		ns := NewSeriesGeneric(name, typ.concreteType, init)
+		ns.isEqualFunc = typ.isEqualFunc
+		ns.isLessThanFunc = typ.isLessThanFunc
+		ns.valFormatter = typ.valFormatter
+		return ns
+	default:
+		return NewSeriesMixed(name, init)
+	}
+}
@@ -0,0 +1,67 @@
+// Copyright 2018-20 PJ Engineering and Business Solutions Pty. Ltd. All rights reserved.
+
+package dataframe
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestGroupBy(t *testing.T) {
+	ctx := context.Background()
+
+	s1 := NewSeriesString("country", nil, "AU", "US", "AU", nil, "US", "AU")
+	s2 := NewSeriesInt64("day", nil, 1, 1, 2, 2, 2, 3)
+	s3 := NewSeriesFloat64("sales", nil, 10.0, 20.0, nil, 5.0, 40.0, 30.0)
+	df := NewDataFrame(s1, s2, s3)
+
+	g, err := GroupBy(ctx, df, []interface{}{"country"})
+	if err != nil {
+		t.Fatalf("wrong err: expected: %v actual: %v", nil, err)
+	}
+
+	out, err := g.Aggregate(ctx,
+		Aggregation{Key: "sales", Func: AggCount},
+		Aggregation{Key: "sales", Func: AggSum},
+		Aggregation{Key: 2, Func: AggMean, Name: "avg"},
+		Aggregation{Key: "day", Func: AggMax},
+		Aggregation{Key: "day", Func: AggNUnique},
+		Aggregation{Key: "day", Func: AggCustom, Name: "days", Custom: func(ctx context.Context, vals []interface{}) (interface{}, error) {
+			return len(vals), nil
+		}},
+	)
+	if err != nil {
+		t.Fatalf("wrong err: expected: %v actual: %v", nil, err)
+	}
+
+	expected := `+-----+---------+-------------+-----------+---------+---------+-------------+-------+
+|     | COUNTRY | SALES COUNT | SALES SUM |   AVG   | DAY MAX | DAY NUNIQUE | DAYS  |
++-----+---------+-------------+-----------+---------+---------+-------------+-------+
+| 0:  |   AU    |      2      |    40     |   20    |    3    |      3      |   3   |
+| 1:  |   US    |      2      |    60     |   30    |    2    |      2      |   2   |
+| 2:  |   NaN   |      1      |     5     |    5    |    2    |      1      |   1   |
++-----+---------+-------------+-----------+---------+---------+-------------+-------+
+| 3X7 | STRING  |    INT64    |  FLOAT64  | FLOAT64 |  INT64  |    INT64    | MIXED |
++-----+---------+-------------+-----------+---------+---------+-------------+-------+`
+
+	if strings.TrimSpace(out.Table()) != strings.TrimSpace(expected) {
+		t.Errorf("wrong val: expected: %v actual: %v", expected, out.Table())
+	}
+
+	// Multiple keys with nil keys dropped
+	g, err = GroupBy(ctx, df, []interface{}{"country", "day"}, GroupByOptions{DropNil: true})
+	if err != nil {
+		t.Fatalf("wrong err: expected: %v actual: %v", nil, err)
+	}
+
+	if g.NGroups() != 5 {
+		t.Errorf("wrong val: expected: %v actual: %v", 5, g.NGroups())
+	}
+
+	// Duplicate output names
+	_, err = g.Aggregate(ctx, Aggregation{Key: "sales", Func: AggSum}, Aggregation{Key: "sales", Func: AggSum})
+	if err == nil {
+		t.Errorf("wrong err: expected: %v actual: %v", "error", err)
+	}
+}
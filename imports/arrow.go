@@ -0,0 +1,219 @@
+// Copyright 2018-20 PJ Engineering and Business Solutions Pty. Ltd. All rights reserved.
+
+package imports
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"time"
+
+	"github.com/apache/arrow/go/arrow"
+	"github.com/apache/arrow/go/arrow/array"
+	"github.com/apache/arrow/go/arrow/arrio"
+	"github.com/apache/arrow/go/arrow/ipc"
+	dataframe "github.com/rocketlaunchr/dataframe-go"
+)
+
+// ArrowLoadOptions is likely to change.
+type ArrowLoadOptions struct {
+
+	// ZeroCopy will use the memory of an arrow array directly where the layouts match, rather than copying the values.
+	// Currently this applies to DOUBLE columns that contain no nil values and are stored in a single record batch.
+	// The resulting SeriesFloat64 shares its Values with the arrow record.
+	ZeroCopy bool
+}
+
+// LoadFromArrow will load data from an arrow IPC stream.
+//
+// The arrow types are mapped to the following series:
+//
+//  BOOL                                          -> SeriesBool
+//  INT8, INT16, INT32, INT64, UINT8, UINT16, UINT32 -> SeriesInt64
+//  FLOAT32, FLOAT64                              -> SeriesFloat64
+//  STRING, BINARY                                -> SeriesString
+//  TIMESTAMP, DATE32, DATE64                     -> SeriesTime
+//
+func LoadFromArrow(ctx context.Context, r io.Reader, options ...ArrowLoadOptions) (*dataframe.DataFrame, error) {
+
+	ar, err := ipc.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+	defer ar.Release()
+
+	return loadFromArrow(ctx, ar.Schema(), ar, options...)
+}
+
+// LoadFromArrowFile will load data from an arrow IPC file.
+// See LoadFromArrow for how the arrow types are mapped.
+func LoadFromArrowFile(ctx context.Context, r ipc.ReadAtSeeker, options ...ArrowLoadOptions) (*dataframe.DataFrame, error) {
+
+	ar, err := ipc.NewFileReader(r)
+	if err != nil {
+		return nil, err
+	}
+	defer ar.Close()
+
+	return loadFromArrow(ctx, ar.Schema(), ar, options...)
+}
+
+func loadFromArrow(ctx context.Context, schema *arrow.Schema, ar arrio.Reader, options ...ArrowLoadOptions) (*dataframe.DataFrame, error) {
+
+	if len(options) == 0 {
+		options = append(options, ArrowLoadOptions{})
+	}
+
+	fields := schema.Fields()
+
+	seriess := make([]dataframe.Series, 0, len(fields))
+	for _, field := range fields {
+		s, err := newArrowSeries(field)
+		if err != nil {
+			return nil, err
+		}
+		seriess = append(seriess, s)
+	}
+
+	// shared records the series that share memory with an arrow record
+	shared := make([]bool, len(fields))
+
+	var batches int
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		rec, err := ar.Read()
+		if err != nil {
+			if err == io.EOF {
+				break
+			}
+			return nil, err
+		}
+		batches++
+
+		for i, col := range rec.Columns() {
+			if options[0].ZeroCopy && batches == 1 {
+				if arr, ok := col.(*array.Float64); ok && arr.NullN() == 0 && !containsNaN(arr.Float64Values()) {
+					// The series takes ownership of the record's memory
+					rec.Retain()
+					seriess[i].(*dataframe.SeriesFloat64).Values = arr.Float64Values()
+					shared[i] = true
+					continue
+				}
+			}
+
+			if shared[i] {
+				// Copy the values so that appending does not write into the arrow record's memory
+				fs := seriess[i].(*dataframe.SeriesFloat64)
+				fs.Values = append([]float64(nil), fs.Values...)
+				shared[i] = false
+			}
+
+			if err := appendArrowArray(seriess[i], col); err != nil {
+				return nil, fmt.Errorf("%v field: %s", err, fields[i].Name)
+			}
+		}
+	}
+
+	return dataframe.NewDataFrame(seriess...), nil
+}
+
+// newArrowSeries creates an empty series for an arrow field.
+func newArrowSeries(field arrow.Field) (dataframe.Series, error) {
+
+	switch field.Type.ID() {
+	case arrow.BOOL:
+		return dataframe.NewSeriesBool(field.Name, nil), nil
+	case arrow.INT8, arrow.INT16, arrow.INT32, arrow.INT64, arrow.UINT8, arrow.UINT16, arrow.UINT32:
+		return dataframe.NewSeriesInt64(field.Name, nil), nil
+	case arrow.FLOAT32, arrow.FLOAT64:
+		return dataframe.NewSeriesFloat64(field.Name, nil), nil
+	case arrow.STRING, arrow.BINARY:
+		return dataframe.NewSeriesString(field.Name, nil), nil
+	case arrow.TIMESTAMP, arrow.DATE32, arrow.DATE64:
+		return dataframe.NewSeriesTime(field.Name, nil), nil
+	default:
+		return nil, fmt.Errorf("unsupported arrow type: %s field: %s", field.Type.Name(), field.Name)
+	}
+}
+
+// appendArrowArray appends the values of an arrow array to s.
+func appendArrowArray(s dataframe.Series, arr array.Interface) error {
+
+	var val func(i int) interface{}
+
+	switch a := arr.(type) {
+	case *array.Boolean:
+		val = func(i int) interface{} { return a.Value(i) }
+	case *array.Int8:
+		val = func(i int) interface{} { return int64(a.Value(i)) }
+	case *array.Int16:
+		val = func(i int) interface{} { return int64(a.Value(i)) }
+	case *array.Int32:
+		val = func(i int) interface{} { return int64(a.Value(i)) }
+	case *array.Int64:
+		val = func(i int) interface{} { return a.Value(i) }
+	case *array.Uint8:
+		val = func(i int) interface{} { return int64(a.Value(i)) }
+	case *array.Uint16:
+		val = func(i int) interface{} { return int64(a.Value(i)) }
+	case *array.Uint32:
+		val = func(i int) interface{} { return int64(a.Value(i)) }
+	case *array.Float32:
+		val = func(i int) interface{} { return float64(a.Value(i)) }
+	case *array.Float64:
+		if a.NullN() == 0 && !containsNaN(a.Float64Values()) {
+			fs := s.(*dataframe.SeriesFloat64)
+			fs.Values = append(fs.Values, a.Float64Values()...)
+			return nil
+		}
+		val = func(i int) interface{} { return a.Value(i) }
+	case *array.String:
+		val = func(i int) interface{} { return a.Value(i) }
+	case *array.Binary:
+		val = func(i int) interface{} { return string(a.Value(i)) }
+	case *array.Timestamp:
+		var unit time.Duration
+		switch a.DataType().(*arrow.TimestampType).Unit {
+		case arrow.Second:
+			unit = time.Second
+		case arrow.Millisecond:
+			unit = time.Millisecond
+		case arrow.Microsecond:
+			unit = time.Microsecond
+		default:
+			unit = time.Nanosecond
+		}
+		val = func(i int) interface{} { return time.Unix(0, int64(a.Value(i))*int64(unit)).UTC() }
+	case *array.Date32:
+		val = func(i int) interface{} { return time.Unix(int64(a.Value(i))*24*60*60, 0).UTC() }
+	case *array.Date64:
+		val = func(i int) interface{} { return time.Unix(0, int64(a.Value(i))*int64(time.Millisecond)).UTC() }
+	default:
+		return fmt.Errorf("unsupported arrow type: %s", arr.DataType().Name())
+	}
+
+	for i := 0; i < arr.Len(); i++ {
+		if arr.IsNull(i) {
+			s.Append(nil, dataframe.DontLock)
+			continue
+		}
+		s.Append(val(i), dataframe.DontLock)
+	}
+
+	return nil
+}
+
+// containsNaN returns true if any of vals is NaN. A NaN is a nil value in a SeriesFloat64,
+// so it must be appended (rather than copied) for the series' nil count to be kept.
+func containsNaN(vals []float64) bool {
+	for _, v := range vals {
+		if math.IsNaN(v) {
+			return true
+		}
+	}
+	return false
+}
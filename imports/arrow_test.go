@@ -0,0 +1,118 @@
+package imports
+
+import (
+	"bytes"
+	"io/ioutil"
+	"math"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/apache/arrow/go/arrow"
+	"github.com/apache/arrow/go/arrow/array"
+	"github.com/apache/arrow/go/arrow/ipc"
+	"github.com/apache/arrow/go/arrow/memory"
+	"github.com/rocketlaunchr/dataframe-go"
+	"github.com/rocketlaunchr/dataframe-go/exports"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestArrow(t *testing.T) {
+
+	t1 := time.Date(2020, 1, 2, 3, 4, 5, 6, time.UTC)
+	t2 := time.Date(2019, 6, 7, 8, 9, 10, 0, time.UTC)
+
+	df := dataframe.NewDataFrame(
+		dataframe.NewSeriesString("name", nil, "alpha", nil, "gamma"),
+		dataframe.NewSeriesInt64("age", nil, 10, 20, nil),
+		dataframe.NewSeriesFloat64("amount", nil, nil, 2.5, 3.75),
+		dataframe.NewSeriesFloat64("price", nil, 1.0, 2.0, 3.0),
+		dataframe.NewSeriesBool("active", nil, true, false, nil),
+		dataframe.NewSeriesTime("created", nil, t1, nil, t2),
+	)
+
+	for _, zeroCopy := range []bool{false, true} {
+
+		// Stream format
+		var buf bytes.Buffer
+		err := exports.ExportToArrow(ctx, &buf, df, exports.ArrowExportOptions{ZeroCopy: zeroCopy})
+		if err != nil {
+			t.Fatalf("arrow export error: %v", err)
+		}
+
+		got, err := LoadFromArrow(ctx, &buf, ArrowLoadOptions{ZeroCopy: zeroCopy})
+		if err != nil {
+			t.Fatalf("arrow import error: %v", err)
+		}
+
+		eq, err := df.IsEqual(ctx, got, dataframe.IsEqualOptions{CheckName: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.True(t, eq, "expected: %v actual: %v", df, got)
+
+		// File format
+		f, err := ioutil.TempFile("", "dataframe-arrow")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer os.Remove(f.Name())
+		defer f.Close()
+
+		err = exports.ExportToArrowFile(ctx, f, df, exports.ArrowExportOptions{ZeroCopy: zeroCopy, Range: dataframe.Range{Start: &[]int{1}[0]}})
+		if err != nil {
+			t.Fatalf("arrow export error: %v", err)
+		}
+
+		got, err = LoadFromArrowFile(ctx, f, ArrowLoadOptions{ZeroCopy: zeroCopy})
+		if err != nil {
+			t.Fatalf("arrow import error: %v", err)
+		}
+
+		expected := df.Copy(dataframe.Range{Start: &[]int{1}[0]})
+		eq, err = expected.IsEqual(ctx, got, dataframe.IsEqualOptions{CheckName: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.True(t, eq, "expected: %v actual: %v", expected, got)
+	}
+}
+
+func TestArrowNaN(t *testing.T) {
+
+	b := array.NewFloat64Builder(memory.DefaultAllocator)
+	defer b.Release()
+	b.AppendValues([]float64{1.5, math.NaN(), 3.5}, nil)
+	col := b.NewArray()
+	defer col.Release()
+
+	schema := arrow.NewSchema([]arrow.Field{{Name: "price", Type: arrow.PrimitiveTypes.Float64}}, nil)
+	rec := array.NewRecord(schema, []array.Interface{col}, int64(col.Len()))
+	defer rec.Release()
+
+	for _, zeroCopy := range []bool{false, true} {
+
+		var buf bytes.Buffer
+		aw := ipc.NewWriter(&buf, ipc.WithSchema(schema))
+		if err := aw.Write(rec); err != nil {
+			t.Fatal(err)
+		}
+		aw.Close()
+
+		got, err := LoadFromArrow(ctx, &buf, ArrowLoadOptions{ZeroCopy: zeroCopy})
+		if err != nil {
+			t.Fatalf("arrow import error: %v", err)
+		}
+
+		nilCount, _ := got.Series[0].NilCount()
+		assert.Equal(t, 1, nilCount)
+		assert.True(t, got.Series[0].ContainsNil())
+
+		expected := dataframe.NewDataFrame(dataframe.NewSeriesFloat64("price", nil, 1.5, nil, 3.5))
+		eq, err := expected.IsEqual(ctx, got, dataframe.IsEqualOptions{CheckName: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.True(t, eq, "expected: %v actual: %v", expected, got)
+	}
+}
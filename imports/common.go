@@ -37,31 +37,6 @@ type Converter struct {
 	ConverterFunc GenericDataConverter
 }
 
-func parseObject(v map[string]interface{}, prefix string) map[string]interface{} {
-
-	out := map[string]interface{}{}
-
-	for k, t := range v {
-		var key string
-		if prefix == "" {
-			key = k
-		} else {
-			key = prefix + "." + k
-		}
-
-		switch v := t.(type) {
-		case map[string]interface{}:
-			for k, t := range parseObject(v, key) {
-				out[k] = t
-			}
-		default:
-			out[key] = t
-		}
-	}
-
-	return out
-}
-
 func dictateForce(row int, insertVals map[string]interface{}, name string, typ interface{}, val interface{}) error {
 	switch T := typ.(type) {
 	case float64:
@@ -0,0 +1,507 @@
+// Copyright 2018-20 PJ Engineering and Business Solutions Pty. Ltd. All rights reserved.
+
+package imports
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	dataframe "github.com/rocketlaunchr/dataframe-go"
+)
+
+// CopyFormat is the data format of a PostgreSQL COPY statement.
+type CopyFormat int
+
+const (
+	// CopyText is the tab-delimited text format. \N is interpreted as a nil value.
+	CopyText CopyFormat = 0
+	// CopyBinary is the binary format. The data types of columns that are not strings must be dictated.
+	CopyBinary CopyFormat = 1
+)
+
+// CopyToer is implemented by a PostgreSQL connection that can execute a COPY TO STDOUT statement.
+// The data is written to w.
+//
+// Example (pgx):
+//
+//  type copyToer struct{ conn *pgconn.PgConn }
+//
+//  func (c copyToer) CopyTo(ctx context.Context, w io.Writer, sql string) error {
+//  	_, err := c.conn.CopyTo(ctx, w, sql)
+//  	return err
+//  }
+//
+type CopyToer interface {
+	CopyTo(ctx context.Context, w io.Writer, sql string) error
+}
+
+// CopyLoadOptions is likely to change.
+type CopyLoadOptions struct {
+
+	// Format sets the format of the data.
+	Format CopyFormat
+
+	// Columns sets the names of the columns since they are not contained in the data.
+	// If not set, the columns are named by their (0-indexed) position.
+	Columns []string
+
+	// DictateDataType is used to inform LoadFromCopy what the true underlying data type is for a given column name.
+	// The key must be the case-sensitive column name.
+	// The value for a given key must be of the data type of the data.
+	// eg. For a string use "". For a int64 use int64(0). What is relevant is the data type and not the value itself.
+	// A bool is loaded into a SeriesInt64 with 1 for true and 0 for false.
+	// Columns that are not dictated are assumed to be strings.
+	//
+	// For CopyBinary, int64 accepts SMALLINT, INTEGER and BIGINT columns, float64 accepts REAL and DOUBLE PRECISION columns,
+	// bool accepts BOOLEAN columns and time.Time accepts DATE, TIMESTAMP and TIMESTAMPTZ columns.
+	//
+	// NOTE: A custom Series must implement NewSerieser interface and be able to interpret strings to work.
+	DictateDataType map[string]interface{}
+}
+
+// LoadFromCopy will load data in the PostgreSQL COPY format, such as the output of a "COPY table TO STDOUT" statement.
+func LoadFromCopy(ctx context.Context, r io.Reader, options ...CopyLoadOptions) (*dataframe.DataFrame, error) {
+
+	if len(options) == 0 {
+		options = append(options, CopyLoadOptions{})
+	}
+
+	dec := &copyDecoder{r: bufio.NewReader(r), opts: options[0]}
+	if err := dec.header(); err != nil {
+		return nil, err
+	}
+
+	var (
+		row int
+		df  *dataframe.DataFrame
+	)
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		fields, err := dec.next()
+		if err != nil {
+			if err == io.EOF {
+				break
+			}
+			return nil, err
+		}
+		row++
+
+		if df == nil {
+			df = dataframe.NewDataFrame(dec.newSeries(len(fields))...)
+		}
+
+		if len(fields) != len(df.Series) {
+			return nil, fmt.Errorf("expected %d columns but got %d. row: %d", len(df.Series), len(fields), row-1)
+		}
+
+		insertVals := make([]interface{}, len(fields))
+		for i, field := range fields {
+			if field == nil {
+				continue
+			}
+
+			insertVals[i], err = dec.value(dec.name(i), field, row-1)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		df.Append(&dataframe.DontLock, insertVals...)
+	}
+
+	if df == nil {
+		return nil, dataframe.ErrNoRows
+	}
+
+	return df, nil
+}
+
+// LoadFromSQLCopy will load data from a PostgreSQL database using a "COPY (query) TO STDOUT" statement.
+// It is much faster than LoadFromSQL for large results. query can also be a table name.
+//
+// Example:
+//
+//  df, err := imports.LoadFromSQLCopy(ctx, conn, "SELECT id, name FROM users", imports.CopyLoadOptions{
+//  	Format:          imports.CopyBinary,
+//  	Columns:         []string{"id", "name"},
+//  	DictateDataType: map[string]interface{}{"id": int64(0)},
+//  })
+//
+func LoadFromSQLCopy(ctx context.Context, db CopyToer, query string, options ...CopyLoadOptions) (*dataframe.DataFrame, error) {
+
+	if len(options) == 0 {
+		options = append(options, CopyLoadOptions{})
+	}
+
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, errors.New("invalid query")
+	}
+
+	if strings.ContainsAny(query, " \t\n") {
+		query = "(" + query + ")"
+	}
+
+	stmt := "COPY " + query + " TO STDOUT"
+	if options[0].Format == CopyBinary {
+		stmt = stmt + " (FORMAT binary)"
+	}
+
+	// The data is decoded while it is being received from the database
+	pr, pw := io.Pipe()
+
+	var copyErr error
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		copyErr = db.CopyTo(ctx, pw, stmt)
+		pw.CloseWithError(copyErr)
+	}()
+
+	df, err := LoadFromCopy(ctx, pr, options...)
+	pr.CloseWithError(errCopyFinished) // unblock the database if not all the data was read
+	<-done
+
+	// If the data could not be decoded, the database fails with errCopyFinished
+	if copyErr != nil && copyErr != errCopyFinished {
+		return nil, copyErr
+	}
+	return df, err
+}
+
+// errCopyFinished is returned to the database when the data is no longer being read.
+var errCopyFinished = errors.New("copy finished")
+
+// copySignature is the start of the header of the binary format.
+var copySignature = []byte("PGCOPY\n\377\r\n\x00")
+
+// postgresEpoch is the epoch of times in the binary format.
+var postgresEpoch = time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// copyDecoder decodes rows in the COPY format.
+type copyDecoder struct {
+	r    *bufio.Reader
+	opts CopyLoadOptions
+}
+
+// header reads the header of the binary format.
+func (d *copyDecoder) header() error {
+	if d.opts.Format != CopyBinary {
+		return nil
+	}
+
+	hdr := make([]byte, len(copySignature)+8)
+	if _, err := io.ReadFull(d.r, hdr); err != nil {
+		return fmt.Errorf("invalid COPY header: %v", err)
+	}
+
+	if !bytes.Equal(hdr[:len(copySignature)], copySignature) {
+		return errors.New("invalid COPY signature")
+	}
+
+	// Skip the header extension
+	extLen := int64(binary.BigEndian.Uint32(hdr[len(copySignature)+4:]))
+	if _, err := io.CopyN(ioutil.Discard, d.r, extLen); err != nil {
+		return fmt.Errorf("invalid COPY header: %v", err)
+	}
+
+	return nil
+}
+
+// next returns the fields of the next row. A nil field is a nil value.
+// io.EOF is returned when there are no more rows.
+func (d *copyDecoder) next() ([][]byte, error) {
+
+	if d.opts.Format == CopyBinary {
+		var n [2]byte
+		if _, err := io.ReadFull(d.r, n[:]); err != nil {
+			if err == io.EOF {
+				return nil, io.ErrUnexpectedEOF // trailer is missing
+			}
+			return nil, err
+		}
+
+		count := int16(binary.BigEndian.Uint16(n[:]))
+		if count == -1 {
+			// Trailer
+			return nil, io.EOF
+		} else if count < 0 {
+			return nil, fmt.Errorf("invalid COPY field count: %d", count)
+		}
+
+		fields := make([][]byte, count)
+		for i := range fields {
+			var l [4]byte
+			if _, err := io.ReadFull(d.r, l[:]); err != nil {
+				return nil, err
+			}
+
+			length := int32(binary.BigEndian.Uint32(l[:]))
+			if length == -1 {
+				continue
+			} else if length < 0 {
+				return nil, fmt.Errorf("invalid COPY field length: %d", length)
+			}
+
+			fields[i] = make([]byte, length)
+			if _, err := io.ReadFull(d.r, fields[i]); err != nil {
+				return nil, err
+			}
+		}
+
+		return fields, nil
+	}
+
+	line, err := d.r.ReadBytes('\n')
+	if err != nil {
+		if err == io.EOF && len(line) > 0 {
+			err = nil
+		} else {
+			return nil, err
+		}
+	}
+	line = bytes.TrimSuffix(bytes.TrimSuffix(line, []byte("\n")), []byte("\r"))
+
+	if string(line) == `\.` {
+		// End-of-data marker
+		return nil, io.EOF
+	}
+
+	fields := [][]byte{}
+	for _, field := range bytes.Split(line, []byte("\t")) {
+		if string(field) == `\N` {
+			fields = append(fields, nil)
+			continue
+		}
+		fields = append(fields, unescapeCopyText(field))
+	}
+
+	return fields, nil
+}
+
+// name returns the name of the column at idx.
+func (d *copyDecoder) name(idx int) string {
+	if idx < len(d.opts.Columns) {
+		return d.opts.Columns[idx]
+	}
+	return strconv.Itoa(idx)
+}
+
+// newSeries creates the (empty) series of the DataFrame.
+func (d *copyDecoder) newSeries(n int) []dataframe.Series {
+
+	seriess := make([]dataframe.Series, 0, n)
+
+	for i := 0; i < n; i++ {
+		name := d.name(i)
+
+		typ, exists := d.opts.DictateDataType[name]
+		if !exists {
+			// Default assumption is string
+			seriess = append(seriess, dataframe.NewSeriesString(name, nil))
+			continue
+		}
+
+		switch T := typ.(type) {
+		case float64:
+			seriess = append(seriess, dataframe.NewSeriesFloat64(name, nil))
+		case int64, bool:
+			seriess = append(seriess, dataframe.NewSeriesInt64(name, nil))
+		case string:
+			seriess = append(seriess, dataframe.NewSeriesString(name, nil))
+		case time.Time:
+			seriess = append(seriess, dataframe.NewSeriesTime(name, nil))
+		case dataframe.NewSerieser:
+			seriess = append(seriess, T.NewSeries(name, nil))
+		case Converter:
+			switch T.ConcreteType.(type) {
+			case time.Time:
+				seriess = append(seriess, dataframe.NewSeriesTime(name, nil))
+			default:
+				seriess = append(seriess, dataframe.NewSeriesGeneric(name, T.ConcreteType, nil))
+			}
+		default:
+			seriess = append(seriess, dataframe.NewSeriesGeneric(name, typ, nil))
+		}
+	}
+
+	return seriess
+}
+
+// value converts a field into the data type of the series named name.
+func (d *copyDecoder) value(name string, field []byte, row int) (interface{}, error) {
+
+	typ, exists := d.opts.DictateDataType[name]
+	if !exists {
+		return string(field), nil
+	}
+
+	if d.opts.Format == CopyBinary {
+		switch typ.(type) {
+		case int64:
+			switch len(field) {
+			case 2:
+				return int64(int16(binary.BigEndian.Uint16(field))), nil
+			case 4:
+				return int64(int32(binary.BigEndian.Uint32(field))), nil
+			case 8:
+				return int64(binary.BigEndian.Uint64(field)), nil
+			}
+			return nil, fmt.Errorf("can't force %d bytes to int64. row: %d field: %s", len(field), row, name)
+		case float64:
+			switch len(field) {
+			case 4:
+				return float64(math.Float32frombits(binary.BigEndian.Uint32(field))), nil
+			case 8:
+				return math.Float64frombits(binary.BigEndian.Uint64(field)), nil
+			}
+			return nil, fmt.Errorf("can't force %d bytes to float64. row: %d field: %s", len(field), row, name)
+		case bool:
+			if len(field) == 1 {
+				if field[0] != 0 {
+					return int64(1), nil
+				}
+				return int64(0), nil
+			}
+			return nil, fmt.Errorf("can't force %d bytes to bool. row: %d field: %s", len(field), row, name)
+		case time.Time:
+			switch len(field) {
+			case 4:
+				// Days since the epoch
+				return postgresEpoch.AddDate(0, 0, int(int32(binary.BigEndian.Uint32(field)))), nil
+			case 8:
+				// Microseconds since the epoch
+				micros := int64(binary.BigEndian.Uint64(field))
+				return time.Unix(postgresEpoch.Unix()+micros/1000000, (micros%1000000)*1000).UTC(), nil
+			}
+			return nil, fmt.Errorf("can't force %d bytes to time.Time. row: %d field: %s", len(field), row, name)
+		}
+	}
+
+	v := string(field)
+
+	switch T := typ.(type) {
+	case string:
+		return v, nil
+	case bool:
+		if v == "t" || v == "true" || v == "1" {
+			return int64(1), nil
+		} else if v == "f" || v == "false" || v == "0" {
+			return int64(0), nil
+		}
+		return nil, fmt.Errorf("can't force string: %s to bool. row: %d field: %s", v, row, name)
+	case int64:
+		i, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("can't force string: %s to int64. row: %d field: %s", v, row, name)
+		}
+		return i, nil
+	case float64:
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return nil, fmt.Errorf("can't force string: %s to float64. row: %d field: %s", v, row, name)
+		}
+		return f, nil
+	case time.Time:
+		for _, layout := range copyTimeLayouts {
+			if t, err := time.Parse(layout, v); err == nil {
+				return t, nil
+			}
+		}
+		return nil, fmt.Errorf("can't force string: %s to time.Time. row: %d field: %s", v, row, name)
+	case Converter:
+		cv, err := T.ConverterFunc(v)
+		if err != nil {
+			return nil, fmt.Errorf("can't force string: %s to generic data type. row: %d field: %s", v, row, name)
+		}
+		return cv, nil
+	default:
+		return v, nil
+	}
+}
+
+// copyTimeLayouts are the layouts of DATE, TIMESTAMP and TIMESTAMPTZ values (with the ISO DateStyle).
+var copyTimeLayouts = []string{
+	"2006-01-02 15:04:05Z07:00",
+	"2006-01-02 15:04:05Z07",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+}
+
+// unescapeCopyText reverses the backslash escaping of the text format.
+func unescapeCopyText(field []byte) []byte {
+
+	if bytes.IndexByte(field, '\\') == -1 {
+		return field
+	}
+
+	out := make([]byte, 0, len(field))
+	for i := 0; i < len(field); i++ {
+		c := field[i]
+		if c != '\\' || i == len(field)-1 {
+			out = append(out, c)
+			continue
+		}
+
+		i++
+		switch c = field[i]; c {
+		case 'b':
+			out = append(out, '\b')
+		case 'f':
+			out = append(out, '\f')
+		case 'n':
+			out = append(out, '\n')
+		case 'r':
+			out = append(out, '\r')
+		case 't':
+			out = append(out, '\t')
+		case 'v':
+			out = append(out, '\v')
+		case 'x':
+			// 1 or 2 hex digits
+			j := i + 1
+			for j < len(field) && j < i+3 && isHexDigit(field[j]) {
+				j++
+			}
+			if j == i+1 {
+				out = append(out, c)
+				continue
+			}
+			n, _ := strconv.ParseUint(string(field[i+1:j]), 16, 8)
+			out = append(out, byte(n))
+			i = j - 1
+		case '0', '1', '2', '3', '4', '5', '6', '7':
+			// 1 to 3 octal digits
+			j := i + 1
+			for j < len(field) && j < i+3 && field[j] >= '0' && field[j] <= '7' {
+				j++
+			}
+			n, _ := strconv.ParseUint(string(field[i:j]), 8, 16)
+			out = append(out, byte(n))
+			i = j - 1
+		default:
+			// Any other character is taken literally
+			out = append(out, c)
+		}
+	}
+
+	return out
+}
+
+func isHexDigit(c byte) bool {
+	return (c >= '0' && c <= '9') || (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F')
+}
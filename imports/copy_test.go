@@ -0,0 +1,126 @@
+package imports
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rocketlaunchr/dataframe-go"
+	"github.com/rocketlaunchr/dataframe-go/exports"
+	"github.com/stretchr/testify/assert"
+)
+
+// copyToer is a stand-in for a PostgreSQL connection.
+type copyToer struct {
+	data []byte
+	stmt string
+}
+
+func (c *copyToer) CopyTo(ctx context.Context, w io.Writer, sql string) error {
+	c.stmt = sql
+	_, err := w.Write(c.data)
+	return err
+}
+
+func TestCopy(t *testing.T) {
+
+	t1 := time.Date(2020, 1, 2, 3, 4, 5, 6000, time.UTC)
+	t2 := time.Date(1999, 6, 7, 8, 9, 10, 0, time.UTC)
+
+	df := dataframe.NewDataFrame(
+		dataframe.NewSeriesString("name", nil, "alpha", nil, "tab\there\\ and\nnewline", ""),
+		dataframe.NewSeriesInt64("age", nil, 10, -20, nil, 0),
+		dataframe.NewSeriesFloat64("amount", nil, nil, 2.5, -3.75, 0),
+		dataframe.NewSeriesInt64("active", nil, 1, 0, nil, 1),
+		dataframe.NewSeriesTime("created", nil, t1, nil, t2, t2),
+	)
+
+	opts := CopyLoadOptions{
+		Columns: []string{"name", "age", "amount", "active", "created"},
+		DictateDataType: map[string]interface{}{
+			"age":     int64(0),
+			"amount":  float64(0),
+			"active":  int64(0),
+			"created": time.Time{},
+		},
+	}
+
+	for _, format := range []exports.CopyFormat{exports.CopyText, exports.CopyBinary} {
+
+		var buf bytes.Buffer
+		err := exports.ExportToCopy(ctx, &buf, df, exports.CopyExportOptions{Format: format})
+		if err != nil {
+			t.Fatalf("copy export error: %v", err)
+		}
+
+		opts.Format = CopyFormat(format)
+
+		db := &copyToer{data: buf.Bytes()}
+		got, err := LoadFromSQLCopy(ctx, db, "SELECT * FROM t", opts)
+		if err != nil {
+			t.Fatalf("copy import error: %v", err)
+		}
+
+		eq, err := df.IsEqual(ctx, got, dataframe.IsEqualOptions{CheckName: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.True(t, eq, "expected: %v actual: %v", df, got)
+
+		if format == exports.CopyBinary {
+			assert.Equal(t, "COPY (SELECT * FROM t) TO STDOUT (FORMAT binary)", db.stmt)
+		} else {
+			assert.Equal(t, "COPY (SELECT * FROM t) TO STDOUT", db.stmt)
+		}
+	}
+
+	// Output of PostgreSQL
+	data := "1\tt\t2020-01-02 03:04:05.5+10\t\\x41\\101b\n2\tf\t2020-01-02\t\\N\n\\.\n"
+
+	got, err := LoadFromCopy(ctx, strings.NewReader(data), CopyLoadOptions{
+		DictateDataType: map[string]interface{}{
+			"0": int64(0),
+			"1": false,
+			"2": time.Time{},
+		},
+	})
+	if err != nil {
+		t.Fatalf("copy import error: %v", err)
+	}
+
+	expected := dataframe.NewDataFrame(
+		dataframe.NewSeriesInt64("0", nil, 1, 2),
+		dataframe.NewSeriesInt64("1", nil, 1, 0),
+		dataframe.NewSeriesTime("2", nil, time.Date(2020, 1, 2, 3, 4, 5, 500000000, time.FixedZone("", 10*60*60)), time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC)),
+		dataframe.NewSeriesString("3", nil, "AAb", nil),
+	)
+
+	eq, err := expected.IsEqual(ctx, got, dataframe.IsEqualOptions{CheckName: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.True(t, eq, "expected: %v actual: %v", expected, got)
+}
+
+func TestCopyErrors(t *testing.T) {
+
+	// The decoding error is returned if it fails before all the data is read
+	db := &copyToer{data: []byte("x\n" + strings.Repeat("1\n", 10000))}
+	_, err := LoadFromSQLCopy(ctx, db, "t", CopyLoadOptions{DictateDataType: map[string]interface{}{"0": int64(0)}})
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "can't force string: x to int64")
+	}
+
+	// Invalid field counts and lengths
+	header := "PGCOPY\n\377\r\n\x00" + "\x00\x00\x00\x00" + "\x00\x00\x00\x00"
+	for _, data := range []string{
+		header + "\xff\xfe",
+		header + "\x00\x01" + "\xff\xff\xff\xfe",
+	} {
+		_, err := LoadFromCopy(ctx, strings.NewReader(data), CopyLoadOptions{Format: CopyBinary})
+		assert.Error(t, err)
+	}
+}
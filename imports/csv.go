@@ -46,8 +46,11 @@ type CSVLoadOptions struct {
 	// The key must be the case-sensitive field name.
 	// The value for a given key must be of the data type of the data.
 	// eg. For a string use "". For a int64 use int64(0). What is relevant is the data type and not the value itself.
+	// A bool is loaded into a SeriesInt64 with 1 for true and 0 for false.
 	//
 	// NOTE: A custom Series must implement NewSerieser interface and be able to interpret strings to work.
+	// For example, a SeriesCategorical can be used for columns with a small number of distinct values.
+	// The categories and ordering of the provided SeriesCategorical are retained.
 	DictateDataType map[string]interface{}
 
 	// NilValue allows you to set what string value in the CSV file should be interpreted as a nil value for
@@ -72,25 +75,40 @@ type CSVLoadOptions struct {
 
 // LoadFromCSV will load data from a csv file.
 func LoadFromCSV(ctx context.Context, r io.ReadSeeker, options ...CSVLoadOptions) (*dataframe.DataFrame, error) {
+	df, _, err := loadFromCSV(ctx, r, nil, options...)
+	return df, err
+}
+
+// LazyCSV returns a LazyFrame that loads data from a csv file when collected.
+// Only the columns required by the LazyFrame are loaded.
+// Unless InferDataTypes is set, rows are filtered and limited while the file is being read.
+func LazyCSV(r io.ReadSeeker, options ...CSVLoadOptions) *dataframe.LazyFrame {
+	return dataframe.NewLazyFrame(&csvSource{r, options})
+}
+
+type csvSource struct {
+	r       io.ReadSeeker
+	options []CSVLoadOptions
+}
+
+func (src *csvSource) Scan(ctx context.Context, hints dataframe.ScanHints) (*dataframe.DataFrame, bool, error) {
+	return loadFromCSV(ctx, src.r, &hints, src.options...)
+}
+
+// loadFromCSV loads data from a csv file. If hints is not nil, columns that are not required are skipped.
+// If the data types are not inferred, the Predicate and Limit hints are also applied and applied is true.
+func loadFromCSV(ctx context.Context, r io.ReadSeeker, hints *dataframe.ScanHints, options ...CSVLoadOptions) (_ *dataframe.DataFrame, applied bool, _ error) {
 
 	var init *dataframe.SeriesInit
 
-	cr := csv.NewReader(r)
-	cr.ReuseRecord = true
+	cr := newCSVReader(r, options...)
 	if len(options) > 0 {
-		cr.Comma = options[0].Comma
-		if cr.Comma == 0 {
-			cr.Comma = ','
-		}
-		cr.Comment = options[0].Comment
-		cr.TrimLeadingSpace = options[0].TrimLeadingSpace
-
 		// Count how many rows we have in order to preallocate underlying slices
 		if options[0].LargeDataSet {
 			init = &dataframe.SeriesInit{}
 			for {
 				if err := ctx.Err(); err != nil {
-					return nil, err
+					return nil, false, err
 				}
 
 				_, err := cr.Read()
@@ -99,7 +117,7 @@ func LoadFromCSV(ctx context.Context, r io.ReadSeeker, options ...CSVLoadOptions
 						r.Seek(0, io.SeekStart)
 						break
 					}
-					return nil, err
+					return nil, false, err
 				}
 				init.Capacity++
 			}
@@ -109,24 +127,22 @@ func LoadFromCSV(ctx context.Context, r io.ReadSeeker, options ...CSVLoadOptions
 		}
 	}
 
-	var row int
-	var df *dataframe.DataFrame
+	var (
+		row int
+		df  *dataframe.DataFrame
+		l   = newCSVLoader(hints, options...)
+	)
 
-	// key: new column name
-	// value: indices of the columns that are going to be merged
-	mergingColumn := map[string][]int{}
-	// this is used to guarantee the order of the merged columns
-	var mergedColumns []string
-
-	// check for custom time format
-	timeFormat := time.RFC3339
-	if len(options) > 0 && options[0].TimeFormat != "" {
-		timeFormat = options[0].TimeFormat
-	}
+	// Rows can only be filtered while reading if the values have their final data type
+	applied = hints == nil || !l.opts.InferDataTypes
 
 	for {
 		if err := ctx.Err(); err != nil {
-			return nil, err
+			return nil, false, err
+		}
+
+		if applied && hints != nil && hints.Limit != nil && df != nil && df.NRows(dataframe.DontLock) >= *hints.Limit {
+			break
 		}
 
 		rec, err := cr.Read()
@@ -134,259 +150,370 @@ func LoadFromCSV(ctx context.Context, r io.ReadSeeker, options ...CSVLoadOptions
 			if err == io.EOF {
 				break
 			}
-			return nil, err
+			return nil, false, err
 		}
 
-		if row == 0 {
+		if df == nil {
 			// First row contains headings
+			if err := l.parseHeader(rec); err != nil {
+				return nil, false, err
+			}
 
-			seriess := []dataframe.Series{}
+			// Create the dataframe
+			df = dataframe.NewDataFrame(l.newSeries(init)...)
+			continue
+		}
 
-			// check to see if the column is going to be merged
-			isToBeMerged := func(clmIndex int, name string) bool {
-				if clmIndex < 0 {
-					return false
-				}
-				if len(options) > 0 && len(options[0].MergeColumns) > 0 {
-					for merged, columnsToMerge := range options[0].MergeColumns {
-						if _, found := mergingColumn[merged]; !found {
-							mergingColumn[merged] = make([]int, len(columnsToMerge))
-							mergedColumns = append(mergedColumns, merged)
-							for i := range mergingColumn[merged] {
-								// assign default value for later verification
-								mergingColumn[merged][i] = -1
-							}
-						}
-						for orderIndex, column := range columnsToMerge {
-							if column == name {
-								// insert the column index in the order of configuration
-								mergingColumn[merged][orderIndex] = clmIndex
-								return true
-							}
-						}
-					}
-				}
-				return false
+		insertVals, err := l.record(rec, row)
+		if err != nil {
+			return nil, false, err
+		}
+		row++
+
+		if applied && hints != nil && hints.Predicate != nil {
+			vals := map[interface{}]interface{}{}
+			for i, name := range l.names {
+				vals[name] = insertVals[i]
 			}
 
-			prepareSeries := func(index int, name string) {
+			keep, err := hints.Predicate(vals)
+			if err != nil {
+				return nil, false, &dataframe.RowError{Row: row - 1, Err: err}
+			}
+			if !keep {
+				continue
+			}
+		}
 
-				if isToBeMerged(index, name) {
-					return
-				}
+		df.Append(&dataframe.DontLock, insertVals...)
+	}
 
-				// Check if the datatype is dictated
-				if len(options) > 0 && len(options[0].DictateDataType) > 0 {
-					typ, exists := options[0].DictateDataType[name]
-					if !exists {
-						goto INFER1
-					}
+	if df == nil {
+		return nil, false, dataframe.ErrNoRows
+	}
 
-					switch T := typ.(type) {
-					case float64:
-						seriess = append(seriess, dataframe.NewSeriesFloat64(name, init))
-					case int64, bool:
-						seriess = append(seriess, dataframe.NewSeriesInt64(name, init))
-					case string:
-						seriess = append(seriess, dataframe.NewSeriesString(name, init))
-					case time.Time:
-						seriess = append(seriess, dataframe.NewSeriesTime(name, init))
-					case dataframe.NewSerieser:
-						seriess = append(seriess, T.NewSeries(name, init))
-					case Converter:
-						switch T.ConcreteType.(type) {
-						case time.Time:
-							seriess = append(seriess, dataframe.NewSeriesTime(name, init))
-						default:
-							seriess = append(seriess, dataframe.NewSeriesGeneric(name, T.ConcreteType, init))
-						}
-					default:
-						seriess = append(seriess, dataframe.NewSeriesGeneric(name, typ, init))
-					}
+	// Convert inferred series to actual series
+	if err := l.finish(df); err != nil {
+		return nil, false, err
+	}
 
-					return
-				}
+	return df, applied, nil
+}
+
+func newCSVReader(r io.Reader, options ...CSVLoadOptions) *csv.Reader {
+	cr := csv.NewReader(r)
+	cr.ReuseRecord = true
+	if len(options) > 0 {
+		cr.Comma = options[0].Comma
+		if cr.Comma == 0 {
+			cr.Comma = ','
+		}
+		cr.Comment = options[0].Comment
+		cr.TrimLeadingSpace = options[0].TrimLeadingSpace
+	}
+	return cr
+}
 
-			INFER1:
+// csvLoader converts the records of a csv file into the values of a DataFrame's rows.
+type csvLoader struct {
+	opts       CSVLoadOptions
+	hints      *dataframe.ScanHints
+	timeFormat string
 
-				if len(options) > 0 && options[0].InferDataTypes {
-					var knownSize *int
-					if init != nil {
-						knownSize = &init.Capacity
-					}
-					is := newInferSeries(name, knownSize)
-					seriess = append(seriess, is)
-				} else {
-					// Default assumption is string
-					seriess = append(seriess, dataframe.NewSeriesString(name, init))
-				}
-			}
+	// names of the series in the DataFrame
+	names []string
 
-			// Create the series
-			for index, name := range rec {
-				prepareSeries(index, name)
-			}
+	// key: new column name
+	// value: indices of the columns that are going to be merged
+	mergingColumn map[string][]int
+	// this is used to guarantee the order of the merged columns
+	mergedColumns []string
 
-			for _, column := range mergedColumns {
-				// Create the merged columns
-				prepareSeries(-1, column)
-			}
+	// skipColumns records the columns that are not required
+	skipColumns map[int]bool
 
-			for mergedColumn, indices := range mergingColumn {
-				for _, index := range indices {
-					if index < 0 {
-						return nil, fmt.Errorf("some of the columns don't exist for merging into column %s", mergedColumn)
-					}
-				}
-			}
+	// inferred records the series types that were inferred, so that subsequent
+	// DataFrames created by newSeries have the same types.
+	inferred []dataframe.Series
+}
 
-			// Create the dataframe
-			df = dataframe.NewDataFrame(seriess...)
-		} else {
+func newCSVLoader(hints *dataframe.ScanHints, options ...CSVLoadOptions) *csvLoader {
 
-			insertVals := []interface{}{}
-			// key: merged column name
-			// value: merged values
-			stringsToMerge := map[string][]string{}
+	l := &csvLoader{
+		hints:         hints,
+		timeFormat:    time.RFC3339,
+		mergingColumn: map[string][]int{},
+		skipColumns:   map[int]bool{},
+	}
 
-			isToBeMerged := func(index int, value string) bool {
-				itIs := false
-				for _, merged := range mergedColumns {
-					if _, found := stringsToMerge[merged]; !found {
-						stringsToMerge[merged] = make([]string, len(mergingColumn[merged]))
-					}
-					for order, i := range mergingColumn[merged] {
-						if i == index {
-							// insert the value in the order of configuration
-							stringsToMerge[merged][order] = value
-							itIs = true
-						}
-					}
+	if len(options) > 0 {
+		l.opts = options[0]
+
+		// check for custom time format
+		if options[0].TimeFormat != "" {
+			l.timeFormat = options[0].TimeFormat
+		}
+	}
+
+	return l
+}
+
+// parseHeader determines the series of the DataFrame from the headings.
+func (l *csvLoader) parseHeader(rec []string) error {
+
+	for merged, columnsToMerge := range l.opts.MergeColumns {
+		l.mergingColumn[merged] = make([]int, len(columnsToMerge))
+		l.mergedColumns = append(l.mergedColumns, merged)
+		for i := range l.mergingColumn[merged] {
+			// assign default value for later verification
+			l.mergingColumn[merged][i] = -1
+		}
+	}
+
+	// check to see if the column is going to be merged
+	isToBeMerged := func(clmIndex int, name string) bool {
+		for _, merged := range l.mergedColumns {
+			for orderIndex, column := range l.opts.MergeColumns[merged] {
+				if column == name {
+					// insert the column index in the order of configuration
+					l.mergingColumn[merged][orderIndex] = clmIndex
+					return true
 				}
-				return itIs
 			}
+		}
+		return false
+	}
 
-			columnIndex := -1
-			processValue := func(index int, v string) error {
-				if isToBeMerged(index, v) {
-					return nil
-				}
-				columnIndex++
+	for index, name := range rec {
+		if isToBeMerged(index, name) {
+			continue
+		}
 
-				// Check if v represents a nil value
-				if len(options) > 0 && options[0].NilValue != nil {
-					if v == *options[0].NilValue {
-						insertVals = append(insertVals, nil)
-						return nil
-					}
-				}
+		if l.hints != nil && !l.hints.NeedsColumn(name) {
+			l.skipColumns[index] = true
+			continue
+		}
 
-				// Check if the datatype is dictated
-				if len(options) > 0 && len(options[0].DictateDataType) > 0 {
+		l.names = append(l.names, name)
+	}
 
-					name := df.Names(dataframe.DontLock)[columnIndex]
+	// Create the merged columns
+	l.names = append(l.names, l.mergedColumns...)
 
-					// Check if a datatype is dictated
-					typ, exists := options[0].DictateDataType[name]
-					if !exists {
-						goto INFER2
-					}
+	for mergedColumn, indices := range l.mergingColumn {
+		for _, index := range indices {
+			if index < 0 {
+				return fmt.Errorf("some of the columns don't exist for merging into column %s", mergedColumn)
+			}
+		}
+	}
 
-					switch T := typ.(type) {
-					case string:
-						insertVals = append(insertVals, v)
-					case bool:
-						if v == "TRUE" || v == "true" || v == "True" || v == "1" {
-							insertVals = append(insertVals, int64(1))
-						} else if v == "FALSE" || v == "false" || v == "False" || v == "0" {
-							insertVals = append(insertVals, int64(0))
-						} else {
-							return fmt.Errorf("can't force string: %s to bool. row: %d field: %s", v, row-1, name)
-						}
-					case int64:
-						i, err := strconv.ParseInt(v, 10, 64)
-						if err != nil {
-							return fmt.Errorf("can't force string: %s to int64. row: %d field: %s", v, row-1, name)
-						}
-						insertVals = append(insertVals, i)
-					case float64:
-						f, err := strconv.ParseFloat(v, 64)
-						if err != nil {
-							return fmt.Errorf("can't force string: %s to float64. row: %d field: %s", v, row-1, name)
-						}
-						insertVals = append(insertVals, f)
-					case time.Time:
-						t, err := time.Parse(timeFormat, v)
-						if err != nil {
-							// Assume unix timestamp
-							sec, err := strconv.ParseInt(v, 10, 64)
-							if err != nil {
-								return fmt.Errorf("can't force string: %s to time.Time (%s). row: %d field: %s", v, time.RFC3339, row-1, name)
-							}
-							insertVals = append(insertVals, time.Unix(sec, 0))
-						} else {
-							insertVals = append(insertVals, t)
-						}
-					case dataframe.NewSerieser:
-						insertVals = append(insertVals, v)
-					case Converter:
-						cv, err := T.ConverterFunc(v)
-						if err != nil {
-							return fmt.Errorf("can't force string: %s to generic data type. row: %d field: %s", v, row-1, name)
-						}
-						insertVals = append(insertVals, cv)
-					default:
-						insertVals = append(insertVals, v)
-					}
+	return nil
+}
 
-					return nil
+// newSeries creates the (empty) series of the DataFrame.
+func (l *csvLoader) newSeries(init *dataframe.SeriesInit) []dataframe.Series {
+
+	seriess := []dataframe.Series{}
+
+	for idx, name := range l.names {
+
+		// Check if the datatype is dictated
+		if typ, exists := l.opts.DictateDataType[name]; exists {
+
+			switch T := typ.(type) {
+			case float64:
+				seriess = append(seriess, dataframe.NewSeriesFloat64(name, init))
+			case int64, bool:
+				seriess = append(seriess, dataframe.NewSeriesInt64(name, init))
+			case string:
+				seriess = append(seriess, dataframe.NewSeriesString(name, init))
+			case time.Time:
+				seriess = append(seriess, dataframe.NewSeriesTime(name, init))
+			case dataframe.NewSerieser:
+				seriess = append(seriess, T.NewSeries(name, init))
+			case Converter:
+				switch T.ConcreteType.(type) {
+				case time.Time:
+					seriess = append(seriess, dataframe.NewSeriesTime(name, init))
+				default:
+					seriess = append(seriess, dataframe.NewSeriesGeneric(name, T.ConcreteType, init))
 				}
+			default:
+				seriess = append(seriess, dataframe.NewSeriesGeneric(name, typ, init))
+			}
 
-			INFER2:
+			continue
+		}
 
-				// Datatype is either inferred or assumed to be a string
-				insertVals = append(insertVals, v)
-				return nil
+		if l.opts.InferDataTypes {
+			if l.inferred != nil {
+				// Only accept values of the type that was previously inferred
+				seriess = append(seriess, newInferSeriesFrom(l.inferred[idx], init))
+				continue
 			}
 
-			for idx, v := range rec {
-				err := processValue(idx, v)
-				if err != nil {
-					return nil, err
-				}
+			var knownSize *int
+			if init != nil {
+				knownSize = &init.Capacity
 			}
+			seriess = append(seriess, newInferSeries(name, knownSize))
+		} else {
+			// Default assumption is string
+			seriess = append(seriess, dataframe.NewSeriesString(name, init))
+		}
+	}
 
-			// append merged column values
-			for _, column := range mergedColumns {
-				err := processValue(-1, strings.Join(stringsToMerge[column], " "))
-				if err != nil {
-					return nil, err
+	return seriess
+}
+
+// record converts rec into the values to insert into the DataFrame. row is used for error messages.
+func (l *csvLoader) record(rec []string, row int) ([]interface{}, error) {
+
+	insertVals := make([]interface{}, 0, len(l.names))
+
+	// key: merged column name
+	// value: merged values
+	stringsToMerge := map[string][]string{}
+
+	isToBeMerged := func(index int, value string) bool {
+		itIs := false
+		for _, merged := range l.mergedColumns {
+			if _, found := stringsToMerge[merged]; !found {
+				stringsToMerge[merged] = make([]string, len(l.mergingColumn[merged]))
+			}
+			for order, i := range l.mergingColumn[merged] {
+				if i == index {
+					// insert the value in the order of configuration
+					stringsToMerge[merged][order] = value
+					itIs = true
 				}
 			}
+		}
+		return itIs
+	}
 
-			df.Append(&dataframe.DontLock, insertVals...)
+	processValue := func(v string) error {
+		name := l.names[len(insertVals)]
+
+		val, err := l.value(name, v, row)
+		if err != nil {
+			return err
 		}
-		row++
+		insertVals = append(insertVals, val)
+		return nil
 	}
 
-	if df == nil {
-		return nil, dataframe.ErrNoRows
+	for idx, v := range rec {
+		if isToBeMerged(idx, v) || l.skipColumns[idx] {
+			continue
+		}
+
+		if err := processValue(v); err != nil {
+			return nil, err
+		}
 	}
 
-	// Convert inferred series to actual series
-	if len(options) > 0 && options[0].InferDataTypes {
-		for idx := len(df.Series) - 1; idx >= 0; idx-- {
-			s := df.Series[idx]
+	// append merged column values
+	for _, column := range l.mergedColumns {
+		if err := processValue(strings.Join(stringsToMerge[column], " ")); err != nil {
+			return nil, err
+		}
+	}
 
-			is, ok := s.(*inferSeries)
-			if !ok {
-				continue
+	return insertVals, nil
+}
+
+// value converts v into the data type of the series named name.
+func (l *csvLoader) value(name string, v string, row int) (interface{}, error) {
+
+	// Check if v represents a nil value
+	if l.opts.NilValue != nil && v == *l.opts.NilValue {
+		return nil, nil
+	}
+
+	// Check if a datatype is dictated
+	typ, exists := l.opts.DictateDataType[name]
+	if !exists {
+		// Datatype is either inferred or assumed to be a string
+		return v, nil
+	}
+
+	switch T := typ.(type) {
+	case string:
+		return v, nil
+	case bool:
+		if v == "TRUE" || v == "true" || v == "True" || v == "1" {
+			return int64(1), nil
+		} else if v == "FALSE" || v == "false" || v == "False" || v == "0" {
+			return int64(0), nil
+		}
+		return nil, fmt.Errorf("can't force string: %s to bool. row: %d field: %s", v, row, name)
+	case int64:
+		i, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("can't force string: %s to int64. row: %d field: %s", v, row, name)
+		}
+		return i, nil
+	case float64:
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return nil, fmt.Errorf("can't force string: %s to float64. row: %d field: %s", v, row, name)
+		}
+		return f, nil
+	case time.Time:
+		t, err := time.Parse(l.timeFormat, v)
+		if err != nil {
+			// Assume unix timestamp
+			sec, err := strconv.ParseInt(v, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("can't force string: %s to time.Time (%s). row: %d field: %s", v, time.RFC3339, row, name)
 			}
+			return time.Unix(sec, 0), nil
+		}
+		return t, nil
+	case dataframe.NewSerieser:
+		return v, nil
+	case Converter:
+		cv, err := T.ConverterFunc(v)
+		if err != nil {
+			return nil, fmt.Errorf("can't force string: %s to generic data type. row: %d field: %s", v, row, name)
+		}
+		return cv, nil
+	default:
+		return v, nil
+	}
+}
+
+// finish converts inferred series to actual series.
+func (l *csvLoader) finish(df *dataframe.DataFrame) error {
+
+	if !l.opts.InferDataTypes {
+		return nil
+	}
+
+	inferred := make([]dataframe.Series, len(df.Series))
 
-			ns, _ := is.inferred()
-			df.Series[idx] = ns
+	for idx := len(df.Series) - 1; idx >= 0; idx-- {
+		s := df.Series[idx]
+		inferred[idx] = s
+
+		is, ok := s.(*inferSeries)
+		if !ok {
+			continue
+		}
+
+		ns, _ := is.inferred()
+		if ns == nil {
+			return fmt.Errorf("values of field %s do not match inferred type: %s", l.names[idx], l.inferred[idx].Type())
 		}
+		df.Series[idx] = ns
+		inferred[idx] = ns
+	}
+
+	if l.inferred == nil {
+		l.inferred = inferred
 	}
 
-	return df, nil
+	return nil
 }
@@ -0,0 +1,124 @@
+// Copyright 2018-20 PJ Engineering and Business Solutions Pty. Ltd. All rights reserved.
+
+package imports
+
+import (
+	"context"
+	"encoding/csv"
+	"io"
+
+	dataframe "github.com/rocketlaunchr/dataframe-go"
+)
+
+// CSVChunkReader reads a csv file in chunks so that large files can be processed with bounded memory.
+// Each chunk is returned as a DataFrame with the same series names and types.
+//
+// If InferDataTypes is set, the data types are inferred from the first chunk. Subsequent chunks must contain values
+// of the same types, otherwise an error is returned. Use DictateDataType for fields where the first chunk is not representative.
+// LargeDataSet is ignored.
+//
+// Example:
+//
+//  cr := imports.NewCSVChunkReader(f, 10000, imports.CSVLoadOptions{InferDataTypes: true})
+//  for {
+//     df, err := cr.Next(ctx)
+//     if err == io.EOF {
+//        break
+//     }
+//     if err != nil {
+//        return err
+//     }
+//     // process df
+//  }
+//
+type CSVChunkReader struct {
+	cr        *csv.Reader
+	l         *csvLoader
+	chunkSize int
+	row       int
+	header    bool
+	done      bool
+}
+
+// NewCSVChunkReader creates a CSVChunkReader that returns chunks of (at most) chunkSize rows.
+func NewCSVChunkReader(r io.Reader, chunkSize int, options ...CSVLoadOptions) *CSVChunkReader {
+	if chunkSize <= 0 {
+		panic("chunkSize must be greater than 0")
+	}
+
+	return &CSVChunkReader{
+		cr:        newCSVReader(r, options...),
+		l:         newCSVLoader(nil, options...),
+		chunkSize: chunkSize,
+	}
+}
+
+// Names returns the names of the series in each chunk. It is nil until Next is called.
+func (r *CSVChunkReader) Names() []string {
+	return r.l.names
+}
+
+// Next returns the next chunk of rows. io.EOF is returned when there are no more rows.
+// If the file contains no headings, dataframe.ErrNoRows is returned.
+func (r *CSVChunkReader) Next(ctx context.Context) (*dataframe.DataFrame, error) {
+
+	if r.done {
+		return nil, io.EOF
+	}
+
+	if !r.header {
+		rec, err := r.cr.Read()
+		if err != nil {
+			r.done = true
+			if err == io.EOF {
+				return nil, dataframe.ErrNoRows
+			}
+			return nil, err
+		}
+
+		// First row contains headings
+		if err := r.l.parseHeader(rec); err != nil {
+			r.done = true
+			return nil, err
+		}
+		r.header = true
+	}
+
+	df := dataframe.NewDataFrame(r.l.newSeries(&dataframe.SeriesInit{Capacity: r.chunkSize})...)
+
+	for df.NRows(dataframe.DontLock) < r.chunkSize {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		rec, err := r.cr.Read()
+		if err != nil {
+			if err == io.EOF {
+				r.done = true
+				break
+			}
+			return nil, err
+		}
+
+		insertVals, err := r.l.record(rec, r.row)
+		if err != nil {
+			return nil, err
+		}
+		r.row++
+
+		df.Append(&dataframe.DontLock, insertVals...)
+	}
+
+	if df.NRows(dataframe.DontLock) == 0 {
+		r.done = true
+		return nil, io.EOF
+	}
+
+	// Convert inferred series to actual series
+	if err := r.l.finish(df); err != nil {
+		r.done = true
+		return nil, err
+	}
+
+	return df, nil
+}
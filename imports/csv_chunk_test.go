@@ -0,0 +1,71 @@
+package imports
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/rocketlaunchr/dataframe-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCSVChunkReader(t *testing.T) {
+	csvStr := `Country,Age,Amount,Active
+"United States",50,112.1,true
+"United Kingdom",NA,18.2,false
+"United States",32,321.31,true
+Spain,66,555.42,false
+"United States",40,1.5,NA
+`
+
+	opts := CSVLoadOptions{
+		InferDataTypes: true,
+		NilValue:       &[]string{"NA"}[0],
+	}
+
+	cr := NewCSVChunkReader(strings.NewReader(csvStr), 2, opts)
+
+	expected := []*dataframe.DataFrame{
+		dataframe.NewDataFrame(
+			dataframe.NewSeriesString("Country", nil, "United States", "United Kingdom"),
+			dataframe.NewSeriesInt64("Age", nil, 50, nil),
+			dataframe.NewSeriesFloat64("Amount", nil, 112.1, 18.2),
+			dataframe.NewSeriesBool("Active", nil, true, false),
+		),
+		dataframe.NewDataFrame(
+			dataframe.NewSeriesString("Country", nil, "United States", "Spain"),
+			dataframe.NewSeriesInt64("Age", nil, 32, 66),
+			dataframe.NewSeriesFloat64("Amount", nil, 321.31, 555.42),
+			dataframe.NewSeriesBool("Active", nil, true, false),
+		),
+		dataframe.NewDataFrame(
+			dataframe.NewSeriesString("Country", nil, "United States"),
+			dataframe.NewSeriesInt64("Age", nil, 40),
+			dataframe.NewSeriesFloat64("Amount", nil, 1.5),
+			dataframe.NewSeriesBool("Active", nil, nil),
+		),
+	}
+
+	for i, expDf := range expected {
+		df, err := cr.Next(ctx)
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+
+		eq, err := df.IsEqual(ctx, expDf, dataframe.IsEqualOptions{CheckName: true})
+		assert.NoError(t, err)
+		assert.True(t, eq, "chunk %d: expected %v, got %v", i, expDf.Table(), df.Table())
+	}
+
+	_, err := cr.Next(ctx)
+	assert.Equal(t, io.EOF, err)
+
+	// Values in later chunks must match the inferred type
+	cr = NewCSVChunkReader(strings.NewReader("Age\n1\n2\nthree\n"), 2, opts)
+
+	_, err = cr.Next(ctx)
+	assert.NoError(t, err)
+
+	_, err = cr.Next(ctx)
+	assert.Error(t, err)
+}
@@ -99,3 +99,82 @@ Spain,19:56,2012-02-01,66,555.42,00241
 	assert.True(t, strings.Contains(err.Error(), "merging into column"))
 	assert.Nil(t, df)
 }
+
+func TestLoadFromCSV_categorical(t *testing.T) {
+	csvStr := `
+Country,Size
+"United States",small
+"United Kingdom",large
+"United States",NA
+Spain,medium
+`
+
+	sizes := dataframe.NewSeriesCategorical("", nil)
+	sizes.SetCategories([]string{"small", "medium", "large"}, true)
+
+	opts := CSVLoadOptions{
+		NilValue: &[]string{"NA"}[0],
+		DictateDataType: map[string]interface{}{
+			"Country": dataframe.NewSeriesCategorical("", nil),
+			"Size":    sizes,
+		},
+	}
+
+	df, err := LoadFromCSV(ctx, strings.NewReader(csvStr), opts)
+	if err != nil {
+		t.Errorf("csv import error: %v", err)
+		t.FailNow()
+	}
+
+	expDf := dataframe.NewDataFrame(
+		dataframe.NewSeriesCategorical("country", nil, "United States", "United Kingdom", "United States", "Spain"),
+		dataframe.NewSeriesCategorical("size", nil, "small", "large", nil, "medium"),
+	)
+
+	if eq, _ := df.IsEqual(ctx, expDf); !eq {
+		t.Errorf("csv import not equal")
+	}
+
+	size := df.Series[1].(*dataframe.SeriesCategorical)
+	if !size.Ordered() || size.Code(1) != 2 {
+		t.Errorf("wrong val: expected: %v actual: %v", 2, size.Code(1))
+	}
+}
+
+func TestLazyCSV(t *testing.T) {
+	csvStr := `Country,Age,Amount,Id
+"United States",50,112.1,01234
+"United Kingdom",17,18.2,12345
+"United States",32,321.31,54320
+Spain,66,555.42,00241
+"United States",40,1.5,11111
+`
+
+	opts := CSVLoadOptions{
+		DictateDataType: map[string]interface{}{
+			"Age":    int64(0),
+			"Amount": float64(0),
+		},
+	}
+
+	df, err := LazyCSV(strings.NewReader(csvStr), opts).
+		Filter(func(vals map[interface{}]interface{}) (bool, error) {
+			return vals["Country"] == "United States", nil
+		}, "Country").
+		Select("Age", "Amount").
+		Limit(2).
+		Collect(ctx)
+	if err != nil {
+		t.Errorf("csv import error: %v", err)
+		t.FailNow()
+	}
+
+	expDf := dataframe.NewDataFrame(
+		dataframe.NewSeriesInt64("Age", nil, 50, 32),
+		dataframe.NewSeriesFloat64("Amount", nil, 112.1, 321.31),
+	)
+
+	eq, err := df.IsEqual(ctx, expDf, dataframe.IsEqualOptions{CheckName: true})
+	assert.NoError(t, err)
+	assert.True(t, eq, "expected %v, got %v", expDf.Table(), df.Table())
+}
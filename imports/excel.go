@@ -0,0 +1,452 @@
+// Copyright 2018-20 PJ Engineering and Business Solutions Pty. Ltd. All rights reserved.
+
+package imports
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	dataframe "github.com/rocketlaunchr/dataframe-go"
+	"github.com/tealeg/xlsx/v3"
+)
+
+// ExcelLoadOptions is likely to change.
+type ExcelLoadOptions struct {
+
+	// Sheet is the name (string) or 0-indexed position (int) of the sheet to load.
+	// When not set, the first sheet is loaded.
+	Sheet interface{}
+
+	// HeaderRow is the 0-indexed row (relative to Range) that contains the headings.
+	// Rows above the HeaderRow are ignored.
+	HeaderRow int
+
+	// Range is used to load a subset of the cells in the sheet. eg. "B2:F100".
+	// When not set, all the cells in the sheet are loaded.
+	Range string
+
+	// DictateDataType is used to inform LoadFromExcel what the true underlying data type is for a given field name.
+	// The key must be the case-sensitive field name.
+	// The value for a given key must be of the data type of the data.
+	// eg. For a string use "". For a int64 use int64(0). What is relevant is the data type and not the value itself.
+	// A bool is loaded into a SeriesInt64 with 1 for true and 0 for false.
+	//
+	// NOTE: A custom Series must implement NewSerieser interface and be able to interpret strings to work.
+	DictateDataType map[string]interface{}
+
+	// NilValue allows you to set what cell value should be interpreted as a nil value for
+	// the purposes of insertion. Empty cells and cells containing errors are always nil.
+	//
+	// Common values are: NULL, \N, NaN, NA
+	NilValue *string
+
+	// InferDataTypes can be set to true if the underlying data type should be determined from the cells' types.
+	// Columns of dates become a SeriesTime, whole numbers a SeriesInt64, other numbers a SeriesFloat64 and
+	// booleans a SeriesBool. DictateDataType always takes precedence when determining the type.
+	// Otherwise NewSeriesString is used.
+	InferDataTypes bool
+
+	// Format that should be used to parse and display time strings. Default uses time.RFC3339.
+	TimeFormat string
+}
+
+// LoadFromExcel will load data from a sheet of an xlsx file.
+// r must provide random access to the file and size is the size of the file in bytes.
+//
+// Example:
+//
+//  f, _ := os.Open("report.xlsx")
+//  fi, _ := f.Stat()
+//  df, err := imports.LoadFromExcel(ctx, f, fi.Size(), imports.ExcelLoadOptions{Sheet: "Q1", InferDataTypes: true})
+//
+func LoadFromExcel(ctx context.Context, r io.ReaderAt, size int64, options ...ExcelLoadOptions) (*dataframe.DataFrame, error) {
+
+	if len(options) == 0 {
+		options = append(options, ExcelLoadOptions{})
+	}
+	opts := options[0]
+
+	timeFormat := time.RFC3339
+	if opts.TimeFormat != "" {
+		timeFormat = opts.TimeFormat
+	}
+
+	file, err := xlsx.OpenReaderAt(r, size)
+	if err != nil {
+		return nil, err
+	}
+
+	var sheet *xlsx.Sheet
+	switch s := opts.Sheet.(type) {
+	case nil:
+		if len(file.Sheets) == 0 {
+			return nil, dataframe.ErrNoRows
+		}
+		sheet = file.Sheets[0]
+	case int:
+		if s < 0 || s >= len(file.Sheets) {
+			return nil, fmt.Errorf("sheet out of range: %d", s)
+		}
+		sheet = file.Sheets[s]
+	case string:
+		var exists bool
+		sheet, exists = file.Sheet[s]
+		if !exists {
+			return nil, fmt.Errorf("unknown sheet: %s", s)
+		}
+	default:
+		return nil, fmt.Errorf("unknown Sheet type: %T", opts.Sheet)
+	}
+
+	// Determine cells to load
+	minRow, minCol, maxRow, maxCol := 0, 0, sheet.MaxRow-1, sheet.MaxCol-1
+	if opts.Range != "" {
+		minRow, minCol, maxRow, maxCol, err = parseExcelRange(opts.Range)
+		if err != nil {
+			return nil, err
+		}
+		if maxRow > sheet.MaxRow-1 {
+			maxRow = sheet.MaxRow - 1
+		}
+		if maxCol > sheet.MaxCol-1 {
+			maxCol = sheet.MaxCol - 1
+		}
+	}
+	minRow += opts.HeaderRow
+
+	if minRow > maxRow || minCol > maxCol {
+		return nil, dataframe.ErrNoRows
+	}
+
+	// First row contains headings
+	header, err := sheet.Row(minRow)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, maxCol-minCol+1)
+	for col := minCol; col <= maxCol; col++ {
+		names = append(names, header.GetCell(col).Value)
+	}
+
+	// Convert cells to values
+	nRows := maxRow - minRow
+	cols := make([][]interface{}, len(names))
+	for i := range cols {
+		cols[i] = make([]interface{}, 0, nRows)
+	}
+
+	for row := minRow + 1; row <= maxRow; row++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		r, err := sheet.Row(row)
+		if err != nil {
+			return nil, err
+		}
+
+		for i := range names {
+			cell := r.GetCell(minCol + i)
+			if opts.NilValue != nil && cell.Value == *opts.NilValue {
+				cols[i] = append(cols[i], nil)
+				continue
+			}
+
+			val, err := excelValue(cell, file.Date1904)
+			if err != nil {
+				return nil, fmt.Errorf("%v. row: %d field: %s", err, row-minRow-1, names[i])
+			}
+			cols[i] = append(cols[i], val)
+		}
+	}
+
+	init := &dataframe.SeriesInit{Capacity: nRows}
+	l := &excelLoader{opts: opts, timeFormat: timeFormat, date1904: file.Date1904}
+
+	seriess := make([]dataframe.Series, 0, len(names))
+	for i, name := range names {
+		s, err := l.series(name, cols[i], init)
+		if err != nil {
+			return nil, err
+		}
+		seriess = append(seriess, s)
+	}
+
+	return dataframe.NewDataFrame(seriess...), nil
+}
+
+// parseExcelRange returns the 0-indexed bounds of a range such as "B2:F100".
+func parseExcelRange(rng string) (minRow, minCol, maxRow, maxCol int, _ error) {
+
+	parts := strings.Split(rng, ":")
+	if len(parts) != 2 {
+		return 0, 0, 0, 0, fmt.Errorf("invalid range: %s", rng)
+	}
+
+	minCol, minRow, err := xlsx.GetCoordsFromCellIDString(parts[0])
+	if err != nil {
+		return 0, 0, 0, 0, fmt.Errorf("invalid range: %s", rng)
+	}
+
+	maxCol, maxRow, err = xlsx.GetCoordsFromCellIDString(parts[1])
+	if err != nil {
+		return 0, 0, 0, 0, fmt.Errorf("invalid range: %s", rng)
+	}
+
+	if minRow > maxRow || minCol > maxCol {
+		return 0, 0, 0, 0, fmt.Errorf("invalid range: %s", rng)
+	}
+
+	return minRow, minCol, maxRow, maxCol, nil
+}
+
+// excelValue returns the value of a cell as a string, bool, int64, float64 or time.Time.
+// Empty cells and errors are returned as nil.
+func excelValue(cell *xlsx.Cell, date1904 bool) (interface{}, error) {
+
+	if cell.Value == "" {
+		return nil, nil
+	}
+
+	switch cell.Type() {
+	case xlsx.CellTypeNumeric:
+		if cell.IsTime() {
+			t, err := cell.GetTime(date1904)
+			if err != nil {
+				return nil, fmt.Errorf("can't convert cell: %s to time.Time", cell.Value)
+			}
+			// Remove floating point errors. Excel does not store times beyond millisecond precision.
+			return t.Round(time.Millisecond), nil
+		}
+		if i, err := cell.Int64(); err == nil {
+			return i, nil
+		}
+		f, err := cell.Float()
+		if err != nil {
+			return nil, fmt.Errorf("can't convert cell: %s to float64", cell.Value)
+		}
+		return f, nil
+	case xlsx.CellTypeBool:
+		return cell.Bool(), nil
+	case xlsx.CellTypeError:
+		return nil, nil
+	case xlsx.CellTypeDate:
+		if t, err := time.Parse(time.RFC3339, cell.Value); err == nil {
+			return t, nil
+		}
+	}
+
+	return cell.Value, nil
+}
+
+type excelLoader struct {
+	opts       ExcelLoadOptions
+	timeFormat string
+	date1904   bool
+}
+
+// series creates a Series named name containing vals.
+func (l *excelLoader) series(name string, vals []interface{}, init *dataframe.SeriesInit) (dataframe.Series, error) {
+
+	var s dataframe.Series
+
+	// Check if the datatype is dictated
+	if typ, exists := l.opts.DictateDataType[name]; exists {
+
+		switch T := typ.(type) {
+		case float64:
+			s = dataframe.NewSeriesFloat64(name, init)
+		case int64, bool:
+			s = dataframe.NewSeriesInt64(name, init)
+		case string:
+			s = dataframe.NewSeriesString(name, init)
+		case time.Time:
+			s = dataframe.NewSeriesTime(name, init)
+		case dataframe.NewSerieser:
+			s = T.NewSeries(name, init)
+		case Converter:
+			switch T.ConcreteType.(type) {
+			case time.Time:
+				s = dataframe.NewSeriesTime(name, init)
+			default:
+				s = dataframe.NewSeriesGeneric(name, T.ConcreteType, init)
+			}
+		default:
+			s = dataframe.NewSeriesGeneric(name, typ, init)
+		}
+
+		for row, v := range vals {
+			if v != nil {
+				var err error
+				v, err = l.force(typ, v)
+				if err != nil {
+					return nil, fmt.Errorf("%v. row: %d field: %s", err, row, name)
+				}
+			}
+			s.Append(v, dataframe.DontLock)
+		}
+		return s, nil
+	}
+
+	if l.opts.InferDataTypes {
+		s = inferExcelSeries(name, vals, init)
+	} else {
+		s = dataframe.NewSeriesString(name, init)
+	}
+
+	for _, v := range vals {
+		if v != nil {
+			switch s.(type) {
+			case *dataframe.SeriesString:
+				v = l.toString(v)
+			case *dataframe.SeriesFloat64:
+				if i, ok := v.(int64); ok {
+					v = float64(i)
+				}
+			}
+		}
+		s.Append(v, dataframe.DontLock)
+	}
+
+	return s, nil
+}
+
+// inferExcelSeries creates a Series based on the types of the non-nil values.
+func inferExcelSeries(name string, vals []interface{}, init *dataframe.SeriesInit) dataframe.Series {
+
+	var ints, floats, bools, times, others int
+	for _, v := range vals {
+		switch v.(type) {
+		case nil:
+		case int64:
+			ints++
+		case float64:
+			floats++
+		case bool:
+			bools++
+		case time.Time:
+			times++
+		default:
+			others++
+		}
+	}
+
+	switch {
+	case others > 0 || ints+floats+bools+times == 0:
+		return dataframe.NewSeriesString(name, init)
+	case ints > 0 && floats+bools+times == 0:
+		return dataframe.NewSeriesInt64(name, init)
+	case ints+floats > 0 && bools+times == 0:
+		return dataframe.NewSeriesFloat64(name, init)
+	case bools > 0 && ints+floats+times == 0:
+		return dataframe.NewSeriesBool(name, init)
+	case times > 0 && ints+floats+bools == 0:
+		return dataframe.NewSeriesTime(name, init)
+	}
+
+	return dataframe.NewSeriesString(name, init)
+}
+
+// toString converts a cell's value to a string.
+func (l *excelLoader) toString(v interface{}) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	case time.Time:
+		return v.Format(l.timeFormat)
+	}
+	return fmt.Sprintf("%v", v)
+}
+
+// force converts a cell's value v into the data type typ.
+func (l *excelLoader) force(typ interface{}, v interface{}) (interface{}, error) {
+
+	switch T := typ.(type) {
+	case float64:
+		switch v := v.(type) {
+		case float64:
+			return v, nil
+		case int64:
+			return float64(v), nil
+		case bool:
+			if v {
+				return 1.0, nil
+			}
+			return 0.0, nil
+		case string:
+			f, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				return nil, fmt.Errorf("can't force string: %s to float64", v)
+			}
+			return f, nil
+		}
+	case int64, bool:
+		// bools are treated as int64
+		switch v := v.(type) {
+		case int64:
+			return v, nil
+		case float64:
+			if v == math.Trunc(v) {
+				return int64(v), nil
+			}
+		case bool:
+			if v {
+				return int64(1), nil
+			}
+			return int64(0), nil
+		case string:
+			if _, isBool := T.(bool); isBool {
+				if v == "TRUE" || v == "true" || v == "True" || v == "1" {
+					return int64(1), nil
+				} else if v == "FALSE" || v == "false" || v == "False" || v == "0" {
+					return int64(0), nil
+				}
+				return nil, fmt.Errorf("can't force string: %s to bool", v)
+			}
+			i, err := strconv.ParseInt(v, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("can't force string: %s to int64", v)
+			}
+			return i, nil
+		}
+	case string, dataframe.NewSerieser:
+		return l.toString(v), nil
+	case time.Time:
+		switch v := v.(type) {
+		case time.Time:
+			return v, nil
+		case string:
+			t, err := time.Parse(l.timeFormat, v)
+			if err != nil {
+				return nil, fmt.Errorf("can't force string: %s to time.Time (%s)", v, l.timeFormat)
+			}
+			return t, nil
+		case int64:
+			return xlsx.TimeFromExcelTime(float64(v), l.date1904).Round(time.Millisecond), nil
+		case float64:
+			return xlsx.TimeFromExcelTime(v, l.date1904).Round(time.Millisecond), nil
+		}
+	case Converter:
+		cv, err := T.ConverterFunc(v)
+		if err != nil {
+			return nil, fmt.Errorf("can't force %T to generic data type", v)
+		}
+		return cv, nil
+	default:
+		return v, nil
+	}
+
+	return nil, fmt.Errorf("can't force %T to %T", v, typ)
+}
@@ -0,0 +1,163 @@
+package imports
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/rocketlaunchr/dataframe-go"
+	"github.com/rocketlaunchr/dataframe-go/exports"
+	"github.com/stretchr/testify/assert"
+	"github.com/tealeg/xlsx/v3"
+)
+
+func TestLoadFromExcel(t *testing.T) {
+
+	t1 := time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC)
+	t2 := time.Date(2019, 6, 7, 0, 0, 0, 0, time.UTC)
+
+	file := xlsx.NewFile()
+	file.AddSheet("ignored")
+	sheet, _ := file.AddSheet("data")
+
+	addRow := func(vals ...interface{}) {
+		row := sheet.AddRow()
+		for _, v := range vals {
+			cell := row.AddCell()
+			switch v := v.(type) {
+			case nil:
+			case time.Time:
+				cell.SetDate(v)
+			case bool:
+				cell.SetBool(v)
+			default:
+				cell.SetValue(v)
+			}
+		}
+	}
+
+	addRow("Quarterly report")
+	addRow("Name", "Age", "Amount", "Active", "Joined", "Id")
+	addRow("alpha", 10, 1.5, true, t1, 1)
+	addRow("NA", nil, 2, false, nil, 2)
+	addRow("gamma", 30, 3.25, nil, t2, 3)
+
+	var buf bytes.Buffer
+	if err := file.Write(&buf); err != nil {
+		t.Fatalf("excel export error: %v", err)
+	}
+	r := bytes.NewReader(buf.Bytes())
+
+	opts := ExcelLoadOptions{
+		Sheet:           "data",
+		HeaderRow:       1,
+		InferDataTypes:  true,
+		NilValue:        &[]string{"NA"}[0],
+		DictateDataType: map[string]interface{}{"Id": float64(0)},
+	}
+
+	df, err := LoadFromExcel(ctx, r, r.Size(), opts)
+	if err != nil {
+		t.Fatalf("excel import error: %v", err)
+	}
+
+	expected := dataframe.NewDataFrame(
+		dataframe.NewSeriesString("Name", nil, "alpha", nil, "gamma"),
+		dataframe.NewSeriesInt64("Age", nil, 10, nil, 30),
+		dataframe.NewSeriesFloat64("Amount", nil, 1.5, 2.0, 3.25),
+		dataframe.NewSeriesBool("Active", nil, true, false, nil),
+		dataframe.NewSeriesTime("Joined", nil, t1, nil, t2),
+		dataframe.NewSeriesFloat64("Id", nil, 1.0, 2.0, 3.0),
+	)
+
+	for i, s := range expected.Series {
+		eq, err := s.IsEqual(ctx, df.Series[i], dataframe.IsEqualOptions{CheckName: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.True(t, eq, "series %s: expected: %v actual: %v", s.Name(), s, df.Series[i])
+	}
+
+	// Cell range and default string type
+	df, err = LoadFromExcel(ctx, r, r.Size(), ExcelLoadOptions{Sheet: 1, Range: "B2:C4"})
+	if err != nil {
+		t.Fatalf("excel import error: %v", err)
+	}
+
+	expected = dataframe.NewDataFrame(
+		dataframe.NewSeriesString("Age", nil, "10", nil),
+		dataframe.NewSeriesString("Amount", nil, "1.5", "2"),
+	)
+
+	eq, err := expected.IsEqual(ctx, df)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.True(t, eq, "expected: %v actual: %v", expected, df)
+
+	_, err = LoadFromExcel(ctx, r, r.Size(), ExcelLoadOptions{Sheet: "unknown"})
+	assert.Error(t, err)
+}
+
+func TestExcelRoundTrip(t *testing.T) {
+
+	t1 := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	df1 := dataframe.NewDataFrame(
+		dataframe.NewSeriesString("name", nil, "alpha", nil),
+		dataframe.NewSeriesInt64("age", nil, 10, nil),
+		dataframe.NewSeriesFloat64("amount", nil, 1.25, nil),
+		dataframe.NewSeriesBool("active", nil, true, nil),
+		dataframe.NewSeriesTime("created", nil, t1, nil),
+	)
+	df2 := dataframe.NewDataFrame(
+		dataframe.NewSeriesFloat64("price", nil, 3.5, 4.5, 5.5),
+	)
+
+	var buf bytes.Buffer
+	err := exports.ExportToExcelSheets(ctx, &buf,
+		exports.ExcelSheet{Name: "first", DataFrame: df1, Options: exports.ExcelExportOptions{
+			NumberFormats: map[string]string{"amount": "0.00", "created": "yyyy-mm-dd hh:mm:ss"},
+			ColumnWidths:  map[string]float64{"name": 20},
+			FreezeHeader:  true,
+			AutoFilter:    true,
+		}},
+		exports.ExcelSheet{Name: "second", DataFrame: df2},
+	)
+	if err != nil {
+		t.Fatalf("excel export error: %v", err)
+	}
+	r := bytes.NewReader(buf.Bytes())
+
+	file, err := xlsx.OpenBinary(buf.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	sheet := file.Sheet["first"]
+	if assert.NotNil(t, sheet.AutoFilter) {
+		assert.Equal(t, "A1", sheet.AutoFilter.TopLeftCell)
+		assert.Equal(t, "E3", sheet.AutoFilter.BottomRightCell)
+	}
+
+	got, err := LoadFromExcel(ctx, r, r.Size(), ExcelLoadOptions{Sheet: "first", InferDataTypes: true})
+	if err != nil {
+		t.Fatalf("excel import error: %v", err)
+	}
+
+	eq, err := df1.IsEqual(ctx, got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.True(t, eq, "expected: %v actual: %v", df1, got)
+
+	got, err = LoadFromExcel(ctx, r, r.Size(), ExcelLoadOptions{Sheet: "second", InferDataTypes: true})
+	if err != nil {
+		t.Fatalf("excel import error: %v", err)
+	}
+
+	eq, err = df2.IsEqual(ctx, got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.True(t, eq, "expected: %v actual: %v", df2, got)
+}
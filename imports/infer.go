@@ -53,6 +53,7 @@ func newInferSeries(name string, knownSize *int) *inferSeries {
 	is.series = []dataframe.Series{}
 
 	// Create initial set of series
+	is.series = append(is.series, dataframe.NewSeriesBool(name, init))
 	is.series = append(is.series, dataframe.NewSeriesFloat64(name, init))
 	is.series = append(is.series, dataframe.NewSeriesInt64(name, init))
 	for _, layout := range timelayouts {
@@ -65,6 +66,25 @@ func newInferSeries(name string, knownSize *int) *inferSeries {
 	return is
 }
 
+// newInferSeriesFrom creates an inferSeries that only accepts values of the same type as s.
+// It is used to ensure subsequent chunks of data are inferred consistently.
+func newInferSeriesFrom(s dataframe.Series, init *dataframe.SeriesInit) *inferSeries {
+
+	name := s.Name(dataframe.DontLock)
+
+	var ns dataframe.Series
+	switch x := s.(type) {
+	case *dataframe.SeriesTime:
+		ts := dataframe.NewSeriesTime(name, init)
+		ts.Layout = x.Layout
+		ns = ts
+	default:
+		ns = s.(dataframe.NewSerieser).NewSeries(name, init)
+	}
+
+	return &inferSeries{series: []dataframe.Series{ns}}
+}
+
 // 	We are only appending in here
 func (is *inferSeries) Insert(row int, val interface{}, opts ...dataframe.Options) {
 
@@ -87,6 +107,16 @@ func (is *inferSeries) Insert(row int, val interface{}, opts ...dataframe.Option
 			iterator := s.ValuesIterator(dataframe.ValuesOptions{0, 1, true})
 
 			switch x := s.(type) {
+			case *dataframe.SeriesBool:
+				ns = dataframe.NewSeriesBool(x.Name(dataframe.DontLock), init)
+
+				for {
+					row, val, _ := iterator()
+					if row == nil {
+						break
+					}
+					ns.Append(val, dataframe.DontLock)
+				}
 			case *dataframe.SeriesFloat64:
 				ns = dataframe.NewSeriesFloat64(x.Name(dataframe.DontLock), init)
 
@@ -147,6 +177,17 @@ func (is *inferSeries) Insert(row int, val interface{}, opts ...dataframe.Option
 		// val is string from here onwards
 
 		switch x := s.(type) {
+		case *dataframe.SeriesBool:
+			// Only words are accepted so that 0 and 1 are inferred as int64
+			valStr := val.(string)
+
+			if valStr == "true" || valStr == "TRUE" || valStr == "True" {
+				s.Append(true, dataframe.DontLock)
+			} else if valStr == "false" || valStr == "FALSE" || valStr == "False" {
+				s.Append(false, dataframe.DontLock)
+			} else {
+				toRemove = append(toRemove, i)
+			}
 		case *dataframe.SeriesFloat64:
 			f, err := strconv.ParseFloat(val.(string), 64)
 			if err != nil {
@@ -198,6 +239,18 @@ func (is *inferSeries) inferred() (dataframe.Series, bool) {
 
 	// We have multiple possible series. Which one do we pick?
 
+	// Do we have a SeriesBool
+	for _, s := range is.series {
+		if bs, ok := s.(*dataframe.SeriesBool); ok {
+			// A column containing only nil values is not considered to be a SeriesBool
+			nilCount, _ := bs.NilCount(dataframe.NilCountOptions{DontLock: true})
+			if nilCount < bs.NRows(dataframe.DontLock) {
+				// We found a SeriesBool
+				return bs, true
+			}
+		}
+	}
+
 	// Do we have a SeriesInt64
 	for _, s := range is.series {
 		if is, ok := s.(*dataframe.SeriesInt64); ok {
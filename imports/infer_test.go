@@ -16,15 +16,15 @@ var ctx = context.Background()
 func TestCSVImport(t *testing.T) {
 
 	csvStr := `
-Country,Date,Age,Amount,Id
-"United States",2012-02-01,50,112.1,01234
-"United States",2012-02-01,32,321.31,54320
-"United Kingdom",2012-02-01,17,18.2,12345
-"United States",2012-02-01,32,321.31,54320
-"United Kingdom",2015-05-07,NA,18.2,12345
-"United States",2012-02-01,32,321.31,54320
-"United States",2012-02-01,32,321.31,54320
-Spain,2012-02-01,66,555.42,00241
+Country,Date,Age,Amount,Id,Active
+"United States",2012-02-01,50,112.1,01234,true
+"United States",2012-02-01,32,321.31,54320,false
+"United Kingdom",2012-02-01,17,18.2,12345,TRUE
+"United States",2012-02-01,32,321.31,54320,NA
+"United Kingdom",2015-05-07,NA,18.2,12345,False
+"United States",2012-02-01,32,321.31,54320,true
+"United States",2012-02-01,32,321.31,54320,true
+Spain,2012-02-01,66,555.42,00241,false
 `
 
 	opts := CSVLoadOptions{
@@ -53,6 +53,7 @@ Spain,2012-02-01,66,555.42,00241
 		dataframe.NewSeriesInt64("age", nil, 50, 32, 17, 32, nil, 32, 32, 66),
 		dataframe.NewSeriesFloat64("amount", nil, 112.1, 321.31, 18.2, 321.31, 18.2, 321.31, 321.31, 555.42),
 		dataframe.NewSeriesFloat64("id", nil, 1234, 54320, 12345, 54320, 12345, 54320, 54320, 241),
+		dataframe.NewSeriesBool("active", nil, true, false, true, nil, false, true, true, false),
 	)
 
 	if eq, _ := df.IsEqual(ctx, expDf); !eq {
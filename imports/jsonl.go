@@ -8,14 +8,55 @@ import (
 	"fmt"
 	"io"
 	"sort"
+	"strconv"
+	"strings"
 	"time"
 
 	dataframe "github.com/rocketlaunchr/dataframe-go"
 )
 
+// JSONOrient sets the layout of the json data.
+type JSONOrient int
+
+const (
+	// JSONLines is the jsonl format. Each line is an object representing a row.
+	//
+	// See: http://jsonlines.org/ for more information.
+	JSONLines JSONOrient = 0
+	// JSONRecords is an array of objects, each representing a row: [{"a":1,"b":2},{"a":3,"b":4}]
+	JSONRecords JSONOrient = 1
+	// JSONColumns is an object of arrays, each representing a column: {"a":[1,3],"b":[2,4]}
+	JSONColumns JSONOrient = 2
+	// JSONSplit is an object containing the column names and an array of rows: {"columns":["a","b"],"data":[[1,2],[3,4]]}
+	JSONSplit JSONOrient = 3
+	// JSONValues is an array of rows without column names: [[1,2],[3,4]]
+	JSONValues JSONOrient = 4
+)
+
+// ArrayMode sets how LoadFromJSON imports arrays.
+type ArrayMode int
+
+const (
+	// IgnoreArrays imports arrays as nil values.
+	IgnoreArrays ArrayMode = 0
+	// ExplodeArrays creates a row for each element of an array. The values of the other fields are repeated.
+	// If a row contains multiple arrays, a row is created for each combination of elements.
+	// Empty arrays are imported as nil values.
+	ExplodeArrays ArrayMode = 1
+	// KeepArrays imports arrays as []interface{} values in a SeriesGeneric.
+	// Numbers in the arrays are converted to int64 or float64.
+	// A field that contains an array in any row must only contain arrays or null values.
+	// The data is read twice to find these fields.
+	KeepArrays ArrayMode = 2
+)
+
 // JSONLoadOptions is likely to change.
 type JSONLoadOptions struct {
 
+	// Orient sets the layout of the json data. The default is JSONLines.
+	// Separator, MaxDepth, Arrays, Fields, UnionFields and ErrorOnUnknownFields don't apply to JSONColumns.
+	Orient JSONOrient
+
 	// LargeDataSet should be set to true for large datasets.
 	// It will set the capacity of the underlying slices of the Dataframe by performing a basic parse
 	// of the full dataset before processing the data fully.
@@ -26,70 +67,141 @@ type JSONLoadOptions struct {
 	// The key must be the case-sensitive field name.
 	// The value for a given key must be of the data type of the data.
 	// eg. For a string use "". For a int64 use int64(0). What is relevant is the data type and not the value itself.
+	// A bool is loaded into a SeriesInt64 with 1 for true and 0 for false.
 	//
 	// NOTE: A custom Series must implement NewSerieser interface and be able to interpret strings to work.
 	DictateDataType map[string]interface{}
 
 	// ErrorOnUnknownFields will generate an error if an unknown field is encountered after the first row.
 	ErrorOnUnknownFields bool
+
+	// UnionFields will create a series for every field encountered in any row, rather than only the fields of the first row.
+	// Rows that don't contain a field have a nil value. ErrorOnUnknownFields is ignored when set.
+	UnionFields bool
+
+	// Separator is used to join the keys of nested objects when they are flattened.
+	// When not set, it defaults to ".".
+	Separator string
+
+	// MaxDepth is the maximum number of levels of nested objects that are flattened.
+	// Objects below the maximum depth are imported as json-encoded strings.
+	// When not set, all levels are flattened.
+	MaxDepth *int
+
+	// Arrays sets how arrays are imported. The default is IgnoreArrays.
+	Arrays ArrayMode
+
+	// Fields selects which fields are imported using JSONPath-style expressions.
+	// eg. "$.user.name", "$['first name']" or "$.tags[0]".
+	// The series name is the path joined by Separator (eg. "user.name" or "tags.0").
+	// Selected objects are flattened, with MaxDepth counted from the selected field.
+	// When not set, all fields are imported.
+	Fields []string
 }
 
-// LoadFromJSON will load data from a jsonl file.
-// The first row determines which fields will be imported for subsequent rows.
+// LoadFromJSON will load data from a jsonl file (or another layout set by Orient).
+// The first row determines which fields will be imported for subsequent rows, unless UnionFields is set.
+// Nested objects are flattened by joining the keys with Separator.
+// For JSONValues, the series are named by their position ("0", "1", ...).
+//
+// Example:
+//
+//  {"id": 1, "user": {"name": "Tom"}, "tags": ["a", "b"]}
+//
+//  opts := imports.JSONLoadOptions{Arrays: imports.ExplodeArrays}
+//
+//  +-----+----+------+-----------+
+//  |     | ID | TAGS | USER.NAME |
+//  +-----+----+------+-----------+
+//  | 0:  | 1  |  a   |    Tom    |
+//  | 1:  | 1  |  b   |    Tom    |
+//  +-----+----+------+-----------+
+//
 func LoadFromJSON(ctx context.Context, r io.ReadSeeker, options ...JSONLoadOptions) (*dataframe.DataFrame, error) {
 
+	opts := JSONLoadOptions{}
+	if len(options) > 0 {
+		opts = options[0]
+	}
+
 	var init *dataframe.SeriesInit
 
-	if len(options) > 0 {
-		// Count how many rows we have in order to preallocate underlying slices
-		if options[0].LargeDataSet {
-			init = &dataframe.SeriesInit{}
-			dec := json.NewDecoder(r)
-
-			tokenCount := 0
-			for {
-				if err := ctx.Err(); err != nil {
-					return nil, err
-				}
+	// Count how many rows we have in order to preallocate underlying slices
+	if opts.LargeDataSet && (opts.Orient == JSONLines || opts.Orient == JSONRecords) {
+		init = &dataframe.SeriesInit{}
+		dec := json.NewDecoder(r)
 
-				t, err := dec.Token()
-				if err != nil {
-					if err == io.EOF {
-						r.Seek(0, io.SeekStart)
-						break
-					}
-					return nil, err
+		tokenCount := 0
+		for {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+
+			t, err := dec.Token()
+			if err != nil {
+				if err == io.EOF {
+					r.Seek(0, io.SeekStart)
+					break
 				}
+				return nil, err
+			}
 
-				switch delim := t.(type) {
-				case json.Delim:
-					if delim.String() == "{" {
-						tokenCount++
-					} else if delim.String() == "}" {
-						tokenCount--
-						if tokenCount == 0 {
-							init.Size++
-						}
+			switch delim := t.(type) {
+			case json.Delim:
+				if delim.String() == "{" {
+					tokenCount++
+				} else if delim.String() == "}" {
+					tokenCount--
+					if tokenCount == 0 {
+						init.Size++
 					}
 				}
 			}
 		}
 	}
 
-	knownFields := map[string]interface{}{} // These fields are determined by the first row
-
-	var row int
-	var df *dataframe.DataFrame
-
 	dec := json.NewDecoder(r)
 	dec.UseNumber()
+
+	if opts.Orient == JSONColumns {
+		return loadJSONColumns(ctx, dec, opts)
+	}
+
+	f, err := newJSONFlattener(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	// With KeepArrays, a field is stored in a SeriesGeneric if any of its values is an array
+	var arrayFields map[string]bool
+	if opts.Arrays == KeepArrays {
+		arrayFields, err = findJSONArrayFields(ctx, r, opts, f)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := r.Seek(0, io.SeekStart); err != nil {
+			return nil, err
+		}
+		dec = json.NewDecoder(r)
+		dec.UseNumber()
+	}
+
+	jr := &jsonRecordReader{dec: dec, orient: opts.Orient}
+
+	knownFields := map[string]dataframe.Series{} // These fields are determined by the first row (or all rows if UnionFields is set)
+
+	var (
+		row   int // input row
+		dfRow int // dataframe row
+		df    *dataframe.DataFrame
+	)
+
 	for {
 		if err := ctx.Err(); err != nil {
 			return nil, err
 		}
 
-		var raw map[string]interface{}
-		err := dec.Decode(&raw)
+		raw, err := jr.next()
 		if err != nil {
 			if err == io.EOF {
 				break
@@ -98,186 +210,663 @@ func LoadFromJSON(ctx context.Context, r io.ReadSeeker, options ...JSONLoadOptio
 		}
 		row++
 
-		vals := parseObject(raw, "")
+		for _, vals := range f.flatten(raw) {
 
-		if row == 1 {
+			if df == nil {
 
-			// The first row determines which fields we use
-			knownFields = vals
+				// The first row determines which fields we use
+				seriess := []dataframe.Series{}
+				for name := range vals {
+					s := newJSONSeries(name, arrayFields[name], init, opts)
+					knownFields[name] = s
+					seriess = append(seriess, s)
+				}
 
-			// Create a series for each field (of the appropriate data type)
-			seriess := []dataframe.Series{}
+				// Create the dataframe
+				df = dataframe.NewDataFrame(seriess...)
+			}
 
-			for name := range vals {
+			insertVals := map[string]interface{}{}
 
-				// Check if we know what the datatype should be. Otherwise assume string
-				if len(options) > 0 && len(options[0].DictateDataType) > 0 {
+			for name, val := range vals {
 
-					typ, exists := options[0].DictateDataType[name]
-					if !exists {
-						seriess = append(seriess, dataframe.NewSeriesString(name, init))
+				// Check if field is a known field
+				s, exists := knownFields[name]
+				if !exists {
+					// unknown field
+					if opts.UnionFields {
+						s = newJSONSeries(name, arrayFields[name], &dataframe.SeriesInit{Size: df.NRows(dataframe.DontLock)}, opts)
+						knownFields[name] = s
+						df.AddSeries(s, nil, dataframe.DontLock)
+					} else if opts.ErrorOnUnknownFields {
+						return nil, fmt.Errorf("unknown field encountered. row: %d field: %s", row-1, name)
+					} else {
 						continue
 					}
+				}
 
-					switch T := typ.(type) {
-					case float64:
-						seriess = append(seriess, dataframe.NewSeriesFloat64(name, init))
-					case int64, bool:
-						seriess = append(seriess, dataframe.NewSeriesInt64(name, init))
-					case string:
-						seriess = append(seriess, dataframe.NewSeriesString(name, init))
-					case time.Time:
-						seriess = append(seriess, dataframe.NewSeriesTime(name, init))
-					case dataframe.NewSerieser:
-						seriess = append(seriess, T.NewSeries(name, init))
-					case Converter:
-						switch T.ConcreteType.(type) {
-						case time.Time:
-							seriess = append(seriess, dataframe.NewSeriesTime(name, init))
-						default:
-							seriess = append(seriess, dataframe.NewSeriesGeneric(name, T.ConcreteType, init))
-						}
-					default:
-						seriess = append(seriess, dataframe.NewSeriesGeneric(name, typ, init))
-					}
-				} else {
-					seriess = append(seriess, dataframe.NewSeriesString(name, init))
+				// Store values
+				err := storeJSONValue(row, insertVals, s, name, val, opts)
+				if err != nil {
+					return nil, err
 				}
+			}
+
+			if dfRow >= df.NRows(dataframe.DontLock) {
+				df.Append(&dataframe.DontLock, make([]interface{}, len(df.Series))...)
+			}
+			df.UpdateRow(dfRow, &dataframe.DontLock, insertVals)
+			dfRow++
+		}
+	}
+
+	if df == nil {
+		return nil, dataframe.ErrNoRows
+	}
+
+	// The order is not stable
+	names := df.Names(dataframe.DontLock)
+	sort.Strings(names)
+
+	if order := jr.order(); order != nil {
+		// Preserve the column order of the file. Fields flattened from the same column are kept together.
+		position := func(name string) int {
+			for i, col := range order {
+				if name == col || strings.HasPrefix(name, col+f.separator) {
+					return i
+				}
+			}
+			return len(order)
+		}
+		sort.SliceStable(names, func(i, j int) bool { return position(names[i]) < position(names[j]) })
+	}
+	df.ReorderColumns(names)
+
+	return df, nil
+}
+
+// loadJSONColumns loads data in the JSONColumns orient. Each column is read into a series in turn.
+func loadJSONColumns(ctx context.Context, dec *json.Decoder, opts JSONLoadOptions) (*dataframe.DataFrame, error) {
 
+	if err := expectJSONDelim(dec, '{'); err != nil {
+		if err == io.EOF {
+			return nil, dataframe.ErrNoRows
+		}
+		return nil, err
+	}
+
+	seriess := []dataframe.Series{}
+
+	for dec.More() {
+		t, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		name := t.(string)
+
+		if err := expectJSONDelim(dec, '['); err != nil {
+			return nil, err
+		}
+
+		vals := []interface{}{}
+		for dec.More() {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+
+			var val interface{}
+			if err := dec.Decode(&val); err != nil {
+				return nil, err
 			}
+			vals = append(vals, val)
+		}
 
-			// Create the dataframe
-			df = dataframe.NewDataFrame(seriess...)
+		if err := expectJSONDelim(dec, ']'); err != nil {
+			return nil, err
+		}
+
+		// The series type is decided once all the values of the column are known
+		var isArray bool
+		for _, val := range vals {
+			if _, ok := val.([]interface{}); ok {
+				isArray = true
+				break
+			}
+		}
 
-			// Store values of first row into dataframe
+		s := newJSONSeries(name, isArray, &dataframe.SeriesInit{Capacity: len(vals)}, opts)
+		for i, val := range vals {
 			insertVals := map[string]interface{}{}
+			err := storeJSONValue(i+1, insertVals, s, name, val, opts)
+			if err != nil {
+				return nil, err
+			}
+			s.Append(insertVals[name], dataframe.DontLock)
+		}
 
-			for name, val := range vals {
+		if len(seriess) > 0 && s.NRows(dataframe.DontLock) != seriess[0].NRows(dataframe.DontLock) {
+			return nil, fmt.Errorf("column %s has %d values but expected %d", name, s.NRows(dataframe.DontLock), seriess[0].NRows(dataframe.DontLock))
+		}
+		seriess = append(seriess, s)
+	}
 
-				// Store values
-				if len(options) > 0 && len(options[0].DictateDataType) > 0 {
-
-					// Check if a datatype is dictated
-					typ, exists := options[0].DictateDataType[name]
-					if !exists {
-						// Store value as a string
-						switch v := val.(type) {
-						case string:
-							insertVals[name] = v
-						case json.Number:
-							insertVals[name] = v.String()
-						case bool:
-							if v == true {
-								insertVals[name] = "1"
-							} else {
-								insertVals[name] = "0"
-							}
-						}
-					} else {
-						err := dictateForce(row, insertVals, name, typ, val)
-						if err != nil {
-							return nil, err
-						}
-					}
-				} else {
-					// Store value as a string
-
-					switch v := val.(type) {
-					case string:
-						insertVals[name] = v
-					case json.Number:
-						insertVals[name] = v.String()
-					case bool:
-						if v == true {
-							insertVals[name] = "1"
-						} else {
-							insertVals[name] = "0"
-						}
-					}
-				}
+	if err := expectJSONDelim(dec, '}'); err != nil {
+		return nil, err
+	}
 
+	if len(seriess) == 0 {
+		return nil, dataframe.ErrNoRows
+	}
+
+	return dataframe.NewDataFrame(seriess...), nil
+}
+
+// jsonRecordReader reads the rows of the JSONLines, JSONRecords, JSONSplit and JSONValues orients.
+// Arrays of rows are streamed rather than decoded in full.
+type jsonRecordReader struct {
+	dec     *json.Decoder
+	orient  JSONOrient
+	started bool
+	inRows  bool // the elements of an array of rows are being read
+
+	columns  []string        // JSONSplit column names
+	buffered [][]interface{} // JSONSplit rows that appear before the column names
+	width    int             // maximum number of values in a JSONValues row
+}
+
+// next returns the next row as an object. io.EOF is returned when there are no more rows.
+func (jr *jsonRecordReader) next() (map[string]interface{}, error) {
+
+	if jr.orient == JSONLines {
+		var raw map[string]interface{}
+		err := jr.dec.Decode(&raw)
+		return raw, err
+	}
+
+	if !jr.started {
+		jr.started = true
+		if jr.orient == JSONSplit {
+			if err := expectJSONDelim(jr.dec, '{'); err != nil {
+				return nil, err
+			}
+			if err := jr.splitKeys(); err != nil {
+				return nil, err
 			}
+		} else {
+			if err := expectJSONDelim(jr.dec, '['); err != nil {
+				return nil, err
+			}
+			jr.inRows = true
+		}
+	}
 
-			if init == nil {
-				df.Append(&dataframe.DontLock, make([]interface{}, len(df.Series))...)
+	for jr.inRows {
+		if jr.dec.More() {
+			if jr.orient == JSONRecords {
+				var raw map[string]interface{}
+				err := jr.dec.Decode(&raw)
+				return raw, err
+			}
+
+			var vals []interface{}
+			if err := jr.dec.Decode(&vals); err != nil {
+				return nil, err
+			}
+			return jr.record(vals)
+		}
+
+		// End of the rows
+		if err := expectJSONDelim(jr.dec, ']'); err != nil {
+			return nil, err
+		}
+		jr.inRows = false
+
+		if jr.orient == JSONSplit {
+			if err := jr.splitKeys(); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if len(jr.buffered) > 0 {
+		vals := jr.buffered[0]
+		jr.buffered = jr.buffered[1:]
+		return jr.record(vals)
+	}
+
+	return nil, io.EOF
+}
+
+// splitKeys reads the keys of a JSONSplit object until the rows can be streamed or the object ends.
+func (jr *jsonRecordReader) splitKeys() error {
+
+	for jr.dec.More() {
+		t, err := jr.dec.Token()
+		if err != nil {
+			return err
+		}
+
+		switch key := t.(string); key {
+		case "columns":
+			if err := jr.dec.Decode(&jr.columns); err != nil {
+				return err
+			}
+		case "data":
+			if jr.columns == nil {
+				// The column names are not known yet
+				if err := jr.dec.Decode(&jr.buffered); err != nil {
+					return err
+				}
+				continue
 			}
-			df.UpdateRow(row-1, &dataframe.DontLock, insertVals)
 
+			if err := expectJSONDelim(jr.dec, '['); err != nil {
+				return err
+			}
+			jr.inRows = true
+			return nil
+		case "index":
+			var index json.RawMessage
+			if err := jr.dec.Decode(&index); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("unknown key: %s", key)
+		}
+	}
+
+	return expectJSONDelim(jr.dec, '}')
+}
+
+// record converts a row of values into an object.
+func (jr *jsonRecordReader) record(vals []interface{}) (map[string]interface{}, error) {
+
+	if jr.orient == JSONSplit && len(vals) != len(jr.columns) {
+		return nil, fmt.Errorf("expected %d values in row but got %d", len(jr.columns), len(vals))
+	}
+
+	if len(vals) > jr.width {
+		jr.width = len(vals)
+	}
+
+	rec := make(map[string]interface{}, len(vals))
+	for i, val := range vals {
+		if jr.orient == JSONSplit {
+			rec[jr.columns[i]] = val
 		} else {
+			rec[strconv.Itoa(i)] = val
+		}
+	}
+	return rec, nil
+}
 
-			insertVals := map[string]interface{}{}
+// order returns the column order of the file. nil is returned if the layout does not have an order.
+func (jr *jsonRecordReader) order() []string {
 
-			for name, val := range vals {
+	switch jr.orient {
+	case JSONSplit:
+		return jr.columns
+	case JSONValues:
+		order := make([]string, 0, jr.width)
+		for i := 0; i < jr.width; i++ {
+			order = append(order, strconv.Itoa(i))
+		}
+		return order
+	}
 
-				// Check if field is a known field
-				_, exists := knownFields[name]
-				if !exists {
-					// unknown field
-					if len(options) > 0 && options[0].ErrorOnUnknownFields {
-						return nil, fmt.Errorf("unknown field encountered. row: %d field: %s", row-1, name)
-					}
-					continue
+	return nil
+}
+
+// findJSONArrayFields reads all the rows and returns the fields that contain an array.
+func findJSONArrayFields(ctx context.Context, r io.Reader, opts JSONLoadOptions, f *jsonFlattener) (map[string]bool, error) {
+
+	dec := json.NewDecoder(r)
+	dec.UseNumber()
+
+	jr := &jsonRecordReader{dec: dec, orient: opts.Orient}
+
+	arrayFields := map[string]bool{}
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		raw, err := jr.next()
+		if err != nil {
+			if err == io.EOF {
+				return arrayFields, nil
+			}
+			return nil, err
+		}
+
+		for _, vals := range f.flatten(raw) {
+			for name, val := range vals {
+				if _, isArray := val.([]interface{}); isArray {
+					arrayFields[name] = true
 				}
+			}
+		}
+	}
+}
 
-				// Store values
-				if len(options) > 0 && len(options[0].DictateDataType) > 0 {
-
-					// Check if a datatype is dictated
-					typ, exists := options[0].DictateDataType[name]
-					if !exists {
-						// Store value as a string
-						switch v := val.(type) {
-						case string:
-							insertVals[name] = v
-						case json.Number:
-							insertVals[name] = v.String()
-						case bool:
-							if v == true {
-								insertVals[name] = "1"
-							} else {
-								insertVals[name] = "0"
-							}
-						}
-					} else {
-						err := dictateForce(row, insertVals, name, typ, val)
-						if err != nil {
-							return nil, err
-						}
-					}
-				} else {
-					// Store value as a string
-
-					switch v := val.(type) {
-					case string:
-						insertVals[name] = v
-					case json.Number:
-						insertVals[name] = v.String()
-					case bool:
-						if v == true {
-							insertVals[name] = "1"
-						} else {
-							insertVals[name] = "0"
-						}
+func expectJSONDelim(dec *json.Decoder, delim json.Delim) error {
+
+	t, err := dec.Token()
+	if err != nil {
+		return err
+	}
+
+	if d, ok := t.(json.Delim); !ok || d != delim {
+		return fmt.Errorf("expected %v but got %v", delim, t)
+	}
+	return nil
+}
+
+// storeJSONValue converts val to the data type of the series s and stores it in insertVals.
+func storeJSONValue(row int, insertVals map[string]interface{}, s dataframe.Series, name string, val interface{}, opts JSONLoadOptions) error {
+
+	typ, exists := opts.DictateDataType[name]
+	if exists {
+		// Datatype is dictated
+		return dictateForce(row, insertVals, name, typ, val)
+	}
+
+	if _, isGeneric := s.(*dataframe.SeriesGeneric); isGeneric {
+		// Kept arrays
+		switch v := val.(type) {
+		case nil:
+		case []interface{}:
+			insertVals[name] = normalizeJSON(v)
+		default:
+			return fmt.Errorf("can't store %T in array field. row: %d field: %s", v, row-1, name)
+		}
+		return nil
+	}
+
+	// Store value as a string
+	switch v := val.(type) {
+	case string:
+		insertVals[name] = v
+	case json.Number:
+		insertVals[name] = v.String()
+	case bool:
+		if v == true {
+			insertVals[name] = "1"
+		} else {
+			insertVals[name] = "0"
+		}
+	case []interface{}, map[string]interface{}:
+		b, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		insertVals[name] = string(b)
+	}
+
+	return nil
+}
+
+// newJSONSeries creates a series for a field. isArray is true if any value of the field is an array.
+func newJSONSeries(name string, isArray bool, init *dataframe.SeriesInit, opts JSONLoadOptions) dataframe.Series {
+
+	// Check if we know what the datatype should be. Otherwise assume string
+	typ, exists := opts.DictateDataType[name]
+	if !exists {
+		if isArray && opts.Arrays == KeepArrays {
+			return dataframe.NewSeriesGeneric(name, []interface{}(nil), init)
+		}
+		return dataframe.NewSeriesString(name, init)
+	}
+
+	switch T := typ.(type) {
+	case float64:
+		return dataframe.NewSeriesFloat64(name, init)
+	case int64, bool:
+		return dataframe.NewSeriesInt64(name, init)
+	case string:
+		return dataframe.NewSeriesString(name, init)
+	case time.Time:
+		return dataframe.NewSeriesTime(name, init)
+	case dataframe.NewSerieser:
+		return T.NewSeries(name, init)
+	case Converter:
+		switch T.ConcreteType.(type) {
+		case time.Time:
+			return dataframe.NewSeriesTime(name, init)
+		default:
+			return dataframe.NewSeriesGeneric(name, T.ConcreteType, init)
+		}
+	default:
+		return dataframe.NewSeriesGeneric(name, typ, init)
+	}
+}
+
+// jsonFlattener converts json objects into rows of fields.
+type jsonFlattener struct {
+	separator string
+	maxDepth  *int
+	arrays    ArrayMode
+	fields    []jsonPath
+}
+
+func newJSONFlattener(opts JSONLoadOptions) (*jsonFlattener, error) {
+
+	f := &jsonFlattener{
+		separator: opts.Separator,
+		maxDepth:  opts.MaxDepth,
+		arrays:    opts.Arrays,
+	}
+
+	if f.separator == "" {
+		f.separator = "."
+	}
+
+	for _, expr := range opts.Fields {
+		path, err := parseJSONPath(expr)
+		if err != nil {
+			return nil, err
+		}
+		f.fields = append(f.fields, path)
+	}
+
+	return f, nil
+}
+
+// flatten converts a json object into rows of flattened fields.
+// More than one row is returned when arrays are exploded.
+func (f *jsonFlattener) flatten(obj map[string]interface{}) []map[string]interface{} {
+
+	rows := []map[string]interface{}{{}}
+
+	if f.fields != nil {
+		for _, path := range f.fields {
+			rows = f.add(rows, path.name(f.separator), path.lookup(obj), 0)
+		}
+		return rows
+	}
+
+	for _, k := range sortedKeys(obj) {
+		rows = f.add(rows, k, obj[k], 0)
+	}
+	return rows
+}
+
+// add sets the field key to val for every row. Objects are flattened up to the maximum depth.
+func (f *jsonFlattener) add(rows []map[string]interface{}, key string, val interface{}, depth int) []map[string]interface{} {
+
+	switch v := val.(type) {
+	case map[string]interface{}:
+		if f.maxDepth == nil || depth < *f.maxDepth {
+			for _, k := range sortedKeys(v) {
+				rows = f.add(rows, key+f.separator+k, v[k], depth+1)
+			}
+			return rows
+		}
+
+		// Objects that are too deep are stored as json
+		b, _ := json.Marshal(v)
+		val = string(b)
+	case []interface{}:
+		switch f.arrays {
+		case ExplodeArrays:
+			if len(v) == 0 {
+				val = nil
+				break
+			}
+
+			out := make([]map[string]interface{}, 0, len(rows)*len(v))
+			for _, elem := range v {
+				copies := make([]map[string]interface{}, 0, len(rows))
+				for _, row := range rows {
+					cp := make(map[string]interface{}, len(row))
+					for k, v := range row {
+						cp[k] = v
 					}
+					copies = append(copies, cp)
 				}
+				out = append(out, f.add(copies, key, elem, depth)...)
 			}
+			return out
+		case KeepArrays:
+			// Numbers are converted by storeJSONValue
+		default:
+			val = nil
+		}
+	}
 
-			if init == nil {
-				df.Append(&dataframe.DontLock, make([]interface{}, len(df.Series))...)
+	for _, row := range rows {
+		row[key] = val
+	}
+	return rows
+}
+
+// normalizeJSON converts json.Number values to int64 or float64.
+func normalizeJSON(val interface{}) interface{} {
+
+	switch v := val.(type) {
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		f, _ := v.Float64()
+		return f
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(v))
+		for k, e := range v {
+			out[k] = normalizeJSON(e)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, 0, len(v))
+		for _, e := range v {
+			out = append(out, normalizeJSON(e))
+		}
+		return out
+	default:
+		return v
+	}
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// jsonPath is a parsed JSONPath-style expression.
+// Each element is either an object key (string) or an array index (int).
+type jsonPath []interface{}
+
+// parseJSONPath parses a subset of JSONPath: "$.a.b", "$['a b']", "$[\"a\"]" and "$.a[0]".
+// The leading "$" is optional.
+func parseJSONPath(expr string) (jsonPath, error) {
+
+	p := expr
+	if strings.HasPrefix(p, "$") {
+		p = p[1:]
+	} else if p != "" && p[0] != '.' && p[0] != '[' {
+		p = "." + p
+	}
+
+	var path jsonPath
+
+	for len(p) > 0 {
+		switch p[0] {
+		case '.':
+			p = p[1:]
+			end := strings.IndexAny(p, ".[")
+			if end == -1 {
+				end = len(p)
+			}
+			if end == 0 {
+				return nil, fmt.Errorf("invalid path: %s", expr)
+			}
+			path = append(path, p[:end])
+			p = p[end:]
+		case '[':
+			if len(p) > 1 && (p[1] == '\'' || p[1] == '"') {
+				end := strings.Index(p[2:], string(p[1])+"]")
+				if end == -1 {
+					return nil, fmt.Errorf("invalid path: %s", expr)
+				}
+				path = append(path, p[2:2+end])
+				p = p[2+end+2:]
+				continue
 			}
-			df.UpdateRow(row-1, &dataframe.DontLock, insertVals)
 
+			end := strings.IndexByte(p, ']')
+			if end == -1 {
+				return nil, fmt.Errorf("invalid path: %s", expr)
+			}
+			idx, err := strconv.Atoi(p[1:end])
+			if err != nil || idx < 0 {
+				return nil, fmt.Errorf("invalid path: %s", expr)
+			}
+			path = append(path, idx)
+			p = p[end+1:]
+		default:
+			return nil, fmt.Errorf("invalid path: %s", expr)
 		}
 	}
 
-	if df == nil {
-		return nil, dataframe.ErrNoRows
+	if len(path) == 0 {
+		return nil, fmt.Errorf("invalid path: %s", expr)
 	}
 
-	// The order is not stable
-	names := df.Names(dataframe.DontLock)
-	sort.Strings(names)
-	df.ReorderColumns(names)
+	return path, nil
+}
 
-	return df, nil
+// name returns the series name for the path.
+func (path jsonPath) name(separator string) string {
+	segs := make([]string, 0, len(path))
+	for _, seg := range path {
+		segs = append(segs, fmt.Sprint(seg))
+	}
+	return strings.Join(segs, separator)
+}
+
+// lookup returns the value at the path. nil is returned if the path does not exist.
+func (path jsonPath) lookup(obj map[string]interface{}) interface{} {
+
+	var v interface{} = obj
+	for _, seg := range path {
+		switch s := seg.(type) {
+		case string:
+			m, ok := v.(map[string]interface{})
+			if !ok {
+				return nil
+			}
+			v = m[s]
+		case int:
+			a, ok := v.([]interface{})
+			if !ok || s >= len(a) {
+				return nil
+			}
+			v = a[s]
+		}
+	}
+	return v
 }
@@ -0,0 +1,209 @@
+package imports
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/rocketlaunchr/dataframe-go"
+	"github.com/rocketlaunchr/dataframe-go/exports"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadFromJSONNested(t *testing.T) {
+
+	jsonStr := `{"id": 1, "user": {"name": "Tom", "address": {"city": "Sydney"}}, "tags": ["a", "b"]}
+{"id": 2, "user": {"name": "Ann", "address": {"city": "Perth"}}, "tags": [], "extra": true}
+`
+
+	tests := []struct {
+		name     string
+		opts     JSONLoadOptions
+		expected *dataframe.DataFrame
+	}{
+		{
+			"default",
+			JSONLoadOptions{},
+			dataframe.NewDataFrame(
+				dataframe.NewSeriesString("id", nil, "1", "2"),
+				dataframe.NewSeriesString("tags", nil, nil, nil),
+				dataframe.NewSeriesString("user.address.city", nil, "Sydney", "Perth"),
+				dataframe.NewSeriesString("user.name", nil, "Tom", "Ann"),
+			),
+		},
+		{
+			"separator and max depth",
+			JSONLoadOptions{Separator: "_", MaxDepth: &[]int{1}[0]},
+			dataframe.NewDataFrame(
+				dataframe.NewSeriesString("id", nil, "1", "2"),
+				dataframe.NewSeriesString("tags", nil, nil, nil),
+				dataframe.NewSeriesString("user_address", nil, `{"city":"Sydney"}`, `{"city":"Perth"}`),
+				dataframe.NewSeriesString("user_name", nil, "Tom", "Ann"),
+			),
+		},
+		{
+			"explode arrays and union fields",
+			JSONLoadOptions{Arrays: ExplodeArrays, UnionFields: true},
+			dataframe.NewDataFrame(
+				dataframe.NewSeriesString("extra", nil, nil, nil, "1"),
+				dataframe.NewSeriesString("id", nil, "1", "1", "2"),
+				dataframe.NewSeriesString("tags", nil, "a", "b", nil),
+				dataframe.NewSeriesString("user.address.city", nil, "Sydney", "Sydney", "Perth"),
+				dataframe.NewSeriesString("user.name", nil, "Tom", "Tom", "Ann"),
+			),
+		},
+		{
+			"keep arrays",
+			JSONLoadOptions{Arrays: KeepArrays, DictateDataType: map[string]interface{}{"id": int64(0)}},
+			dataframe.NewDataFrame(
+				dataframe.NewSeriesInt64("id", nil, 1, 2),
+				dataframe.NewSeriesGeneric("tags", []interface{}(nil), nil, []interface{}{"a", "b"}, []interface{}{}),
+				dataframe.NewSeriesString("user.address.city", nil, "Sydney", "Perth"),
+				dataframe.NewSeriesString("user.name", nil, "Tom", "Ann"),
+			),
+		},
+		{
+			"field selection",
+			JSONLoadOptions{Fields: []string{"$.user['name']", "$.tags[1]", "user.address"}},
+			dataframe.NewDataFrame(
+				dataframe.NewSeriesString("tags.1", nil, "b", nil),
+				dataframe.NewSeriesString("user.address.city", nil, "Sydney", "Perth"),
+				dataframe.NewSeriesString("user.name", nil, "Tom", "Ann"),
+			),
+		},
+	}
+
+	for _, tt := range tests {
+		df, err := LoadFromJSON(ctx, strings.NewReader(jsonStr), tt.opts)
+		if err != nil {
+			t.Errorf("%s: json import error: %v", tt.name, err)
+			continue
+		}
+
+		eq, err := df.IsEqual(ctx, tt.expected, dataframe.IsEqualOptions{CheckName: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.True(t, eq, "%s: expected: %v actual: %v", tt.name, tt.expected, df)
+	}
+
+	// Unknown fields
+	_, err := LoadFromJSON(ctx, strings.NewReader(jsonStr), JSONLoadOptions{ErrorOnUnknownFields: true})
+	assert.Error(t, err)
+
+	// Kept arrays that don't appear in the first row
+	df, err := LoadFromJSON(ctx, strings.NewReader(`{"id": 1, "tags": null}
+{"id": 2, "tags": ["a", 3]}
+`), JSONLoadOptions{Arrays: KeepArrays})
+	if err != nil {
+		t.Fatalf("json import error: %v", err)
+	}
+	expected := dataframe.NewDataFrame(
+		dataframe.NewSeriesString("id", nil, "1", "2"),
+		dataframe.NewSeriesGeneric("tags", []interface{}(nil), nil, nil, []interface{}{"a", int64(3)}),
+	)
+	eq, err := df.IsEqual(ctx, expected, dataframe.IsEqualOptions{CheckName: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.True(t, eq, "expected: %v actual: %v", expected, df)
+
+	// Kept arrays mixed with other values
+	_, err = LoadFromJSON(ctx, strings.NewReader(`{"tags": "a"}
+{"tags": ["a", "b"]}
+`), JSONLoadOptions{Arrays: KeepArrays})
+	assert.Error(t, err)
+
+	_, err = LoadFromJSON(ctx, strings.NewReader(`{"tags": ["a", "b"]}
+{"tags": "a"}
+`), JSONLoadOptions{Arrays: KeepArrays})
+	assert.Error(t, err)
+
+	_, err = LoadFromJSON(ctx, strings.NewReader(`{"tags": [["a"], "b"]}`), JSONLoadOptions{Arrays: KeepArrays, Orient: JSONColumns})
+	assert.Error(t, err)
+
+	// Invalid path
+	_, err = LoadFromJSON(ctx, strings.NewReader(jsonStr), JSONLoadOptions{Fields: []string{"$.tags[x]"}})
+	assert.Error(t, err)
+}
+
+func TestJSONOrient(t *testing.T) {
+
+	df := dataframe.NewDataFrame(
+		dataframe.NewSeriesInt64("age", nil, 10, nil, 30),
+		dataframe.NewSeriesFloat64("amount", nil, 1.5, 2.5, nil),
+		dataframe.NewSeriesString("name", nil, "alpha", "beta", nil),
+	)
+
+	// Export format
+	var buf bytes.Buffer
+	err := exports.ExportToJSON(ctx, &buf, df, exports.JSONExportOptions{Orient: exports.JSONSplit, Range: dataframe.Range{End: &[]int{1}[0]}})
+	if err != nil {
+		t.Fatalf("json export error: %v", err)
+	}
+	assert.Equal(t, `{"columns":["age","amount","name"],"data":[[10,1.5,"alpha"],[null,2.5,"beta"]]}`+"\n", buf.String())
+
+	tests := []struct {
+		eOrient exports.JSONOrient
+		iOrient JSONOrient
+	}{
+		{exports.JSONLines, JSONLines},
+		{exports.JSONRecords, JSONRecords},
+		{exports.JSONColumns, JSONColumns},
+		{exports.JSONSplit, JSONSplit},
+		{exports.JSONValues, JSONValues},
+	}
+
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		err := exports.ExportToJSON(ctx, &buf, df, exports.JSONExportOptions{Orient: tt.eOrient})
+		if err != nil {
+			t.Fatalf("json export error: %v", err)
+		}
+
+		opts := JSONLoadOptions{
+			Orient:          tt.iOrient,
+			DictateDataType: map[string]interface{}{"age": int64(0), "amount": float64(0)},
+		}
+
+		expected := df
+		if tt.iOrient == JSONValues {
+			opts.DictateDataType = map[string]interface{}{"0": int64(0), "1": float64(0)}
+			expected = dataframe.NewDataFrame(
+				dataframe.NewSeriesInt64("0", nil, 10, nil, 30),
+				dataframe.NewSeriesFloat64("1", nil, 1.5, 2.5, nil),
+				dataframe.NewSeriesString("2", nil, "alpha", "beta", nil),
+			)
+		}
+
+		got, err := LoadFromJSON(ctx, bytes.NewReader(buf.Bytes()), opts)
+		if err != nil {
+			t.Fatalf("json import error (orient %d): %v", tt.iOrient, err)
+		}
+
+		eq, err := expected.IsEqual(ctx, got, dataframe.IsEqualOptions{CheckName: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.True(t, eq, "orient %d: expected: %v actual: %v", tt.iOrient, expected, got)
+	}
+
+	// Split with data before columns
+	got, err := LoadFromJSON(ctx, strings.NewReader(`{"data":[["x",1],["y",2]],"index":[0,1],"columns":["b","a"]}`), JSONLoadOptions{Orient: JSONSplit})
+	if err != nil {
+		t.Fatalf("json import error: %v", err)
+	}
+	expected := dataframe.NewDataFrame(
+		dataframe.NewSeriesString("b", nil, "x", "y"),
+		dataframe.NewSeriesString("a", nil, "1", "2"),
+	)
+	eq, err := expected.IsEqual(ctx, got, dataframe.IsEqualOptions{CheckName: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.True(t, eq, "expected: %v actual: %v", expected, got)
+
+	// Columns of different lengths
+	_, err = LoadFromJSON(ctx, strings.NewReader(`{"a":[1,2],"b":[1]}`), JSONLoadOptions{Orient: JSONColumns})
+	assert.Error(t, err)
+}
@@ -0,0 +1,356 @@
+// Copyright 2018-20 PJ Engineering and Business Solutions Pty. Ltd. All rights reserved.
+
+package imports
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	dataframe "github.com/rocketlaunchr/dataframe-go"
+	"github.com/xitongsys/parquet-go/common"
+	"github.com/xitongsys/parquet-go/parquet"
+	"github.com/xitongsys/parquet-go/reader"
+	"github.com/xitongsys/parquet-go/source"
+)
+
+// ParquetLoadOptions is likely to change.
+type ParquetLoadOptions struct {
+
+	// Columns is used to import a subset of the columns. The names are case-sensitive.
+	// Nested columns are named by joining the path with a ".".
+	// If not set, all columns are imported.
+	Columns []string
+
+	// RowGroups is used to import a subset of the row groups (0-indexed).
+	// If not set, all row groups are imported.
+	RowGroups []int
+}
+
+// LoadFromParquet will load data from a parquet file.
+// r must provide random access to the file and size is the size of the file in bytes.
+//
+// The parquet types are mapped to the following series:
+//
+//  BOOLEAN                                  -> SeriesBool
+//  INT32, INT64                             -> SeriesInt64
+//  FLOAT, DOUBLE                            -> SeriesFloat64
+//  BYTE_ARRAY, FIXED_LEN_BYTE_ARRAY (UTF8)  -> SeriesString
+//  DATE, TIME_MILLIS, TIME_MICROS,
+//  TIMESTAMP_MILLIS, TIMESTAMP_MICROS, INT96 -> SeriesTime
+//
+// Optional columns can contain nil values. Repeated columns are not supported.
+// Times are returned in UTC. TIME_MILLIS and TIME_MICROS are interpreted as an offset from the unix epoch
+// so that files created by earlier versions of exports.ExportToParquet can be read back.
+//
+// Example:
+//
+//  f, _ := os.Open("data.parquet")
+//  fi, _ := f.Stat()
+//  df, err := imports.LoadFromParquet(ctx, f, fi.Size(), imports.ParquetLoadOptions{Columns: []string{"name", "age"}})
+//
+func LoadFromParquet(ctx context.Context, r io.ReaderAt, size int64, options ...ParquetLoadOptions) (*dataframe.DataFrame, error) {
+
+	if len(options) == 0 {
+		options = append(options, ParquetLoadOptions{})
+	}
+
+	pr, err := reader.NewParquetColumnReader(&parquetFile{r: r, size: size}, 1)
+	if err != nil {
+		return nil, err
+	}
+	defer pr.ReadStop()
+
+	// Select row groups
+	if options[0].RowGroups != nil {
+		rowGroups := pr.Footer.RowGroups
+		selected := make([]*parquet.RowGroup, 0, len(options[0].RowGroups))
+		for _, idx := range options[0].RowGroups {
+			if idx < 0 || idx >= len(rowGroups) {
+				return nil, fmt.Errorf("row group out of range: %d", idx)
+			}
+			selected = append(selected, rowGroups[idx])
+		}
+		pr.Footer.RowGroups = selected
+	}
+
+	var nRows int64
+	for _, rg := range pr.Footer.RowGroups {
+		nRows += rg.NumRows
+	}
+
+	// Determine columns to import
+	cols, err := parquetColumns(pr, options[0].Columns)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(cols) == 0 {
+		return nil, dataframe.ErrNoRows
+	}
+
+	seriess := make([]dataframe.Series, 0, len(cols))
+	for _, col := range cols {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		var values []interface{}
+		if nRows > 0 {
+			values, _, _, err = pr.ReadColumnByPath(col.path, nRows)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		s, err := col.series(values, int(nRows))
+		if err != nil {
+			return nil, err
+		}
+		seriess = append(seriess, s)
+	}
+
+	return dataframe.NewDataFrame(seriess...), nil
+}
+
+type parquetColumn struct {
+	name     string
+	path     string
+	schema   *parquet.SchemaElement
+	repeated bool
+}
+
+// parquetColumns returns the leaf columns of the file in schema order.
+// If names is set, only the named columns are returned (in the order of names).
+func parquetColumns(pr *reader.ParquetReader, names []string) ([]parquetColumn, error) {
+
+	sh := pr.SchemaHandler
+	root := sh.GetRootExName()
+
+	all := []parquetColumn{}
+	lookup := map[string]int{}
+
+	for i, se := range sh.SchemaElements {
+		if i == 0 || se.GetNumChildren() > 0 {
+			continue
+		}
+
+		inPath := sh.IndexMap[int32(i)]
+		exPath := sh.InPathToExPath[inPath]
+		name := strings.TrimPrefix(exPath, root+".")
+
+		rl, err := sh.MaxRepetitionLevel(common.StrToPath(inPath))
+		if err != nil {
+			return nil, err
+		}
+
+		lookup[name] = len(all)
+		all = append(all, parquetColumn{name: name, path: inPath, schema: se, repeated: rl > 0})
+	}
+
+	if names == nil {
+		return all, nil
+	}
+
+	out := make([]parquetColumn, 0, len(names))
+	for _, name := range names {
+		idx, exists := lookup[name]
+		if !exists {
+			return nil, fmt.Errorf("unknown column: %s", name)
+		}
+		out = append(out, all[idx])
+	}
+
+	return out, nil
+}
+
+// series converts the raw values of a column into a Series.
+func (c parquetColumn) series(values []interface{}, nRows int) (dataframe.Series, error) {
+
+	se := c.schema
+
+	if c.repeated {
+		return nil, fmt.Errorf("repeated column not supported: %s", c.name)
+	}
+
+	init := &dataframe.SeriesInit{Capacity: nRows}
+
+	var (
+		s    dataframe.Series
+		conv func(v interface{}) interface{}
+	)
+
+	switch se.GetType() {
+	case parquet.Type_BOOLEAN:
+		s = dataframe.NewSeriesBool(c.name, init)
+	case parquet.Type_INT32:
+		switch {
+		case isConvertedType(se, parquet.ConvertedType_DATE) || (se.LogicalType != nil && se.LogicalType.IsSetDATE()):
+			s = dataframe.NewSeriesTime(c.name, init)
+			conv = func(v interface{}) interface{} {
+				return time.Unix(int64(v.(int32))*24*60*60, 0).UTC()
+			}
+		case isConvertedType(se, parquet.ConvertedType_TIME_MILLIS):
+			s = dataframe.NewSeriesTime(c.name, init)
+			conv = func(v interface{}) interface{} {
+				return time.Unix(0, int64(v.(int32))*int64(time.Millisecond)).UTC()
+			}
+		case isConvertedType(se, parquet.ConvertedType_UINT_32):
+			s = dataframe.NewSeriesInt64(c.name, init)
+			conv = func(v interface{}) interface{} { return int64(uint32(v.(int32))) }
+		default:
+			s = dataframe.NewSeriesInt64(c.name, init)
+			conv = func(v interface{}) interface{} { return int64(v.(int32)) }
+		}
+	case parquet.Type_INT64:
+		if unit := timeUnit(se); unit != 0 {
+			s = dataframe.NewSeriesTime(c.name, init)
+			conv = func(v interface{}) interface{} {
+				return time.Unix(0, v.(int64)*int64(unit)).UTC()
+			}
+		} else {
+			s = dataframe.NewSeriesInt64(c.name, init)
+		}
+	case parquet.Type_INT96:
+		s = dataframe.NewSeriesTime(c.name, init)
+		conv = func(v interface{}) interface{} { return int96ToTime(v.(string)) }
+	case parquet.Type_FLOAT:
+		s = dataframe.NewSeriesFloat64(c.name, init)
+		conv = func(v interface{}) interface{} { return float64(v.(float32)) }
+	case parquet.Type_DOUBLE:
+		s = dataframe.NewSeriesFloat64(c.name, init)
+	case parquet.Type_BYTE_ARRAY, parquet.Type_FIXED_LEN_BYTE_ARRAY:
+		if isConvertedType(se, parquet.ConvertedType_DECIMAL) {
+			return nil, fmt.Errorf("DECIMAL column not supported: %s", c.name)
+		}
+		s = dataframe.NewSeriesString(c.name, init)
+	default:
+		return nil, fmt.Errorf("unsupported parquet type for column: %s", c.name)
+	}
+
+	if len(values) != nRows {
+		return nil, fmt.Errorf("expected %d values for column %s but got %d", nRows, c.name, len(values))
+	}
+
+	for _, v := range values {
+		if v != nil && conv != nil {
+			v = conv(v)
+		}
+		s.Append(v, dataframe.DontLock)
+	}
+
+	return s, nil
+}
+
+func isConvertedType(se *parquet.SchemaElement, ct parquet.ConvertedType) bool {
+	return se.ConvertedType != nil && *se.ConvertedType == ct
+}
+
+// timeUnit returns the unit of an INT64 column that represents a time.
+// 0 is returned if the column does not represent a time.
+func timeUnit(se *parquet.SchemaElement) time.Duration {
+
+	switch {
+	case isConvertedType(se, parquet.ConvertedType_TIMESTAMP_MILLIS):
+		return time.Millisecond
+	case isConvertedType(se, parquet.ConvertedType_TIMESTAMP_MICROS), isConvertedType(se, parquet.ConvertedType_TIME_MICROS):
+		return time.Microsecond
+	}
+
+	if se.LogicalType == nil {
+		return 0
+	}
+
+	var unit *parquet.TimeUnit
+	if se.LogicalType.IsSetTIMESTAMP() {
+		unit = se.LogicalType.TIMESTAMP.GetUnit()
+	} else if se.LogicalType.IsSetTIME() {
+		unit = se.LogicalType.TIME.GetUnit()
+	}
+
+	switch {
+	case unit == nil:
+		return 0
+	case unit.IsSetMILLIS():
+		return time.Millisecond
+	case unit.IsSetMICROS():
+		return time.Microsecond
+	case unit.IsSetNANOS():
+		return time.Nanosecond
+	}
+
+	return 0
+}
+
+// int96ToTime converts a legacy INT96 timestamp (nanoseconds of the day followed by the julian day).
+func int96ToTime(v string) time.Time {
+
+	const julianUnixEpoch = 2440588
+
+	b := []byte(v)
+	nanos := int64(binary.LittleEndian.Uint64(b[:8]))
+	days := int64(binary.LittleEndian.Uint32(b[8:]))
+
+	return time.Unix((days-julianUnixEpoch)*24*60*60, nanos).UTC()
+}
+
+// parquetFile adapts an io.ReaderAt to a source.ParquetFile. It is read-only.
+type parquetFile struct {
+	r      io.ReaderAt
+	size   int64
+	offset int64
+}
+
+func (f *parquetFile) Create(name string) (source.ParquetFile, error) {
+	return nil, errors.New("parquet file is read-only")
+}
+
+func (f *parquetFile) Open(name string) (source.ParquetFile, error) {
+	if name != "" {
+		return nil, fmt.Errorf("external column chunks not supported: %s", name)
+	}
+	return &parquetFile{r: f.r, size: f.size}, nil
+}
+
+func (f *parquetFile) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += f.offset
+	case io.SeekEnd:
+		offset += f.size
+	default:
+		return 0, errors.New("invalid whence")
+	}
+
+	if offset < 0 {
+		return 0, errors.New("negative position")
+	}
+	f.offset = offset
+	return offset, nil
+}
+
+func (f *parquetFile) Read(p []byte) (int, error) {
+	if f.offset >= f.size {
+		return 0, io.EOF
+	}
+
+	n, err := f.r.ReadAt(p, f.offset)
+	f.offset += int64(n)
+	if err == io.EOF && n > 0 {
+		err = nil
+	}
+	return n, err
+}
+
+func (f *parquetFile) Write(p []byte) (int, error) {
+	return 0, errors.New("parquet file is read-only")
+}
+
+func (f *parquetFile) Close() error {
+	return nil
+}
@@ -0,0 +1,158 @@
+package imports
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/rocketlaunchr/dataframe-go"
+	"github.com/rocketlaunchr/dataframe-go/exports"
+	"github.com/stretchr/testify/assert"
+	"github.com/xitongsys/parquet-go/reader"
+)
+
+func TestLoadFromParquet(t *testing.T) {
+
+	t1 := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	t2 := time.Date(2019, 6, 7, 8, 9, 10, 0, time.UTC)
+
+	df := dataframe.NewDataFrame(
+		dataframe.NewSeriesString("name", nil, "alpha", nil, "gamma"),
+		dataframe.NewSeriesInt64("age", nil, 10, 20, nil),
+		dataframe.NewSeriesFloat64("amount", nil, nil, 2.5, 3.75),
+		dataframe.NewSeriesBool("active", nil, true, false, nil),
+		dataframe.NewSeriesTime("created", nil, t1, nil, t2),
+	)
+
+	var buf bytes.Buffer
+	err := exports.ExportToParquet(ctx, &buf, df)
+	if err != nil {
+		t.Fatalf("parquet export error: %v", err)
+	}
+
+	r := bytes.NewReader(buf.Bytes())
+
+	// Round-trip
+	got, err := LoadFromParquet(ctx, r, r.Size())
+	if err != nil {
+		t.Fatalf("parquet import error: %v", err)
+	}
+
+	for _, s := range df.Series {
+		col, err := got.NameToColumn(s.Name())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		eq, err := s.IsEqual(ctx, got.Series[col], dataframe.IsEqualOptions{CheckName: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.True(t, eq, "series %s: expected: %v actual: %v", s.Name(), s, got.Series[col])
+	}
+
+	// Column projection
+	got, err = LoadFromParquet(ctx, r, r.Size(), ParquetLoadOptions{Columns: []string{"created", "age"}})
+	if err != nil {
+		t.Fatalf("parquet import error: %v", err)
+	}
+	assert.Equal(t, []string{"created", "age"}, got.Names())
+	assert.Equal(t, 3, got.NRows())
+
+	_, err = LoadFromParquet(ctx, r, r.Size(), ParquetLoadOptions{Columns: []string{"unknown"}})
+	assert.Error(t, err)
+
+	// Row group selection
+	got, err = LoadFromParquet(ctx, r, r.Size(), ParquetLoadOptions{RowGroups: []int{}})
+	if err != nil {
+		t.Fatalf("parquet import error: %v", err)
+	}
+	assert.Equal(t, 0, got.NRows())
+
+	got, err = LoadFromParquet(ctx, r, r.Size(), ParquetLoadOptions{RowGroups: []int{0}})
+	if err != nil {
+		t.Fatalf("parquet import error: %v", err)
+	}
+	assert.Equal(t, 3, got.NRows())
+
+	_, err = LoadFromParquet(ctx, r, r.Size(), ParquetLoadOptions{RowGroups: []int{1}})
+	assert.Error(t, err)
+}
+
+func TestParquetTypedExport(t *testing.T) {
+
+	type inner struct {
+		Z float32
+	}
+
+	type point struct {
+		X     int32
+		Y     *string
+		Inner inner
+		note  string
+	}
+
+	y := "why"
+	t1 := time.Date(2020, 1, 2, 3, 4, 5, 6000, time.UTC)
+
+	df := dataframe.NewDataFrame(
+		dataframe.NewSeriesString("Full Name", nil, "alpha", nil),
+		dataframe.NewSeriesTime("Created At", nil, t1, nil),
+		dataframe.NewSeriesGeneric("int32", int32(0), nil, int32(-5), nil),
+		dataframe.NewSeriesGeneric("float32", float32(0), nil, nil, float32(1.5)),
+		dataframe.NewSeriesGeneric("bool", false, nil, true, nil),
+		dataframe.NewSeriesGeneric("point", point{}, nil, point{X: 1, Y: &y, Inner: inner{Z: 2.5}}, point{X: 2}),
+		dataframe.NewSeriesMixed("mixed", nil, 1, "two"),
+	)
+
+	var buf bytes.Buffer
+	err := exports.ExportToParquet(ctx, &buf, df)
+	if err != nil {
+		t.Fatalf("parquet export error: %v", err)
+	}
+
+	r := bytes.NewReader(buf.Bytes())
+
+	got, err := LoadFromParquet(ctx, r, r.Size())
+	if err != nil {
+		t.Fatalf("parquet import error: %v", err)
+	}
+
+	expected := dataframe.NewDataFrame(
+		dataframe.NewSeriesString("Full Name", nil, "alpha", nil),
+		dataframe.NewSeriesTime("Created At", nil, t1, nil),
+		dataframe.NewSeriesInt64("int32", nil, -5, nil),
+		dataframe.NewSeriesFloat64("float32", nil, nil, 1.5),
+		dataframe.NewSeriesBool("bool", nil, true, nil),
+		dataframe.NewSeriesInt64("point.X", nil, 1, 2),
+		dataframe.NewSeriesString("point.Y", nil, "why", nil),
+		dataframe.NewSeriesFloat64("point.Inner.Z", nil, 2.5, 0),
+		dataframe.NewSeriesString("mixed", nil, "1", "two"),
+	)
+
+	eq, err := expected.IsEqual(ctx, got, dataframe.IsEqualOptions{CheckName: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.True(t, eq, "expected: %v actual: %v", expected, got)
+
+	// Times are written as TIMESTAMP adjusted to UTC
+	pr, err := reader.NewParquetColumnReader(&parquetFile{r: r, size: r.Size()}, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pr.ReadStop()
+
+	se := pr.SchemaHandler.SchemaElements[2]
+	if assert.NotNil(t, se.LogicalType) && assert.True(t, se.LogicalType.IsSetTIMESTAMP()) {
+		assert.True(t, se.LogicalType.TIMESTAMP.IsAdjustedToUTC)
+	}
+
+	// Names that can't be distinguished
+	df = dataframe.NewDataFrame(
+		dataframe.NewSeriesInt64("name", nil, 1),
+		dataframe.NewSeriesInt64("Name", nil, 2),
+	)
+	err = exports.ExportToParquet(ctx, &bytes.Buffer{}, df)
+	assert.Error(t, err)
+}
@@ -5,6 +5,7 @@ package imports
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"strconv"
@@ -23,6 +24,12 @@ const (
 	PostgreSQL Database = 0
 	// MySQL database
 	MySQL Database = 1
+	// SQLite database
+	SQLite Database = 2
+	// SQLServer is a Microsoft SQL Server database
+	SQLServer Database = 3
+	// Oracle database
+	Oracle Database = 4
 )
 
 type queryContexter1 interface {
@@ -65,6 +72,7 @@ type SQLLoadOptions struct {
 	// The key must be the case-sensitive column name.
 	// The value for a given key must be of the data type of the data.
 	// eg. For a string use "". For a int64 use int64(0). What is relevant is the data type and not the value itself.
+	// A bool is loaded into a SeriesInt64 with 1 for true and 0 for false.
 	//
 	// NOTE: A custom Series must implement NewSerieser interface and be able to interpret strings to work.
 	DictateDataType map[string]interface{}
@@ -80,27 +88,220 @@ type SQLLoadOptions struct {
 
 // LoadFromSQL will load data from a sql database.
 // stmt must be a *sql.Stmt or the equivalent from the mysql-go package.
+// Only the first result set is loaded.
+//
+// The column types (as reported by the driver) are mapped to the following series:
+//
+//  VARCHAR, TEXT, CHAR etc.                  -> SeriesString
+//  INT, BIGINT etc.                          -> SeriesInt64
+//  FLOAT, DOUBLE, REAL etc.                  -> SeriesFloat64
+//  MONEY, SMALLMONEY                         -> SeriesFloat64 (SQLServer only), SeriesString otherwise
+//  DECIMAL, NUMERIC, NUMBER                  -> SeriesInt64 (if the scale is 0 and precision <= 18), SeriesFloat64 otherwise
+//  BOOL, BOOLEAN                             -> SeriesBool
+//  BIT                                       -> SeriesBool (if the driver reports a length of 1, or for SQLServer), SeriesString otherwise
+//  DATE, DATETIME, TIMESTAMP etc.            -> SeriesTime
+//  JSON, JSONB                               -> SeriesGeneric (json.RawMessage)
+//  other types                               -> SeriesString
+//
+// The PostgreSQL (lib/pq) and MySQL drivers don't report the length of BIT columns, so they are loaded as strings
+// ("1" and "\x01" respectively for a BIT(1) value of 1). Dictate the column as a bool to load it as 1 and 0.
 //
 // See: https://godoc.org/github.com/rocketlaunchr/mysql-go#Stmt
 func LoadFromSQL(ctx context.Context, stmt interface{}, options *SQLLoadOptions, args ...interface{}) (*dataframe.DataFrame, error) {
+	return loadFromSQL(ctx, stmt, options, nil, args...)
+}
+
+// LoadFromSQLBatches will load data from a sql database in batches so that large results can be processed with bounded memory.
+// fn is called with a DataFrame for every batchSize rows (the final batch of a result set may be smaller).
+// Each result set is loaded in turn, with resultSet set to its (0-indexed) position.
+// All batches of a result set have the same series names and types. Result sets with no rows are skipped.
+// If fn returns an error, loading stops and the error is returned.
+//
+// See LoadFromSQL for the other arguments. KnownRowCount is ignored.
+//
+// Example:
+//
+//  err := imports.LoadFromSQLBatches(ctx, stmt, nil, 10000, func(resultSet int, df *dataframe.DataFrame) error {
+//     // process df
+//     return nil
+//  })
+//
+func LoadFromSQLBatches(ctx context.Context, stmt interface{}, options *SQLLoadOptions, batchSize int, fn func(resultSet int, df *dataframe.DataFrame) error, args ...interface{}) error {
+
+	if batchSize <= 0 {
+		return errors.New("batchSize must be greater than 0")
+	}
+
+	rows, err := querySQL(ctx, stmt, options, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for resultSet := 0; ; resultSet++ {
+
+		l, err := newSQLLoader(rows, options, nil)
+		if err != nil {
+			return err
+		}
+
+		var (
+			row int
+			df  *dataframe.DataFrame
+		)
+
+		for rows.Next() {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			row++
+
+			insertVals, err := l.record(rows, row)
+			if err != nil {
+				return err
+			}
+
+			if df == nil {
+				df = dataframe.NewDataFrame(l.newSeries(&dataframe.SeriesInit{Capacity: batchSize})...)
+			}
+
+			df.Append(&dataframe.DontLock, make([]interface{}, len(df.Series))...)
+			df.UpdateRow(df.NRows(dataframe.DontLock)-1, &dataframe.DontLock, insertVals)
+
+			if df.NRows(dataframe.DontLock) == batchSize {
+				if err := fn(resultSet, df); err != nil {
+					return err
+				}
+				df = nil
+			}
+		}
+		if err := rows.Err(); err != nil {
+			return err
+		}
+
+		if df != nil {
+			if err := fn(resultSet, df); err != nil {
+				return err
+			}
+		}
+
+		if !rows.NextResultSet() {
+			break
+		}
+	}
+
+	return rows.Err()
+}
+
+// LazySQL returns a LazyFrame that loads data from a sql database when collected.
+// Only the columns required by the LazyFrame are parsed and stored, and rows are filtered and limited
+// while the results are being read. See LoadFromSQL for the arguments.
+func LazySQL(stmt interface{}, options *SQLLoadOptions, args ...interface{}) *dataframe.LazyFrame {
+	return dataframe.NewLazyFrame(&sqlSource{stmt, options, args})
+}
+
+type sqlSource struct {
+	stmt    interface{}
+	options *SQLLoadOptions
+	args    []interface{}
+}
+
+func (src *sqlSource) Scan(ctx context.Context, hints dataframe.ScanHints) (*dataframe.DataFrame, bool, error) {
+	df, err := loadFromSQL(ctx, src.stmt, src.options, &hints, src.args...)
+	return df, true, err
+}
+
+// loadFromSQL loads data from a sql database. If hints is not nil, they are applied while the results are read.
+func loadFromSQL(ctx context.Context, stmt interface{}, options *SQLLoadOptions, hints *dataframe.ScanHints, args ...interface{}) (*dataframe.DataFrame, error) {
 
 	var (
-		init     *dataframe.SeriesInit
-		database Database
-		row      int
-		df       *dataframe.DataFrame
+		init   *dataframe.SeriesInit
+		row    int
+		stored int // number of rows added to df
+		df     *dataframe.DataFrame
 	)
 
-	if options != nil {
+	if options != nil && options.KnownRowCount != nil {
+		init = &dataframe.SeriesInit{
+			Size: *options.KnownRowCount,
+		}
+	}
+
+	rows, err := querySQL(ctx, stmt, options, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	l, err := newSQLLoader(rows, options, hints)
+	if err != nil {
+		return nil, err
+	}
+
+	// Create the dataframe
+	df = dataframe.NewDataFrame(l.newSeries(init)...)
+
+	for rows.Next() {
+		if hints != nil && hints.Limit != nil && stored >= *hints.Limit {
+			break
+		}
+		row++
+
+		insertVals, err := l.record(rows, row)
+		if err != nil {
+			return nil, err
+		}
 
-		if options.KnownRowCount != nil {
-			init = &dataframe.SeriesInit{
-				Size: *options.KnownRowCount,
+		if hints != nil && hints.Predicate != nil {
+			vals := make(map[interface{}]interface{}, len(insertVals))
+			for k, v := range insertVals {
+				vals[k] = v
 			}
+
+			keep, err := hints.Predicate(vals)
+			if err != nil {
+				return nil, &dataframe.RowError{Row: row - 1, Err: err}
+			}
+			if !keep {
+				continue
+			}
+		}
+
+		if init == nil || stored >= init.Size {
+			df.Append(&dataframe.DontLock, make([]interface{}, len(df.Series))...)
 		}
+		df.UpdateRow(stored, &dataframe.DontLock, insertVals)
+		stored++
 
-		database = options.Database
-		if database != PostgreSQL && database != MySQL {
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if df == nil {
+		return nil, dataframe.ErrNoRows
+	}
+
+	// Remove unused preallocated rows from dataframe
+	if init != nil {
+		excess := df.NRows() - stored
+		for {
+			if excess <= 0 {
+				break
+			}
+			df.Remove(df.NRows() - 1) // remove current last row
+			excess--
+		}
+	}
+
+	return df, nil
+}
+
+// querySQL executes stmt and returns the results.
+func querySQL(ctx context.Context, stmt interface{}, options *SQLLoadOptions, args ...interface{}) (rows, error) {
+
+	if options != nil {
+		if options.Database < PostgreSQL || options.Database > Oracle {
 			return nil, errors.New("invalid database")
 		}
 	}
@@ -134,229 +335,330 @@ func LoadFromSQL(ctx context.Context, stmt interface{}, options *SQLLoadOptions,
 	if err != nil {
 		return nil, err
 	}
-	defer rows.Close()
 
-	cols, _ := rows.ColumnTypes()
-	totalColumns := len(cols)
+	return rows, nil
+}
+
+// sqlColumn is the kind of series used for a column type.
+type sqlColumn int
+
+const (
+	sqlString sqlColumn = iota
+	sqlFloat
+	sqlInt
+	sqlBool
+	sqlTime
+	sqlDate
+	sqlJSON
+)
+
+// sqlColumnKind returns the kind of series used for a column based on the type reported by the driver.
+func sqlColumnKind(database Database, ct *sql.ColumnType) sqlColumn {
+
+	switch ct.DatabaseTypeName() {
+	case "VARCHAR", "TEXT", "NVARCHAR", "MEDIUMTEXT", "LONGTEXT", "CHAR", "NCHAR", "NTEXT", "VARCHAR2", "NVARCHAR2", "CLOB":
+		return sqlString
+	case "FLOAT", "FLOAT4", "FLOAT8", "DOUBLE", "REAL", "BINARY_FLOAT", "BINARY_DOUBLE":
+		return sqlFloat
+	case "MONEY", "SMALLMONEY":
+		// PostgreSQL formats money with a currency symbol (eg. $1,234.56)
+		if database == SQLServer {
+			return sqlFloat
+		}
+		return sqlString
+	case "DECIMAL", "NUMERIC", "NUMBER":
+		// Decimals without a fractional part that fit in an int64
+		if precision, scale, ok := ct.DecimalSize(); ok && scale == 0 && precision > 0 && precision <= 18 {
+			return sqlInt
+		}
+		return sqlFloat
+	case "INT", "INTEGER", "TINYINT", "INT2", "INT4", "INT8", "MEDIUMINT", "SMALLINT", "BIGINT":
+		return sqlInt
+	case "BOOL", "BOOLEAN":
+		return sqlBool
+	case "BIT":
+		// Only single bits are bools. PostgreSQL returns bit strings (eg. 101) and MySQL returns bytes for BIT(n > 1).
+		// SQL Server's BIT is always a single bit.
+		if length, ok := ct.Length(); ok && length == 1 || !ok && database == SQLServer {
+			return sqlBool
+		}
+		return sqlString
+	case "DATETIME", "TIMESTAMP", "TIMESTAMPTZ", "DATETIME2", "DATETIMEOFFSET", "SMALLDATETIME":
+		return sqlTime
+	case "DATE":
+		return sqlDate
+	case "JSON", "JSONB":
+		return sqlJSON
+	default: // assume string if info is not available
+		return sqlString
+	}
+}
+
+// sqlLoader converts the rows of a result set into the values of a DataFrame's rows.
+type sqlLoader struct {
+	options  *SQLLoadOptions
+	database Database
+	hints    *dataframe.ScanHints
+	cols     []*sql.ColumnType
+}
 
-	if totalColumns <= 0 {
+func newSQLLoader(rows rows, options *SQLLoadOptions, hints *dataframe.ScanHints) (*sqlLoader, error) {
+
+	cols, _ := rows.ColumnTypes()
+	if len(cols) <= 0 {
 		return nil, errors.New("no series found")
 	}
 
-	// Create the dataframe
+	l := &sqlLoader{
+		options: options,
+		hints:   hints,
+		cols:    cols,
+	}
+
+	if options != nil {
+		l.database = options.Database
+	}
+
+	return l, nil
+}
+
+// dictated returns the data type of a column if it is dictated.
+func (l *sqlLoader) dictated(name string) (interface{}, bool) {
+	if l.options == nil {
+		return nil, false
+	}
+
+	typ, exists := l.options.DictateDataType[name]
+	return typ, exists
+}
+
+// newSeries creates the (empty) series of the DataFrame.
+func (l *sqlLoader) newSeries(init *dataframe.SeriesInit) []dataframe.Series {
+
 	seriess := []dataframe.Series{}
-	for _, ct := range cols { // ct is ColumnType
+	for _, ct := range l.cols { // ct is ColumnType
 		name := ct.Name()
 		typ := ct.DatabaseTypeName()
 
+		if l.hints != nil && !l.hints.NeedsColumn(name) {
+			continue
+		}
+
 		// Check if data type is dictated and use if available
-		if options != nil && len(options.DictateDataType) > 0 {
-			if dtyp, exists := options.DictateDataType[name]; exists {
-
-				switch T := dtyp.(type) {
-				case float64:
-					seriess = append(seriess, dataframe.NewSeriesFloat64(name, init))
-				case int64, bool:
-					seriess = append(seriess, dataframe.NewSeriesInt64(name, init))
-				case string:
-					seriess = append(seriess, dataframe.NewSeriesString(name, init))
+		if dtyp, exists := l.dictated(name); exists {
+
+			switch T := dtyp.(type) {
+			case float64:
+				seriess = append(seriess, dataframe.NewSeriesFloat64(name, init))
+			case int64, bool:
+				seriess = append(seriess, dataframe.NewSeriesInt64(name, init))
+			case string:
+				seriess = append(seriess, dataframe.NewSeriesString(name, init))
+			case time.Time:
+				seriess = append(seriess, dataframe.NewSeriesTime(name, init))
+			case dataframe.NewSerieser:
+				seriess = append(seriess, T.NewSeries(name, init))
+			case Converter:
+				switch T.ConcreteType.(type) {
 				case time.Time:
 					seriess = append(seriess, dataframe.NewSeriesTime(name, init))
-				case dataframe.NewSerieser:
-					seriess = append(seriess, T.NewSeries(name, init))
-				case Converter:
-					switch T.ConcreteType.(type) {
-					case time.Time:
-						seriess = append(seriess, dataframe.NewSeriesTime(name, init))
-					default:
-						seriess = append(seriess, dataframe.NewSeriesGeneric(name, T.ConcreteType, init))
-					}
 				default:
-					seriess = append(seriess, dataframe.NewSeriesGeneric(name, typ, init))
+					seriess = append(seriess, dataframe.NewSeriesGeneric(name, T.ConcreteType, init))
 				}
-
-				continue
+			default:
+				seriess = append(seriess, dataframe.NewSeriesGeneric(name, typ, init))
 			}
+
+			continue
 		}
 
 		// Use typ if info is available
-		switch typ {
-		case "VARCHAR", "TEXT", "NVARCHAR", "MEDIUMTEXT", "LONGTEXT":
-			seriess = append(seriess, dataframe.NewSeriesString(name, init))
-		case "FLOAT", "FLOAT4", "FLOAT8", "DOUBLE", "DECIMAL", "NUMERIC":
+		switch sqlColumnKind(l.database, ct) {
+		case sqlFloat:
 			seriess = append(seriess, dataframe.NewSeriesFloat64(name, init))
-		case "BOOL", "INT", "TINYINT", "INT2", "INT4", "INT8", "MEDIUMINT", "SMALLINT", "BIGINT":
+		case sqlInt:
 			seriess = append(seriess, dataframe.NewSeriesInt64(name, init))
-		case "DATETIME", "TIMESTAMP", "TIMESTAMPTZ":
+		case sqlBool:
+			seriess = append(seriess, dataframe.NewSeriesBool(name, init))
+		case sqlTime, sqlDate:
 			seriess = append(seriess, dataframe.NewSeriesTime(name, init))
-		case "":
-			// Assume string
-			seriess = append(seriess, dataframe.NewSeriesString(name, init))
-		default: // assume string if info is not available
+		case sqlJSON:
+			s := dataframe.NewSeriesGeneric(name, json.RawMessage(nil), init)
+			s.SetValueToStringFormatter(func(v interface{}) string {
+				if v == nil {
+					return "NaN"
+				}
+				return string(v.(json.RawMessage))
+			})
+			seriess = append(seriess, s)
+		default:
 			seriess = append(seriess, dataframe.NewSeriesString(name, init))
 		}
-
 	}
-	df = dataframe.NewDataFrame(seriess...)
 
-	for rows.Next() {
-		row++
+	return seriess
+}
 
-		rowData := make([]interface{}, totalColumns)
-		for i := range rowData {
-			rowData[i] = &[]byte{}
-		}
+// record scans the current row and converts it into the values to insert into the DataFrame. row is used for error messages.
+func (l *sqlLoader) record(rows rows, row int) (map[string]interface{}, error) {
 
-		if err := rows.Scan(rowData...); err != nil {
-			return nil, err
-		}
+	rowData := make([]interface{}, len(l.cols))
+	for i := range rowData {
+		rowData[i] = &[]byte{}
+	}
 
-		insertVals := map[string]interface{}{}
-		for colID, elem := range rowData {
+	if err := rows.Scan(rowData...); err != nil {
+		return nil, err
+	}
 
-			colType := cols[colID].DatabaseTypeName()
-			fieldName := cols[colID].Name()
+	insertVals := map[string]interface{}{}
+	for colID, elem := range rowData {
 
-			var val *string
+		fieldName := l.cols[colID].Name()
 
-			raw := elem.(*[]byte)
-			if !(raw == nil || *raw == nil) {
-				val = &[]string{string(*raw)}[0]
-			}
+		if l.hints != nil && !l.hints.NeedsColumn(fieldName) {
+			continue
+		}
 
-			if val == nil {
-				insertVals[fieldName] = nil
-				continue
-			}
+		var val *string
 
-			if options != nil && len(options.DictateDataType) > 0 {
-				if dtyp, exists := options.DictateDataType[fieldName]; exists {
-
-					switch T := dtyp.(type) {
-					case float64:
-						f, err := strconv.ParseFloat(*val, 64)
-						if err != nil {
-							return nil, fmt.Errorf("can't force string: %s to float64. row: %d field: %s", *val, row-1, fieldName)
-						}
-						insertVals[fieldName] = f
-					case int64:
-						n, err := strconv.ParseInt(*val, 10, 64)
-						if err != nil {
-							return nil, fmt.Errorf("can't force string: %s to Int. row: %d field: %s", *val, row-1, fieldName)
-						}
-						insertVals[fieldName] = n
-					case string:
-						insertVals[fieldName] = *val
-					case bool:
-						if *val == "true" || *val == "TRUE" || *val == "True" || *val == "1" {
-							insertVals[fieldName] = int64(1)
-						} else if *val == "false" || *val == "FALSE" || *val == "False" || *val == "0" {
-							insertVals[fieldName] = int64(0)
-						} else {
-							return nil, fmt.Errorf("can't force string: %s to bool. row: %d field: %s", *val, row-1, fieldName)
-						}
-					case time.Time:
-						layout := time.RFC3339 // Default for PostgreSQL
-						if database == MySQL {
-							layout = "2006-01-02 15:04:05"
-						}
-
-						t, err := time.Parse(layout, *val)
-						if err != nil {
-							// Assume unix timestamp
-							sec, err := strconv.ParseInt(*val, 10, 64)
-							if err != nil {
-								return nil, fmt.Errorf("can't force string: %s to time.Time (%s). row: %d field: %s", *val, layout, row-1, fieldName)
-							}
-							t = time.Unix(sec, 0)
-						}
-						insertVals[fieldName] = t
-					case dataframe.NewSerieser:
-						insertVals[fieldName] = *val
-					case Converter:
-						cv, err := T.ConverterFunc(*val)
-						if err != nil {
-							return nil, fmt.Errorf("can't force string: %s to generic data type. row: %d field: %s", *val, row-1, fieldName)
-						}
-						insertVals[fieldName] = cv
-					default:
-						insertVals[fieldName] = *val
-					}
-
-					continue
-				}
-			}
+		raw := elem.(*[]byte)
+		if !(raw == nil || *raw == nil) {
+			val = &[]string{string(*raw)}[0]
+		}
 
-			switch colType {
-			case "VARCHAR", "TEXT", "NVARCHAR", "MEDIUMTEXT", "LONGTEXT":
-				insertVals[fieldName] = *val
-			case "FLOAT", "DOUBLE", "DECIMAL", "NUMERIC", "FLOAT4", "FLOAT8":
+		if val == nil {
+			insertVals[fieldName] = nil
+			continue
+		}
+
+		if dtyp, exists := l.dictated(fieldName); exists {
+
+			switch T := dtyp.(type) {
+			case float64:
 				f, err := strconv.ParseFloat(*val, 64)
 				if err != nil {
 					return nil, fmt.Errorf("can't force string: %s to float64. row: %d field: %s", *val, row-1, fieldName)
 				}
 				insertVals[fieldName] = f
-			case "INT", "TINYINT", "INT2", "INT4", "INT8", "MEDIUMINT", "SMALLINT", "BIGINT":
+			case int64:
 				n, err := strconv.ParseInt(*val, 10, 64)
 				if err != nil {
 					return nil, fmt.Errorf("can't force string: %s to Int. row: %d field: %s", *val, row-1, fieldName)
 				}
 				insertVals[fieldName] = n
-			case "BOOL":
-				if *val == "true" || *val == "TRUE" || *val == "True" || *val == "1" {
+			case string:
+				insertVals[fieldName] = *val
+			case bool:
+				b, err := parseSQLBool(*val)
+				if err != nil {
+					return nil, fmt.Errorf("%v. row: %d field: %s", err, row-1, fieldName)
+				}
+				if b {
 					insertVals[fieldName] = int64(1)
-				} else if *val == "false" || *val == "FALSE" || *val == "False" || *val == "0" {
-					insertVals[fieldName] = int64(0)
 				} else {
-					return nil, fmt.Errorf("can't force string: %s to bool. row: %d field: %s", *val, row-1, fieldName)
-				}
-			case "DATETIME", "TIMESTAMP", "TIMESTAMPTZ":
-				layout := time.RFC3339 // Default for PostgreSQL
-				if database == MySQL {
-					layout = "2006-01-02 15:04:05"
+					insertVals[fieldName] = int64(0)
 				}
-
-				t, err := time.Parse(layout, *val)
+			case time.Time:
+				t, err := parseSQLTime(l.database, *val)
 				if err != nil {
-					// Assume unix timestamp
-					sec, err := strconv.ParseInt(*val, 10, 64)
-					if err != nil {
-						return nil, fmt.Errorf("can't force string: %s to time.Time (%s). row: %d field: %s", *val, layout, row-1, fieldName)
-					}
-					t = time.Unix(sec, 0)
+					return nil, fmt.Errorf("%v. row: %d field: %s", err, row-1, fieldName)
 				}
 				insertVals[fieldName] = t
+			case dataframe.NewSerieser:
+				insertVals[fieldName] = *val
+			case Converter:
+				cv, err := T.ConverterFunc(*val)
+				if err != nil {
+					return nil, fmt.Errorf("can't force string: %s to generic data type. row: %d field: %s", *val, row-1, fieldName)
+				}
+				insertVals[fieldName] = cv
 			default:
-				// Assume string
 				insertVals[fieldName] = *val
 			}
-		}
 
-		if init == nil {
-			df.Append(&dataframe.DontLock, make([]interface{}, len(df.Series))...)
+			continue
 		}
-		df.UpdateRow(row-1, &dataframe.DontLock, insertVals)
 
+		switch sqlColumnKind(l.database, l.cols[colID]) {
+		case sqlFloat:
+			f, err := strconv.ParseFloat(*val, 64)
+			if err != nil {
+				return nil, fmt.Errorf("can't force string: %s to float64. row: %d field: %s", *val, row-1, fieldName)
+			}
+			insertVals[fieldName] = f
+		case sqlInt:
+			n, err := strconv.ParseInt(*val, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("can't force string: %s to Int. row: %d field: %s", *val, row-1, fieldName)
+			}
+			insertVals[fieldName] = n
+		case sqlBool:
+			b, err := parseSQLBool(*val)
+			if err != nil {
+				return nil, fmt.Errorf("%v. row: %d field: %s", err, row-1, fieldName)
+			}
+			insertVals[fieldName] = b
+		case sqlTime:
+			t, err := parseSQLTime(l.database, *val)
+			if err != nil {
+				return nil, fmt.Errorf("%v. row: %d field: %s", err, row-1, fieldName)
+			}
+			insertVals[fieldName] = t
+		case sqlDate:
+			t, err := time.Parse("2006-01-02", *val)
+			if err != nil {
+				// Some drivers return dates as times
+				t, err = parseSQLTime(l.database, *val)
+				if err != nil {
+					return nil, fmt.Errorf("%v. row: %d field: %s", err, row-1, fieldName)
+				}
+			}
+			insertVals[fieldName] = t
+		case sqlJSON:
+			if !json.Valid(*raw) {
+				return nil, fmt.Errorf("can't force string: %s to json. row: %d field: %s", *val, row-1, fieldName)
+			}
+			insertVals[fieldName] = json.RawMessage(*val)
+		default:
+			// Assume string
+			insertVals[fieldName] = *val
+		}
 	}
-	if err := rows.Err(); err != nil {
-		return nil, err
+
+	return insertVals, nil
+}
+
+// parseSQLBool parses a bool returned by the database.
+func parseSQLBool(val string) (bool, error) {
+	switch val {
+	case "true", "TRUE", "True", "t", "1", "\x01":
+		return true, nil
+	case "false", "FALSE", "False", "f", "0", "\x00":
+		return false, nil
 	}
+	return false, fmt.Errorf("can't force string: %s to bool", val)
+}
 
-	if df == nil {
-		return nil, dataframe.ErrNoRows
+// parseSQLTime parses a time returned by the database.
+// The database's default layout is tried first, followed by the other common layout and then a unix timestamp.
+func parseSQLTime(database Database, val string) (time.Time, error) {
+
+	layouts := []string{time.RFC3339, "2006-01-02 15:04:05"} // Default for PostgreSQL
+	if database == MySQL || database == SQLite {
+		layouts[0], layouts[1] = layouts[1], layouts[0]
 	}
 
-	// Remove unused preallocated rows from dataframe
-	if init != nil {
-		excess := init.Size - df.NRows()
-		for {
-			if excess <= 0 {
-				break
-			}
-			df.Remove(df.NRows() - 1) // remove current last row
-			excess--
+	for _, layout := range layouts {
+		if t, err := time.Parse(layout, val); err == nil {
+			return t, nil
 		}
 	}
 
-	return df, nil
+	// Assume unix timestamp
+	sec, err := strconv.ParseInt(val, 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("can't force string: %s to time.Time (%s)", val, layouts[0])
+	}
+	return time.Unix(sec, 0), nil
 }
@@ -0,0 +1,273 @@
+package imports
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/rocketlaunchr/dataframe-go"
+	"github.com/stretchr/testify/assert"
+)
+
+// testResultSet is a result set returned by testDriver.
+type testResultSet struct {
+	columns []string
+	types   []string
+	lengths []int64 // 0 if the length is not reported
+	rows    [][]driver.Value
+}
+
+// testDriver is a database/sql driver that returns the same result sets for every query.
+type testDriver struct {
+	resultSets []testResultSet
+}
+
+func (d *testDriver) Open(name string) (driver.Conn, error) { return &testConn{d}, nil }
+
+type testConn struct{ d *testDriver }
+
+func (c *testConn) Prepare(query string) (driver.Stmt, error) { return &testStmt{c.d}, nil }
+func (c *testConn) Close() error                              { return nil }
+func (c *testConn) Begin() (driver.Tx, error)                 { return nil, driver.ErrSkip }
+
+type testStmt struct{ d *testDriver }
+
+func (s *testStmt) Close() error                                    { return nil }
+func (s *testStmt) NumInput() int                                   { return -1 }
+func (s *testStmt) Exec(args []driver.Value) (driver.Result, error) { return nil, driver.ErrSkip }
+func (s *testStmt) Query(args []driver.Value) (driver.Rows, error) {
+	return &testRows{resultSets: s.d.resultSets}, nil
+}
+
+type testRows struct {
+	resultSets []testResultSet
+	set        int
+	row        int
+}
+
+func (r *testRows) Columns() []string { return r.resultSets[r.set].columns }
+func (r *testRows) Close() error      { return nil }
+
+func (r *testRows) Next(dest []driver.Value) error {
+	rs := r.resultSets[r.set]
+	if r.row >= len(rs.rows) {
+		return io.EOF
+	}
+	copy(dest, rs.rows[r.row])
+	r.row++
+	return nil
+}
+
+func (r *testRows) HasNextResultSet() bool { return r.set < len(r.resultSets)-1 }
+
+func (r *testRows) NextResultSet() error {
+	if !r.HasNextResultSet() {
+		return io.EOF
+	}
+	r.set++
+	r.row = 0
+	return nil
+}
+
+func (r *testRows) ColumnTypeDatabaseTypeName(index int) string {
+	return r.resultSets[r.set].types[index]
+}
+
+func (r *testRows) ColumnTypePrecisionScale(index int) (int64, int64, bool) {
+	if r.resultSets[r.set].types[index] == "DECIMAL" {
+		if r.resultSets[r.set].columns[index] == "whole" {
+			return 10, 0, true
+		}
+		return 10, 2, true
+	}
+	return 0, 0, false
+}
+
+func (r *testRows) ColumnTypeLength(index int) (int64, bool) {
+	if lengths := r.resultSets[r.set].lengths; lengths != nil && lengths[index] > 0 {
+		return lengths[index], true
+	}
+	return 0, false
+}
+
+func init() {
+	sql.Register("dataframe-test-types", &testDriver{
+		resultSets: []testResultSet{
+			{
+				columns: []string{"price", "flag", "bits", "bit", "active"},
+				types:   []string{"MONEY", "BIT", "BIT", "BIT", "VARCHAR"},
+				lengths: []int64{0, 1, 3, 0, 0},
+				rows: [][]driver.Value{
+					{"1234.56", "\x01", "101", "1", "t"},
+					{nil, "\x00", nil, "0", "f"},
+				},
+			},
+		},
+	})
+
+	sql.Register("dataframe-test", &testDriver{
+		resultSets: []testResultSet{
+			{
+				columns: []string{"whole", "amount", "active", "day", "data", "name"},
+				types:   []string{"DECIMAL", "DECIMAL", "BOOL", "DATE", "JSON", "VARCHAR"},
+				rows: [][]driver.Value{
+					{"1", "1.25", "true", "2020-01-02", `{"a":1}`, "alpha"},
+					{"2", "2.50", "false", nil, `[1,2]`, "beta"},
+					{"3", nil, nil, "2020-01-04T00:00:00Z", nil, "gamma"},
+				},
+			},
+			{
+				columns: []string{"id"},
+				types:   []string{"INT"},
+				rows:    [][]driver.Value{},
+			},
+			{
+				columns: []string{"id"},
+				types:   []string{"BIGINT"},
+				rows: [][]driver.Value{
+					{"10"},
+				},
+			},
+		},
+	})
+}
+
+func TestLoadFromSQL(t *testing.T) {
+
+	db, err := sql.Open("dataframe-test", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	stmt, err := db.PrepareContext(ctx, "SELECT")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stmt.Close()
+
+	d1 := time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC)
+	d2 := time.Date(2020, 1, 4, 0, 0, 0, 0, time.UTC)
+
+	expected := dataframe.NewDataFrame(
+		dataframe.NewSeriesInt64("whole", nil, 1, 2, 3),
+		dataframe.NewSeriesFloat64("amount", nil, 1.25, 2.5, nil),
+		dataframe.NewSeriesBool("active", nil, true, false, nil),
+		dataframe.NewSeriesTime("day", nil, d1, nil, d2),
+		dataframe.NewSeriesGeneric("data", json.RawMessage(nil), nil, json.RawMessage(`{"a":1}`), json.RawMessage(`[1,2]`), nil),
+		dataframe.NewSeriesString("name", nil, "alpha", "beta", "gamma"),
+	)
+
+	df, err := LoadFromSQL(ctx, stmt, nil)
+	if err != nil {
+		t.Fatalf("sql import error: %v", err)
+	}
+
+	eq, err := expected.IsEqual(ctx, df, dataframe.IsEqualOptions{CheckName: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.True(t, eq, "expected: %v actual: %v", expected, df)
+	assert.Equal(t, `{"a":1}`, df.Series[4].ValueString(0))
+
+	// Batches
+	type batch struct {
+		resultSet int
+		rows      int
+	}
+
+	batches := []batch{}
+	err = LoadFromSQLBatches(ctx, stmt, nil, 2, func(resultSet int, df *dataframe.DataFrame) error {
+		batches = append(batches, batch{resultSet, df.NRows()})
+
+		if resultSet == 0 {
+			// Batches of a result set contain consecutive rows
+			offset := 2 * (len(batches) - 1)
+			e := expected.Copy(dataframe.Range{Start: &offset, End: &[]int{offset + df.NRows() - 1}[0]})
+			eq, err := e.IsEqual(ctx, df, dataframe.IsEqualOptions{CheckName: true})
+			if err != nil {
+				return err
+			}
+			assert.True(t, eq, "expected: %v actual: %v", e, df)
+		} else {
+			assert.Equal(t, []string{"id"}, df.Names())
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("sql import error: %v", err)
+	}
+	assert.Equal(t, []batch{{0, 2}, {0, 1}, {2, 1}}, batches)
+}
+
+func TestLoadFromSQLTypes(t *testing.T) {
+
+	db, err := sql.Open("dataframe-test-types", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	stmt, err := db.PrepareContext(ctx, "SELECT")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stmt.Close()
+
+	tests := []struct {
+		database Database
+		expected *dataframe.DataFrame
+	}{
+		{
+			PostgreSQL,
+			dataframe.NewDataFrame(
+				dataframe.NewSeriesString("price", nil, "1234.56", nil),
+				dataframe.NewSeriesBool("flag", nil, true, false),
+				dataframe.NewSeriesString("bits", nil, "101", nil),
+				dataframe.NewSeriesString("bit", nil, "1", "0"),
+				dataframe.NewSeriesInt64("active", nil, 1, 0),
+			),
+		},
+		{
+			SQLServer,
+			dataframe.NewDataFrame(
+				dataframe.NewSeriesFloat64("price", nil, 1234.56, nil),
+				dataframe.NewSeriesBool("flag", nil, true, false),
+				dataframe.NewSeriesString("bits", nil, "101", nil),
+				dataframe.NewSeriesBool("bit", nil, true, false),
+				dataframe.NewSeriesInt64("active", nil, 1, 0),
+			),
+		},
+	}
+
+	for _, tt := range tests {
+		df, err := LoadFromSQL(ctx, stmt, &SQLLoadOptions{Database: tt.database, DictateDataType: map[string]interface{}{"active": false}})
+		if err != nil {
+			t.Fatalf("sql import error: %v", err)
+		}
+
+		eq, err := tt.expected.IsEqual(ctx, df, dataframe.IsEqualOptions{CheckName: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.True(t, eq, "%d: expected: %v actual: %v", tt.database, tt.expected, df)
+	}
+
+	// BIT columns without a length can be dictated as bools
+	df, err := LoadFromSQL(ctx, stmt, &SQLLoadOptions{DictateDataType: map[string]interface{}{"flag": false, "bit": false}})
+	if err != nil {
+		t.Fatalf("sql import error: %v", err)
+	}
+
+	for _, col := range []int{1, 3} {
+		expected := dataframe.NewSeriesInt64(df.Series[col].Name(), nil, 1, 0)
+		eq, err := expected.IsEqual(ctx, df.Series[col], dataframe.IsEqualOptions{CheckName: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.True(t, eq, "expected: %v actual: %v", expected, df.Series[col])
+	}
+}
@@ -0,0 +1,264 @@
+// Copyright 2018-20 PJ Engineering and Business Solutions Pty. Ltd. All rights reserved.
+
+package dataframe
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// RangeIndex can be provided to SetIndex to label each row with sequential integers.
+// The labels are stored in a SeriesInt64 so that they are retained after the
+// DataFrame is sorted or filtered.
+type RangeIndex struct {
+
+	// Name is the name of the index.
+	Name string
+
+	// Start is the label of the first row.
+	Start int
+}
+
+// IndexOptions modifies the behavior of SetIndex and ResetIndex.
+type IndexOptions struct {
+
+	// Drop will discard the Series that is being replaced as the index.
+	// For SetIndex, it is the existing index. For ResetIndex, it is the index itself.
+	// When not set, the replaced index is inserted as the first Series of the DataFrame.
+	Drop bool
+
+	// DontLock can be set to true if the DataFrame should not be locked.
+	DontLock bool
+}
+
+// Index returns the Series used to label the rows of the DataFrame.
+// If no index has been set, nil is returned and rows are labelled by their position.
+func (df *DataFrame) Index(opts ...Options) Series {
+	if len(opts) == 0 || !opts[0].DontLock {
+		df.lock.RLock()
+		defer df.lock.RUnlock()
+	}
+
+	return df.index
+}
+
+// SetIndex sets the index of the DataFrame. The index labels each row, is retained by operations
+// such as Copy, Filter, Sort, Apply and Merge, and is displayed by Table.
+//
+// key can be an int (position of series) or string (name of series), in which case
+// the Series is removed from the DataFrame and becomes the index. Alternatively, key can be a Series
+// with the same number of rows as the DataFrame (a copy of which becomes the index), or a RangeIndex.
+//
+// Example:
+//
+//  df.SetIndex("id")
+//  df.SetIndex(dataframe.RangeIndex{Name: "row"})
+//
+func (df *DataFrame) SetIndex(key interface{}, opts ...IndexOptions) error {
+
+	if len(opts) == 0 {
+		opts = append(opts, IndexOptions{})
+	}
+
+	if !opts[0].DontLock {
+		df.lock.Lock()
+		defer df.lock.Unlock()
+	}
+
+	var (
+		idx Series
+		col = -1
+	)
+
+	switch k := key.(type) {
+	case RangeIndex:
+		s := NewSeriesInt64(k.Name, &SeriesInit{Capacity: df.n})
+		for row := 0; row < df.n; row++ {
+			s.values = append(s.values, &[]int64{int64(k.Start + row)}[0])
+		}
+		idx = s
+	case Series:
+		if k.NRows() != df.n {
+			return errors.New("different number of rows in series")
+		}
+		// The series may also be a series of the DataFrame, so it must not be shared
+		idx = k.Copy()
+	default:
+		var err error
+		col, err = df.keyToColumn(key)
+		if err != nil {
+			return err
+		}
+		idx = df.Series[col]
+	}
+
+	restore := df.index != nil && !opts[0].Drop
+	if restore {
+		if err := df.checkIndexName(col); err != nil {
+			return err
+		}
+	}
+
+	if col != -1 {
+		df.Series = append(df.Series[:col], df.Series[col+1:]...)
+	}
+
+	if restore {
+		df.restoreIndex()
+	}
+
+	df.index = idx
+
+	return nil
+}
+
+// ResetIndex removes the index of the DataFrame so that rows are labelled by their position.
+// Unless the Drop option is set, the index is inserted as the first Series of the DataFrame.
+// If the index is unnamed, the Series is named "index".
+func (df *DataFrame) ResetIndex(opts ...IndexOptions) error {
+
+	if len(opts) == 0 {
+		opts = append(opts, IndexOptions{})
+	}
+
+	if !opts[0].DontLock {
+		df.lock.Lock()
+		defer df.lock.Unlock()
+	}
+
+	if df.index == nil {
+		return nil
+	}
+
+	if !opts[0].Drop {
+		if err := df.checkIndexName(-1); err != nil {
+			return err
+		}
+		df.restoreIndex()
+	}
+
+	df.index = nil
+
+	return nil
+}
+
+// indexName returns the name the index will have when inserted into the DataFrame.
+func (df *DataFrame) indexName() string {
+	if name := df.index.Name(); name != "" {
+		return name
+	}
+	return "index"
+}
+
+// checkIndexName returns an error if the index can not be inserted into the DataFrame
+// because a Series (other than the one at position skipCol) has the same name.
+func (df *DataFrame) checkIndexName(skipCol int) error {
+	name := df.indexName()
+	for i, s := range df.Series {
+		if i != skipCol && s.Name() == name {
+			return fmt.Errorf("names of series must be unique: %s", name)
+		}
+	}
+	return nil
+}
+
+// restoreIndex inserts the index as the first Series of the DataFrame.
+func (df *DataFrame) restoreIndex() {
+	df.index.Rename(df.indexName())
+	df.Series = append([]Series{df.index}, df.Series...)
+}
+
+// Loc returns a new DataFrame containing the rows whose index label matches one of labels, in the order
+// of labels. If a label matches multiple rows, they are all returned. If no index has been set,
+// labels are interpreted as row positions. An error is returned if a label can not be found.
+func (df *DataFrame) Loc(ctx context.Context, labels []interface{}, opts ...Options) (*DataFrame, error) {
+	if len(opts) == 0 || !opts[0].DontLock {
+		df.lock.RLock()
+		defer df.lock.RUnlock()
+	}
+
+	var lookup map[interface{}][]int
+
+	if df.index != nil {
+		lookup = make(map[interface{}][]int, df.n)
+		for row := 0; row < df.n; row++ {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+
+			val := df.index.Value(row)
+			if val == nil {
+				continue
+			}
+			key := hashKey(val)
+			lookup[key] = append(lookup[key], row)
+		}
+	}
+
+	rows := []int{}
+	for _, label := range labels {
+		if df.index == nil {
+			row, ok := label.(int)
+			if !ok || row < 0 || row >= df.n {
+				return nil, fmt.Errorf("label not found: %v", label)
+			}
+			rows = append(rows, row)
+			continue
+		}
+
+		matches, exists := lookup[hashKey(label)]
+		if !exists {
+			// Labels of an int64 index can be provided as an int
+			if i, ok := label.(int); ok {
+				matches, exists = lookup[hashKey(int64(i))]
+			}
+			if !exists {
+				return nil, fmt.Errorf("label not found: %v", label)
+			}
+		}
+		rows = append(rows, matches...)
+	}
+
+	return df.take(ctx, rows)
+}
+
+// take returns a new DataFrame containing rows in the order provided. It does not lock the DataFrame.
+func (df *DataFrame) take(ctx context.Context, rows []int) (*DataFrame, error) {
+
+	seriess := make([]Series, 0, len(df.Series))
+	for _, s := range df.Series {
+		ns := newSeriesLike(s, s.Name(), &SeriesInit{Capacity: len(rows)})
+		for _, row := range rows {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			ns.Append(s.Value(row), dontLock)
+		}
+		seriess = append(seriess, ns)
+	}
+
+	ndf := NewDataFrame(seriess...)
+	ndf.n = len(rows)
+	ndf.index = df.indexRows(rows)
+
+	return ndf, nil
+}
+
+// indexRows returns a new index containing the labels of rows.
+// A row of -1 is given a nil label. If no index has been set, nil is returned.
+func (df *DataFrame) indexRows(rows []int) Series {
+	if df.index == nil {
+		return nil
+	}
+
+	idx := newSeriesLike(df.index, df.index.Name(), &SeriesInit{Capacity: len(rows)})
+	for _, row := range rows {
+		if row == -1 {
+			idx.Append(nil, dontLock)
+		} else {
+			idx.Append(df.index.Value(row), dontLock)
+		}
+	}
+	return idx
+}
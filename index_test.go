@@ -0,0 +1,144 @@
+// Copyright 2018-20 PJ Engineering and Business Solutions Pty. Ltd. All rights reserved.
+
+package dataframe
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestIndex(t *testing.T) {
+	ctx := context.Background()
+
+	df := NewDataFrame(
+		NewSeriesString("id", nil, "a", "b", "c", "d"),
+		NewSeriesInt64("sales", nil, 30, 10, nil, 20),
+	)
+
+	err := df.SetIndex("id")
+	if err != nil {
+		t.Fatalf("wrong err: expected: %v actual: %v", nil, err)
+	}
+
+	if len(df.Series) != 1 {
+		t.Errorf("wrong val: expected: %v actual: %v", 1, len(df.Series))
+	}
+
+	// Sort
+	df.Sort(ctx, []SortKey{{Key: "sales"}})
+
+	expectedIdx := NewSeriesString("id", nil, "c", "b", "d", "a")
+	if eq, _ := df.Index().IsEqual(ctx, expectedIdx); !eq {
+		t.Errorf("wrong val: expected: %v actual: %v", expectedIdx, df.Index())
+	}
+
+	// Filter
+	fdf, _ := Filter(ctx, df, FilterDataFrameFn(func(vals map[interface{}]interface{}, row, nRows int) (FilterAction, error) {
+		if vals["sales"] == nil {
+			return DROP, nil
+		}
+		return KEEP, nil
+	}))
+
+	expectedIdx = NewSeriesString("id", nil, "b", "d", "a")
+	if eq, _ := fdf.(*DataFrame).Index().IsEqual(ctx, expectedIdx); !eq {
+		t.Errorf("wrong val: expected: %v actual: %v", expectedIdx, fdf.(*DataFrame).Index())
+	}
+
+	// Copy
+	cp := df.Copy(RangeFinite(1, 2))
+	expectedIdx = NewSeriesString("id", nil, "b", "d")
+	if eq, _ := cp.Index().IsEqual(ctx, expectedIdx); !eq {
+		t.Errorf("wrong val: expected: %v actual: %v", expectedIdx, cp.Index())
+	}
+
+	// Loc
+	loc, err := df.Loc(ctx, []interface{}{"d", "a"})
+	if err != nil {
+		t.Fatalf("wrong err: expected: %v actual: %v", nil, err)
+	}
+
+	expected := NewDataFrame(NewSeriesInt64("sales", nil, 20, 30))
+	if eq, _ := loc.IsEqual(ctx, expected); !eq {
+		t.Errorf("wrong val: expected: %v actual: %v", expected.Table(), loc.Table())
+	}
+
+	if _, err := df.Loc(ctx, []interface{}{"z"}); err == nil {
+		t.Errorf("wrong err: expected: %v actual: %v", "error", err)
+	}
+
+	// Table
+	expectedTable := strings.TrimLeft(`
++-----+-------+
+| ID  | SALES |
++-----+-------+
+|  c  |  NaN  |
+|  b  |  10   |
+|  d  |  20   |
+|  a  |  30   |
++-----+-------+
+| 4X1 | INT64 |
++-----+-------+
+`, "\n")
+
+	if table := df.Table(); table != expectedTable {
+		t.Errorf("wrong val: expected: %v actual: %v", expectedTable, table)
+	}
+
+	// SetIndex replaces existing index
+	err = df.SetIndex(RangeIndex{Name: "row", Start: 1})
+	if err != nil {
+		t.Fatalf("wrong err: expected: %v actual: %v", nil, err)
+	}
+
+	loc, _ = df.Loc(ctx, []interface{}{1})
+	expected = NewDataFrame(
+		NewSeriesString("id", nil, "c"),
+		NewSeriesInt64("sales", nil, nil),
+	)
+	if eq, _ := loc.IsEqual(ctx, expected, IsEqualOptions{CheckName: true}); !eq {
+		t.Errorf("wrong val: expected: %v actual: %v", expected.Table(), loc.Table())
+	}
+
+	// ResetIndex
+	err = df.ResetIndex()
+	if err != nil {
+		t.Fatalf("wrong err: expected: %v actual: %v", nil, err)
+	}
+
+	expected = NewDataFrame(
+		NewSeriesInt64("row", nil, 1, 2, 3, 4),
+		NewSeriesString("id", nil, "c", "b", "d", "a"),
+		NewSeriesInt64("sales", nil, nil, 10, 20, 30),
+	)
+	if eq, _ := df.IsEqual(ctx, expected, IsEqualOptions{CheckName: true}); !eq || df.Index() != nil {
+		t.Errorf("wrong val: expected: %v actual: %v", expected.Table(), df.Table())
+	}
+
+	// SetIndex with a series of the DataFrame
+	df = NewDataFrame(
+		NewSeriesInt64("a", nil, 3, 1, 2),
+		NewSeriesString("b", nil, "c", "a", "b"),
+	)
+
+	err = df.SetIndex(df.Series[1])
+	if err != nil {
+		t.Fatalf("wrong err: expected: %v actual: %v", nil, err)
+	}
+
+	df.Sort(ctx, []SortKey{{Key: "a"}})
+
+	expected = NewDataFrame(
+		NewSeriesInt64("a", nil, 1, 2, 3),
+		NewSeriesString("b", nil, "a", "b", "c"),
+	)
+	if eq, _ := df.IsEqual(ctx, expected, IsEqualOptions{CheckName: true}); !eq {
+		t.Errorf("wrong val: expected: %v actual: %v", expected.Table(), df.Table())
+	}
+
+	expectedIdx = NewSeriesString("b", nil, "a", "b", "c")
+	if eq, _ := df.Index().IsEqual(ctx, expectedIdx); !eq {
+		t.Errorf("wrong val: expected: %v actual: %v", expectedIdx, df.Index())
+	}
+}
@@ -0,0 +1,489 @@
+// Copyright 2018-20 PJ Engineering and Business Solutions Pty. Ltd. All rights reserved.
+
+package dataframe
+
+import (
+	"context"
+	"errors"
+)
+
+// PredicateFn is used by LazyFrame's Filter to determine which rows are kept.
+// vals contains the values of the row keyed by series name. If the function returns false, the row is removed.
+type PredicateFn func(vals map[interface{}]interface{}) (bool, error)
+
+// WithColumnFn is used by LazyFrame's WithColumn to calculate the value of the new Series for each row.
+// vals contains the values of the row keyed by series name.
+type WithColumnFn func(vals map[interface{}]interface{}, row, nRows int) (interface{}, error)
+
+// ScanHints informs a LazySource which series and rows are required by a LazyFrame.
+type ScanHints struct {
+
+	// Columns lists the names of the series that are required.
+	// If nil, all series are required.
+	Columns []string
+
+	// Predicate, if not nil, determines which rows are required.
+	Predicate PredicateFn
+
+	// Limit, if not nil, is the maximum number of rows (after Predicate is applied) that are required.
+	Limit *int
+}
+
+// Keep returns true if vals satisfies the Predicate. vals must be keyed by series name.
+func (h ScanHints) Keep(vals map[interface{}]interface{}) (bool, error) {
+	if h.Predicate == nil {
+		return true, nil
+	}
+	return h.Predicate(vals)
+}
+
+// NeedsColumn returns true if the series named name is required.
+func (h ScanHints) NeedsColumn(name string) bool {
+	if h.Columns == nil {
+		return true
+	}
+	for _, c := range h.Columns {
+		if c == name {
+			return true
+		}
+	}
+	return false
+}
+
+// LazySource provides the DataFrame that a LazyFrame operates on.
+// The returned DataFrame is owned by the LazyFrame and will be modified.
+//
+// Implementations can use hints to avoid loading series and rows that are not required.
+// Loading additional series is permitted. If the Predicate and Limit hints were both applied,
+// applied must be true. Otherwise they will be applied after Scan returns.
+type LazySource interface {
+	Scan(ctx context.Context, hints ScanHints) (df *DataFrame, applied bool, err error)
+}
+
+type lazyOpKind int
+
+const (
+	lazySelect lazyOpKind = iota
+	lazyFilter
+	lazyWithColumn
+	lazySort
+	lazyGroupBy
+	lazyLimit
+)
+
+type lazyOp struct {
+	kind lazyOpKind
+
+	// cols lists the series that the operation depends on. nil means unknown.
+	cols []string
+
+	pred      PredicateFn
+	series    Series
+	fn        WithColumnFn
+	sortKeys  []SortKey
+	groupKeys []interface{}
+	aggs      []Aggregation
+	limit     int
+}
+
+// LazyFrame records a plan of operations to be performed on a DataFrame. Nothing is executed until Collect is called.
+// When the plan is collected, filters are pushed down to the source when possible and series that are not
+// required are not loaded. Operations are then performed on a single DataFrame without creating intermediate copies.
+//
+// Each method returns a new LazyFrame so a plan can be reused as the basis of multiple plans.
+//
+// Example:
+//
+//  df, err := dataframe.NewLazyFrame(src).
+//     Filter(func(vals map[interface{}]interface{}) (bool, error) {
+//        return vals["age"] != nil && vals["age"].(int64) > 30, nil
+//     }, "age").
+//     Sort(dataframe.SortKey{Key: "age", Desc: true}).
+//     Select("name", "age").
+//     Limit(10).
+//     Collect(ctx)
+//
+type LazyFrame struct {
+	src LazySource
+	ops []lazyOp
+}
+
+// NewLazyFrame creates a new LazyFrame that operates on the DataFrame provided by src.
+//
+// See: imports.LazyCSV and imports.LazySQL
+func NewLazyFrame(src LazySource) *LazyFrame {
+	return &LazyFrame{src: src}
+}
+
+// Lazy returns a LazyFrame that operates on the DataFrame.
+// The DataFrame is not modified. Instead Collect returns a new DataFrame.
+func (df *DataFrame) Lazy() *LazyFrame {
+	return NewLazyFrame(&dataFrameSource{df})
+}
+
+func (lf *LazyFrame) with(op lazyOp) *LazyFrame {
+	ops := make([]lazyOp, len(lf.ops), len(lf.ops)+1)
+	copy(ops, lf.ops)
+	return &LazyFrame{src: lf.src, ops: append(ops, op)}
+}
+
+// Select restricts (and reorders) the series to those named.
+func (lf *LazyFrame) Select(names ...string) *LazyFrame {
+	return lf.with(lazyOp{kind: lazySelect, cols: names})
+}
+
+// Filter removes rows for which fn returns false.
+// cols should list the series that fn depends on. If cols is not provided, all series are assumed to be required.
+func (lf *LazyFrame) Filter(fn PredicateFn, cols ...string) *LazyFrame {
+	return lf.with(lazyOp{kind: lazyFilter, pred: fn, cols: nilIfEmpty(cols)})
+}
+
+// WithColumn adds a Series with values calculated by fn. s determines the name and type of the new Series and must
+// implement NewSerieser. If a Series with the same name exists, it is replaced.
+// cols should list the series that fn depends on. If cols is not provided, all series are assumed to be required.
+func (lf *LazyFrame) WithColumn(s Series, fn WithColumnFn, cols ...string) *LazyFrame {
+	return lf.with(lazyOp{kind: lazyWithColumn, series: s, fn: fn, cols: nilIfEmpty(cols)})
+}
+
+// Sort sorts the rows. See DataFrame's Sort.
+func (lf *LazyFrame) Sort(keys ...SortKey) *LazyFrame {
+	cols := []string{}
+	for _, k := range keys {
+		name, ok := k.Key.(string)
+		if !ok {
+			cols = nil
+			break
+		}
+		cols = append(cols, name)
+	}
+	return lf.with(lazyOp{kind: lazySort, sortKeys: keys, cols: cols})
+}
+
+// GroupBy groups the rows by keys and replaces the DataFrame with the result of aggs. See GroupBy and Aggregate.
+func (lf *LazyFrame) GroupBy(keys []interface{}, aggs ...Aggregation) *LazyFrame {
+	cols := []string{}
+	for _, k := range keys {
+		name, ok := k.(string)
+		if !ok {
+			cols = nil
+			break
+		}
+		cols = append(cols, name)
+	}
+	if cols != nil {
+		for _, agg := range aggs {
+			name, ok := agg.Key.(string)
+			if !ok {
+				cols = nil
+				break
+			}
+			cols = append(cols, name)
+		}
+	}
+	return lf.with(lazyOp{kind: lazyGroupBy, groupKeys: keys, aggs: aggs, cols: cols})
+}
+
+// Limit restricts the number of rows to at most n.
+func (lf *LazyFrame) Limit(n int) *LazyFrame {
+	if n < 0 {
+		n = 0
+	}
+	return lf.with(lazyOp{kind: lazyLimit, limit: n})
+}
+
+// Collect executes the plan and returns the resulting DataFrame.
+func (lf *LazyFrame) Collect(ctx context.Context) (*DataFrame, error) {
+
+	hints, ops := lf.optimize()
+
+	df, applied, err := lf.src.Scan(ctx, hints)
+	if err != nil {
+		return nil, err
+	}
+
+	if !applied {
+		if hints.Predicate != nil {
+			if err := lazyApplyFilter(ctx, df, hints.Predicate); err != nil {
+				return nil, err
+			}
+		}
+		if hints.Limit != nil {
+			lazyApplyLimit(df, *hints.Limit)
+		}
+	}
+
+	if hints.Columns != nil {
+		// The source may have loaded series that are not required
+		seriess := []Series{}
+		for _, s := range df.Series {
+			if hints.NeedsColumn(s.Name(dontLock)) {
+				seriess = append(seriess, s)
+			}
+		}
+		df.Series = seriess
+	}
+
+	for _, op := range ops {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		switch op.kind {
+		case lazySelect:
+			seriess := make([]Series, 0, len(op.cols))
+			for _, name := range op.cols {
+				col, err := df.NameToColumn(name, dontLock)
+				if err != nil {
+					return nil, err
+				}
+				seriess = append(seriess, df.Series[col])
+			}
+			df.Series = seriess
+		case lazyFilter:
+			if err := lazyApplyFilter(ctx, df, op.pred); err != nil {
+				return nil, err
+			}
+		case lazyWithColumn:
+			if err := lazyApplyWithColumn(ctx, df, op.series, op.fn); err != nil {
+				return nil, err
+			}
+		case lazySort:
+			if !df.Sort(ctx, op.sortKeys, SortOptions{DontLock: true}) {
+				return nil, ctx.Err()
+			}
+		case lazyGroupBy:
+			g, err := GroupBy(ctx, df, op.groupKeys, GroupByOptions{DontLock: true})
+			if err != nil {
+				return nil, err
+			}
+			df, err = g.Aggregate(ctx, op.aggs...)
+			if err != nil {
+				return nil, err
+			}
+		case lazyLimit:
+			lazyApplyLimit(df, op.limit)
+		}
+	}
+
+	return df, nil
+}
+
+// optimize determines which filters and limits can be pushed down to the source and which series are required.
+// The remaining operations are returned.
+func (lf *LazyFrame) optimize() (ScanHints, []lazyOp) {
+
+	var (
+		hints      ScanHints
+		predicates []lazyOp
+		ops        []lazyOp
+		barrier    bool // Filters can not be moved before a Limit or GroupBy
+		newCols    = map[string]bool{}
+	)
+
+	// Predicate pushdown
+	for _, op := range lf.ops {
+		switch op.kind {
+		case lazyFilter:
+			if !barrier && !dependsOn(op.cols, newCols) {
+				predicates = append(predicates, op)
+				continue
+			}
+		case lazyWithColumn:
+			newCols[op.series.Name()] = true
+		case lazyLimit, lazyGroupBy:
+			barrier = true
+		}
+		ops = append(ops, op)
+	}
+
+	if len(predicates) > 0 {
+		hints.Predicate = func(vals map[interface{}]interface{}) (bool, error) {
+			for _, p := range predicates {
+				keep, err := p.pred(vals)
+				if err != nil || !keep {
+					return false, err
+				}
+			}
+			return true, nil
+		}
+	}
+
+	// Limit pushdown
+	for _, op := range ops {
+		if op.kind == lazyLimit {
+			hints.Limit = &[]int{op.limit}[0]
+		}
+		if op.kind != lazySelect {
+			break
+		}
+	}
+
+	// Projection pushdown
+	var needed map[string]bool // nil means all series are required
+	for i := len(ops) - 1; i >= 0; i-- {
+		op := ops[i]
+		switch op.kind {
+		case lazySelect, lazyGroupBy:
+			// Only the series used are required by subsequent operations
+			needed = map[string]bool{}
+			if op.cols == nil {
+				needed = nil
+			}
+			needed = addCols(needed, op.cols)
+		case lazyWithColumn:
+			if needed != nil {
+				delete(needed, op.series.Name())
+			}
+			needed = addCols(needed, op.cols)
+		case lazyFilter, lazySort:
+			needed = addCols(needed, op.cols)
+		}
+	}
+
+	for _, op := range predicates {
+		needed = addCols(needed, op.cols)
+	}
+
+	if needed != nil {
+		hints.Columns = []string{}
+		for name := range needed {
+			hints.Columns = append(hints.Columns, name)
+		}
+	}
+
+	return hints, ops
+}
+
+// dependsOn returns true if any of cols are in names. If cols is nil, it returns true if names is not empty.
+func dependsOn(cols []string, names map[string]bool) bool {
+	if cols == nil {
+		return len(names) > 0
+	}
+	for _, c := range cols {
+		if names[c] {
+			return true
+		}
+	}
+	return false
+}
+
+// addCols adds cols to needed. If cols is nil, all series are required so nil is returned.
+func addCols(needed map[string]bool, cols []string) map[string]bool {
+	if needed == nil || cols == nil {
+		return nil
+	}
+	for _, c := range cols {
+		needed[c] = true
+	}
+	return needed
+}
+
+func nilIfEmpty(cols []string) []string {
+	if len(cols) == 0 {
+		return nil
+	}
+	return cols
+}
+
+func lazyApplyFilter(ctx context.Context, df *DataFrame, fn PredicateFn) error {
+
+	mask := make([]bool, df.n)
+	for row := 0; row < df.n; row++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		keep, err := fn(df.Row(row, true, SeriesName))
+		if err != nil {
+			return &RowError{Row: row, Err: err}
+		}
+		mask[row] = keep
+	}
+
+	_, err := FilterMask(ctx, df, mask, FilterOptions{InPlace: true, DontLock: true})
+	return err
+}
+
+func lazyApplyWithColumn(ctx context.Context, df *DataFrame, s Series, fn WithColumnFn) error {
+
+	if _, ok := s.(NewSerieser); !ok {
+		return errors.New("s must implement NewSerieser interface")
+	}
+
+	name := s.Name()
+	ns := newSeriesLike(s, name, &SeriesInit{Capacity: df.n})
+
+	for row := 0; row < df.n; row++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		val, err := fn(df.Row(row, true, SeriesName), row, df.n)
+		if err != nil {
+			return &RowError{Row: row, Err: err}
+		}
+		ns.Append(val, dontLock)
+	}
+
+	if col, err := df.NameToColumn(name, dontLock); err == nil {
+		df.Series[col] = ns
+		return nil
+	}
+
+	return df.AddSeries(ns, nil, dontLock)
+}
+
+func lazyApplyLimit(df *DataFrame, n int) {
+	for row := df.n - 1; row >= n; row-- {
+		df.Remove(row, dontLock)
+	}
+}
+
+// dataFrameSource is a LazySource for an existing DataFrame.
+type dataFrameSource struct {
+	df *DataFrame
+}
+
+func (src *dataFrameSource) Scan(ctx context.Context, hints ScanHints) (*DataFrame, bool, error) {
+
+	df := src.df
+
+	df.lock.RLock()
+	defer df.lock.RUnlock()
+
+	rows := []int{}
+	for row := 0; row < df.n; row++ {
+		if err := ctx.Err(); err != nil {
+			return nil, false, err
+		}
+
+		if hints.Limit != nil && len(rows) >= *hints.Limit {
+			break
+		}
+
+		if hints.Predicate != nil {
+			keep, err := hints.Predicate(df.Row(row, true, SeriesName))
+			if err != nil {
+				return nil, false, &RowError{Row: row, Err: err}
+			}
+			if !keep {
+				continue
+			}
+		}
+		rows = append(rows, row)
+	}
+
+	// Only copy the required series
+	projected := &DataFrame{n: df.n, index: df.index}
+	for _, s := range df.Series {
+		if hints.NeedsColumn(s.Name(dontLock)) {
+			projected.Series = append(projected.Series, s)
+		}
+	}
+
+	ndf, err := projected.take(ctx, rows)
+	if err != nil {
+		return nil, false, err
+	}
+	return ndf, true, nil
+}
@@ -0,0 +1,111 @@
+// Copyright 2018-20 PJ Engineering and Business Solutions Pty. Ltd. All rights reserved.
+
+package dataframe
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+type hintsSource struct {
+	df    *DataFrame
+	hints ScanHints
+}
+
+func (src *hintsSource) Scan(ctx context.Context, hints ScanHints) (*DataFrame, bool, error) {
+	src.hints = hints
+	return src.df.Copy(), false, nil
+}
+
+func TestLazyFrame(t *testing.T) {
+	ctx := context.Background()
+
+	df := NewDataFrame(
+		NewSeriesString("name", nil, "a", "b", "c", "d", "e"),
+		NewSeriesString("country", nil, "AU", "NZ", "AU", "AU", "NZ"),
+		NewSeriesInt64("age", nil, 35, 28, nil, 41, 52),
+		NewSeriesFloat64("score", nil, 1.0, 2.0, 3.0, 4.0, 5.0),
+	)
+
+	olderThan30 := func(vals map[interface{}]interface{}) (bool, error) {
+		return vals["age"] != nil && vals["age"].(int64) > 30, nil
+	}
+
+	src := &hintsSource{df: df}
+	lf := NewLazyFrame(src).
+		WithColumn(NewSeriesInt64("age2", nil), func(vals map[interface{}]interface{}, row, nRows int) (interface{}, error) {
+			if vals["age"] == nil {
+				return nil, nil
+			}
+			return vals["age"].(int64) * 2, nil
+		}, "age").
+		Filter(olderThan30, "age").
+		Sort(SortKey{Key: "age2", Desc: true}).
+		Select("name", "age2")
+
+	out, err := lf.Collect(ctx)
+	if err != nil {
+		t.Fatalf("wrong err: expected: %v actual: %v", nil, err)
+	}
+
+	expected := NewDataFrame(
+		NewSeriesString("name", nil, "e", "d", "a"),
+		NewSeriesInt64("age2", nil, 104, 82, 70),
+	)
+	if eq, _ := out.IsEqual(ctx, expected, IsEqualOptions{CheckName: true}); !eq {
+		t.Errorf("wrong val: expected: %v actual: %v", expected.Table(), out.Table())
+	}
+
+	// Filter is pushed down and only the required series are loaded
+	sort.Strings(src.hints.Columns)
+	if !cmp.Equal(src.hints.Columns, []string{"age", "name"}) {
+		t.Errorf("wrong val: expected: %v actual: %v", []string{"age", "name"}, src.hints.Columns)
+	}
+	if src.hints.Predicate == nil || src.hints.Limit != nil {
+		t.Errorf("wrong val: expected pushed predicate")
+	}
+
+	// A filter after Limit is not pushed down
+	lf = NewLazyFrame(src).Select("name", "age").Limit(3).Filter(olderThan30)
+	out, err = lf.Collect(ctx)
+	if err != nil {
+		t.Fatalf("wrong err: expected: %v actual: %v", nil, err)
+	}
+
+	expected = NewDataFrame(
+		NewSeriesString("name", nil, "a"),
+		NewSeriesInt64("age", nil, 35),
+	)
+	if eq, _ := out.IsEqual(ctx, expected, IsEqualOptions{CheckName: true}); !eq {
+		t.Errorf("wrong val: expected: %v actual: %v", expected.Table(), out.Table())
+	}
+
+	if src.hints.Predicate != nil || src.hints.Limit == nil || *src.hints.Limit != 3 {
+		t.Errorf("wrong val: expected pushed limit")
+	}
+
+	// GroupBy on an existing DataFrame
+	out, err = df.Lazy().
+		Filter(olderThan30, "age").
+		GroupBy([]interface{}{"country"}, Aggregation{Key: "score", Func: AggSum}).
+		Collect(ctx)
+	if err != nil {
+		t.Fatalf("wrong err: expected: %v actual: %v", nil, err)
+	}
+
+	expected = NewDataFrame(
+		NewSeriesString("country", nil, "AU", "NZ"),
+		NewSeriesFloat64("score_sum", nil, 5.0, 5.0),
+	)
+	if eq, _ := out.IsEqual(ctx, expected, IsEqualOptions{CheckName: true}); !eq {
+		t.Errorf("wrong val: expected: %v actual: %v", expected.Table(), out.Table())
+	}
+
+	// Original DataFrame is unmodified
+	if df.NRows() != 5 || len(df.Series) != 4 {
+		t.Errorf("wrong val: expected: %v actual: %v", 5, df.NRows())
+	}
+}
@@ -0,0 +1,433 @@
+package funcs
+
+import (
+	"context"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"math"
+	"strconv"
+	"strings"
+
+	dataframe "github.com/rocketlaunchr/dataframe-go"
+	"github.com/sandertv/go-formula/v2"
+)
+
+// FilterExprOptions modifies the behavior of the FilterExpr function.
+type FilterExprOptions struct {
+
+	// CustomFns adds custom functions to be used within expr.
+	CustomFns map[string]func(args ...float64) float64
+
+	// InPlace will perform the filter operation on the current DataFrame.
+	// If InPlace is not set, a new DataFrame will be returned with the selected rows.
+	// The original DataFrame will be unmodified.
+	InPlace bool
+
+	// DontLock can be set to true if the DataFrame should not be locked.
+	DontLock bool
+}
+
+// FilterExpr is used to select rows in a DataFrame using a boolean expression.
+// Rows where expr evaluates to true are kept.
+//
+// Comparisons (==, !=, <, <=, >, >=) can be combined with &&, || and !. Numeric operands are
+// evaluated using the same formula parsing as PiecewiseFunc, so functions from the math package can be used.
+// The variables used in expr must correspond to the Series' names in the DataFrame.
+// Strings can be quoted with single or double quotes. A comparison involving a nil value is false.
+//
+// If the InPlace option is set, the function returns nil. Instead the DataFrame is modified "in place".
+//
+// Example:
+//
+//  fdf, _ := funcs.FilterExpr(ctx, df, "age > 30 && country == 'AU'")
+//
+func FilterExpr(ctx context.Context, df *dataframe.DataFrame, expr string, opts ...FilterExprOptions) (*dataframe.DataFrame, error) {
+
+	if len(opts) == 0 {
+		opts = append(opts, FilterExprOptions{})
+	}
+
+	if !opts[0].DontLock {
+		df.Lock()
+		defer df.Unlock()
+	}
+
+	src := quoteStrings(expr)
+
+	e, err := parser.ParseExpr(src)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing expr: \"%s\" err: %v", expr, err)
+	}
+
+	c := &exprCompiler{src: src, df: df, customFns: opts[0].CustomFns}
+	eval, err := c.compileBool(e)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing expr: \"%s\" err: %v", expr, err)
+	}
+
+	nRows := df.NRows(dataframe.DontLock)
+	mask := make([]bool, nRows)
+	for row := 0; row < nRows; row++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		mask[row], err = eval(row)
+		if err != nil {
+			return nil, &dataframe.RowError{Row: row, Err: err}
+		}
+	}
+
+	out, err := dataframe.FilterMask(ctx, df, mask, dataframe.FilterOptions{InPlace: opts[0].InPlace, DontLock: true})
+	if out == nil {
+		return nil, err
+	}
+	return out.(*dataframe.DataFrame), err
+}
+
+// quoteStrings converts single-quoted strings to double-quoted strings so that expr can be parsed as a Go expression.
+func quoteStrings(expr string) string {
+
+	var (
+		out    strings.Builder
+		inStr  bool
+		quoted []rune
+	)
+
+	runes := []rune(expr)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+
+		switch {
+		case inStr && r == '\\' && i+1 < len(runes):
+			i++
+			quoted = append(quoted, runes[i])
+		case inStr && r == '\'':
+			out.WriteString(strconv.Quote(string(quoted)))
+			inStr = false
+		case inStr:
+			quoted = append(quoted, r)
+		case r == '\'':
+			inStr = true
+			quoted = quoted[:0]
+		case r == '"':
+			// Copy double-quoted strings verbatim
+			out.WriteRune(r)
+			for i++; i < len(runes); i++ {
+				out.WriteRune(runes[i])
+				if runes[i] == '\\' && i+1 < len(runes) {
+					i++
+					out.WriteRune(runes[i])
+				} else if runes[i] == '"' {
+					break
+				}
+			}
+		default:
+			out.WriteRune(r)
+		}
+	}
+
+	if inStr {
+		// Unterminated string is left for the parser to report
+		out.WriteRune('\'')
+		out.WriteString(string(quoted))
+	}
+
+	return out.String()
+}
+
+type exprKind int
+
+const (
+	kindNumeric exprKind = iota
+	kindString
+	kindBool
+)
+
+// valueFn returns the value of an operand at a particular row.
+// The value is a float64 (NaN when nil), string or bool. A nil string or bool is returned as nil.
+type valueFn func(row int) (interface{}, error)
+
+type exprCompiler struct {
+	src       string
+	df        *dataframe.DataFrame
+	customFns map[string]func(args ...float64) float64
+}
+
+// column returns the Series named name and its kind.
+func (c *exprCompiler) column(name string) (dataframe.Series, exprKind, bool, error) {
+	col, err := c.df.NameToColumn(name, dataframe.DontLock)
+	if err != nil {
+		return nil, 0, false, nil
+	}
+
+	s := c.df.Series[col]
+	switch s.(type) {
+	case *dataframe.SeriesInt64, *dataframe.SeriesFloat64:
+		return s, kindNumeric, true, nil
+	case *dataframe.SeriesString, *dataframe.SeriesCategorical:
+		return s, kindString, true, nil
+	case *dataframe.SeriesBool:
+		return s, kindBool, true, nil
+	default:
+		return nil, 0, false, fmt.Errorf("unsupported series type for %s: %s", name, s.Type())
+	}
+}
+
+func (c *exprCompiler) compileBool(e ast.Expr) (func(row int) (bool, error), error) {
+
+	switch expr := e.(type) {
+	case *ast.ParenExpr:
+		return c.compileBool(expr.X)
+	case *ast.UnaryExpr:
+		if expr.Op != token.NOT {
+			break
+		}
+		x, err := c.compileBool(expr.X)
+		if err != nil {
+			return nil, err
+		}
+		return func(row int) (bool, error) {
+			v, err := x(row)
+			return !v, err
+		}, nil
+	case *ast.BinaryExpr:
+		switch expr.Op {
+		case token.LAND, token.LOR:
+			x, err := c.compileBool(expr.X)
+			if err != nil {
+				return nil, err
+			}
+			y, err := c.compileBool(expr.Y)
+			if err != nil {
+				return nil, err
+			}
+			isAnd := expr.Op == token.LAND
+			return func(row int) (bool, error) {
+				v, err := x(row)
+				if err != nil || v != isAnd {
+					// Short-circuit
+					return v, err
+				}
+				return y(row)
+			}, nil
+		case token.EQL, token.NEQ, token.LSS, token.LEQ, token.GTR, token.GEQ:
+			return c.compileComparison(expr)
+		}
+	case *ast.Ident:
+		x, kind, err := c.compileValue(expr)
+		if err != nil {
+			return nil, err
+		}
+		if kind != kindBool {
+			break
+		}
+		return func(row int) (bool, error) {
+			v, err := x(row)
+			if v == nil {
+				return false, err
+			}
+			return v.(bool), err
+		}, nil
+	}
+
+	return nil, fmt.Errorf("expression does not evaluate to a boolean: %s", c.source(e))
+}
+
+func (c *exprCompiler) compileComparison(expr *ast.BinaryExpr) (func(row int) (bool, error), error) {
+
+	x, xKind, err := c.compileValue(expr.X)
+	if err != nil {
+		return nil, err
+	}
+	y, yKind, err := c.compileValue(expr.Y)
+	if err != nil {
+		return nil, err
+	}
+
+	if xKind != yKind {
+		return nil, fmt.Errorf("mismatched types in comparison: %s", c.source(expr))
+	}
+
+	if xKind == kindBool && expr.Op != token.EQL && expr.Op != token.NEQ {
+		return nil, fmt.Errorf("booleans can only be compared for equality: %s", c.source(expr))
+	}
+
+	return func(row int) (bool, error) {
+		xv, err := x(row)
+		if err != nil {
+			return false, err
+		}
+		yv, err := y(row)
+		if err != nil {
+			return false, err
+		}
+
+		var cmp int
+		switch xv := xv.(type) {
+		case nil:
+			return false, nil
+		case float64:
+			yv := yv.(float64)
+			if math.IsNaN(xv) || math.IsNaN(yv) {
+				return false, nil
+			}
+			if xv < yv {
+				cmp = -1
+			} else if xv > yv {
+				cmp = 1
+			}
+		case string:
+			if yv == nil {
+				return false, nil
+			}
+			cmp = strings.Compare(xv, yv.(string))
+		case bool:
+			if yv == nil {
+				return false, nil
+			}
+			if xv != yv.(bool) {
+				cmp = 1
+			}
+		}
+
+		switch expr.Op {
+		case token.EQL:
+			return cmp == 0, nil
+		case token.NEQ:
+			return cmp != 0, nil
+		case token.LSS:
+			return cmp < 0, nil
+		case token.LEQ:
+			return cmp <= 0, nil
+		case token.GTR:
+			return cmp > 0, nil
+		default:
+			return cmp >= 0, nil
+		}
+	}, nil
+}
+
+func (c *exprCompiler) compileValue(e ast.Expr) (valueFn, exprKind, error) {
+
+	switch expr := e.(type) {
+	case *ast.BasicLit:
+		if expr.Kind == token.STRING {
+			str, err := strconv.Unquote(expr.Value)
+			if err != nil {
+				return nil, 0, err
+			}
+			return func(row int) (interface{}, error) { return str, nil }, kindString, nil
+		}
+	case *ast.Ident:
+		s, kind, found, err := c.column(expr.Name)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		if !found {
+			if b, err := strconv.ParseBool(expr.Name); err == nil {
+				return func(row int) (interface{}, error) { return b, nil }, kindBool, nil
+			}
+			break
+		}
+
+		if kind != kindNumeric {
+			return func(row int) (interface{}, error) { return s.Value(row, dataframe.DontLock), nil }, kind, nil
+		}
+	case *ast.ParenExpr:
+		x, kind, err := c.compileValue(expr.X)
+		if err != nil || kind != kindNumeric {
+			return x, kind, err
+		}
+	}
+
+	return c.compileFormula(e)
+}
+
+// compileFormula parses a numeric expression using the formula package.
+func (c *exprCompiler) compileFormula(e ast.Expr) (valueFn, exprKind, error) {
+
+	src := c.source(e)
+
+	f, err := formula.New(src)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	for k, v := range c.customFns {
+		f.RegisterFunc(k, 0, v)
+	}
+
+	// Find Series used as variables
+	vars := []dataframe.Series{}
+	var inspectErr error
+	ast.Inspect(e, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.CallExpr:
+			// Don't treat function names as variables
+			for _, arg := range n.Args {
+				ast.Inspect(arg, func(n ast.Node) bool {
+					if ident, ok := n.(*ast.Ident); ok {
+						inspectErr = c.addVar(&vars, ident.Name)
+					}
+					return inspectErr == nil
+				})
+			}
+			return false
+		case *ast.Ident:
+			inspectErr = c.addVar(&vars, n.Name)
+		}
+		return inspectErr == nil
+	})
+	if inspectErr != nil {
+		return nil, 0, inspectErr
+	}
+
+	variables := make([]formula.Variable, len(vars))
+
+	return func(row int) (interface{}, error) {
+		for i, s := range vars {
+			switch v := s.Value(row, dataframe.DontLock).(type) {
+			case nil:
+				return math.NaN(), nil
+			case int64:
+				variables[i] = formula.Var(s.Name(dataframe.DontLock), v)
+			case float64:
+				variables[i] = formula.Var(s.Name(dataframe.DontLock), v)
+			}
+		}
+
+		val, err := f.Eval(variables...)
+		if err != nil {
+			return nil, err
+		}
+		return val, nil
+	}, kindNumeric, nil
+}
+
+// addVar adds the Series named name to vars if it exists and has not already been added.
+func (c *exprCompiler) addVar(vars *[]dataframe.Series, name string) error {
+	s, kind, found, err := c.column(name)
+	if err != nil || !found {
+		return err
+	}
+
+	if kind != kindNumeric {
+		return fmt.Errorf("%s can not be used in a numeric expression", name)
+	}
+
+	for _, v := range *vars {
+		if v == s {
+			return nil
+		}
+	}
+	*vars = append(*vars, s)
+	return nil
+}
+
+func (c *exprCompiler) source(e ast.Expr) string {
+	return c.src[e.Pos()-1 : e.End()-1]
+}
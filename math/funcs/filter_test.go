@@ -0,0 +1,62 @@
+package funcs
+
+import (
+	"testing"
+
+	dataframe "github.com/rocketlaunchr/dataframe-go"
+)
+
+func TestFilterExpr(t *testing.T) {
+
+	df := dataframe.NewDataFrame(
+		dataframe.NewSeriesString("name", nil, "Alice", "Bob", "Carol", "Dan", "Eve"),
+		dataframe.NewSeriesInt64("age", nil, 35, 28, nil, 41, 33),
+		dataframe.NewSeriesCategorical("country", nil, "AU", "AU", "AU", "NZ", "AU"),
+		dataframe.NewSeriesBool("active", nil, true, true, true, false, false),
+	)
+
+	tests := []struct {
+		expr     string
+		expected []string
+	}{
+		{"age > 30 && country == 'AU'", []string{"Alice", "Eve"}},
+		{"age >= 2*20 || name == \"Bob\"", []string{"Bob", "Dan"}},
+		{"!(age > 30) && active", []string{"Bob", "Carol"}},
+		{"active == false && sqrt(age) < 6", []string{"Eve"}},
+		{"name < 'C' || age % 2 == 1", []string{"Alice", "Bob", "Dan", "Eve"}},
+		{"country != 'AU'", []string{"Dan"}},
+	}
+
+	for i, tc := range tests {
+		fdf, err := FilterExpr(ctx, df, tc.expr)
+		if err != nil {
+			t.Fatalf("%d: wrong err: expected: %v actual: %v", i, nil, err)
+		}
+
+		expected := dataframe.NewSeriesString("name", nil)
+		for _, name := range tc.expected {
+			expected.Append(name)
+		}
+
+		if eq, _ := fdf.Series[0].IsEqual(ctx, expected); !eq {
+			t.Errorf("%d: wrong val: expected: %v actual: %v", i, expected, fdf.Series[0])
+		}
+	}
+
+	// Errors
+	for _, expr := range []string{"age +", "age + 1", "name > 3", "active < true", "name * 2 > 1"} {
+		if _, err := FilterExpr(ctx, df, expr); err == nil {
+			t.Errorf("wrong err: expected: %v actual: %v", "error", err)
+		}
+	}
+
+	// InPlace
+	fdf, err := FilterExpr(ctx, df, "age < 30 || age > 40", FilterExprOptions{InPlace: true})
+	if err != nil || fdf != nil {
+		t.Fatalf("wrong err: expected: %v actual: %v", nil, err)
+	}
+
+	if df.NRows() != 2 {
+		t.Errorf("wrong val: expected: %v actual: %v", 2, df.NRows())
+	}
+}
@@ -0,0 +1,339 @@
+// Copyright 2018-20 PJ Engineering and Business Solutions Pty. Ltd. All rights reserved.
+
+package dataframe
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// JoinType determines which rows are kept by Merge.
+type JoinType int
+
+const (
+	// InnerJoin keeps rows whose keys exist in both DataFrames.
+	InnerJoin JoinType = iota
+
+	// LeftJoin keeps all rows of the left DataFrame.
+	LeftJoin
+
+	// RightJoin keeps all rows of the right DataFrame.
+	RightJoin
+
+	// OuterJoin keeps all rows of both DataFrames.
+	OuterJoin
+
+	// SemiJoin keeps rows of the left DataFrame whose keys exist in the right DataFrame.
+	// Only the Series of the left DataFrame are returned.
+	SemiJoin
+
+	// AntiJoin keeps rows of the left DataFrame whose keys do not exist in the right DataFrame.
+	// Only the Series of the left DataFrame are returned.
+	AntiJoin
+)
+
+// MergeOptions modifies the behavior of the Merge function.
+type MergeOptions struct {
+
+	// On sets the key Series that are found in both DataFrames.
+	// An index of the Series or the name of the Series can be provided.
+	// The key Series appear only once in the returned DataFrame.
+	On []interface{}
+
+	// LeftOn and RightOn set the key Series when they differ between DataFrames.
+	// They are ignored if On is set. Both sets of key Series appear in the returned DataFrame.
+	LeftOn  []interface{}
+	RightOn []interface{}
+
+	// Suffixes are appended to the names of non-key Series that exist in both DataFrames.
+	// If not set, it defaults to "_x" and "_y".
+	Suffixes *[2]string
+
+	// MatchNil can be set so that nil keys are considered equal.
+	// By default, rows with nil keys never match.
+	MatchNil bool
+
+	// DontLock can be set to true if the DataFrames should not be locked.
+	DontLock bool
+}
+
+// Merge combines 2 DataFrames based on the values of key Series, similar to a SQL join.
+// It uses a hash-join so the keys do not need to be sorted. Rows of the returned DataFrame
+// follow the order of the left DataFrame, except for RightJoin where the order of the right
+// DataFrame is used. Unmatched rows of the right DataFrame are placed at the end for OuterJoin.
+//
+// The key Series must have the same type in both DataFrames. All Series must implement the NewSerieser
+// interface, otherwise a SeriesMixed is used.
+//
+// Example:
+//
+//  df, err := dataframe.Merge(ctx, left, right, dataframe.LeftJoin, dataframe.MergeOptions{On: []interface{}{"id"}})
+//
+func Merge(ctx context.Context, left, right *DataFrame, how JoinType, opts ...MergeOptions) (*DataFrame, error) {
+
+	if len(opts) == 0 {
+		opts = append(opts, MergeOptions{})
+	}
+
+	if !opts[0].DontLock {
+		left.lock.RLock()
+		defer left.lock.RUnlock()
+		if right != left {
+			right.lock.RLock()
+			defer right.lock.RUnlock()
+		}
+	}
+
+	suffixes := [2]string{"_x", "_y"}
+	if opts[0].Suffixes != nil {
+		suffixes = *opts[0].Suffixes
+	}
+
+	// Determine key series
+	leftOn, rightOn := opts[0].LeftOn, opts[0].RightOn
+	shared := len(opts[0].On) > 0
+	if shared {
+		leftOn, rightOn = opts[0].On, opts[0].On
+	}
+
+	if len(leftOn) == 0 || len(leftOn) != len(rightOn) {
+		return nil, errors.New("key Series must be provided for both DataFrames")
+	}
+
+	lKeys := make([]int, 0, len(leftOn))
+	rKeys := make([]int, 0, len(rightOn))
+	for i := range leftOn {
+		lCol, err := left.keyToColumn(leftOn[i])
+		if err != nil {
+			return nil, err
+		}
+		rCol, err := right.keyToColumn(rightOn[i])
+		if err != nil {
+			return nil, err
+		}
+		if left.Series[lCol].Type() != right.Series[rCol].Type() {
+			return nil, fmt.Errorf("key Series have different types: %s and %s", left.Series[lCol].Type(), right.Series[rCol].Type())
+		}
+		lKeys = append(lKeys, lCol)
+		rKeys = append(rKeys, rCol)
+	}
+
+	// Determine which rows are joined. A value of -1 signifies no matching row.
+	var pairs [][2]int
+
+	switch how {
+	case InnerJoin, LeftJoin, OuterJoin, SemiJoin, AntiJoin:
+		table, err := buildHashTable(ctx, right, rKeys, opts[0].MatchNil)
+		if err != nil {
+			return nil, err
+		}
+
+		matchedRight := map[int]struct{}{}
+
+		for row := 0; row < left.n; row++ {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+
+			var matches []int
+			key, hasNil := left.rowKey(lKeys, row)
+			if !hasNil || opts[0].MatchNil {
+				matches = table[key]
+			}
+
+			switch how {
+			case SemiJoin:
+				if len(matches) > 0 {
+					pairs = append(pairs, [2]int{row, -1})
+				}
+				continue
+			case AntiJoin:
+				if len(matches) == 0 {
+					pairs = append(pairs, [2]int{row, -1})
+				}
+				continue
+			}
+
+			if len(matches) == 0 {
+				if how != InnerJoin {
+					pairs = append(pairs, [2]int{row, -1})
+				}
+				continue
+			}
+
+			for _, rRow := range matches {
+				pairs = append(pairs, [2]int{row, rRow})
+				if how == OuterJoin {
+					matchedRight[rRow] = struct{}{}
+				}
+			}
+		}
+
+		if how == OuterJoin {
+			for row := 0; row < right.n; row++ {
+				if _, exists := matchedRight[row]; !exists {
+					pairs = append(pairs, [2]int{-1, row})
+				}
+			}
+		}
+	case RightJoin:
+		table, err := buildHashTable(ctx, left, lKeys, opts[0].MatchNil)
+		if err != nil {
+			return nil, err
+		}
+
+		for row := 0; row < right.n; row++ {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+
+			var matches []int
+			key, hasNil := right.rowKey(rKeys, row)
+			if !hasNil || opts[0].MatchNil {
+				matches = table[key]
+			}
+
+			if len(matches) == 0 {
+				pairs = append(pairs, [2]int{-1, row})
+				continue
+			}
+
+			for _, lRow := range matches {
+				pairs = append(pairs, [2]int{lRow, row})
+			}
+		}
+	default:
+		return nil, fmt.Errorf("unknown join type: %d", how)
+	}
+
+	// Determine output series
+	type outSeries struct {
+		name     string
+		lCol     int // -1 if not from left
+		rCol     int // -1 if not from right
+		template Series
+	}
+
+	outs := []outSeries{}
+
+	if how == SemiJoin || how == AntiJoin {
+		for i, s := range left.Series {
+			outs = append(outs, outSeries{name: s.Name(), lCol: i, rCol: -1, template: s})
+		}
+	} else {
+		lKeySet := map[int]int{} // left col -> right col
+		rKeySet := map[int]struct{}{}
+		if shared {
+			for i := range lKeys {
+				lKeySet[lKeys[i]] = rKeys[i]
+				rKeySet[rKeys[i]] = struct{}{}
+			}
+		}
+
+		rNames := map[string]struct{}{}
+		for i, s := range right.Series {
+			if _, exists := rKeySet[i]; !exists {
+				rNames[s.Name()] = struct{}{}
+			}
+		}
+
+		lNames := map[string]struct{}{}
+		for i, s := range left.Series {
+			name := s.Name()
+			if rCol, exists := lKeySet[i]; exists {
+				// Shared key series are coalesced
+				outs = append(outs, outSeries{name: name, lCol: i, rCol: rCol, template: s})
+				lNames[name] = struct{}{}
+				continue
+			}
+			lNames[name] = struct{}{}
+			if _, clash := rNames[name]; clash {
+				name = name + suffixes[0]
+			}
+			outs = append(outs, outSeries{name: name, lCol: i, rCol: -1, template: s})
+		}
+
+		for i, s := range right.Series {
+			if _, exists := rKeySet[i]; exists {
+				continue
+			}
+			name := s.Name()
+			if _, clash := lNames[name]; clash {
+				name = name + suffixes[1]
+			}
+			outs = append(outs, outSeries{name: name, lCol: -1, rCol: i, template: s})
+		}
+	}
+
+	// Check names are unique
+	names := map[string]struct{}{}
+	for _, o := range outs {
+		if _, exists := names[o.name]; exists {
+			return nil, fmt.Errorf("names of series must be unique: %s", o.name)
+		}
+		names[o.name] = struct{}{}
+	}
+
+	// Create output series
+	seriess := make([]Series, 0, len(outs))
+	for _, o := range outs {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		ns := newSeriesLike(o.template, o.name, &SeriesInit{Capacity: len(pairs)})
+
+		for _, p := range pairs {
+			var val interface{}
+			if o.lCol != -1 && p[0] != -1 {
+				val = left.Series[o.lCol].Value(p[0])
+			} else if o.rCol != -1 && p[1] != -1 {
+				val = right.Series[o.rCol].Value(p[1])
+			}
+			ns.Append(val, dontLock)
+		}
+
+		seriess = append(seriess, ns)
+	}
+
+	ndf := NewDataFrame(seriess...)
+
+	// The index of the left DataFrame is retained (or right for RightJoin).
+	// Rows without a corresponding row in that DataFrame are not labelled.
+	rows := make([]int, 0, len(pairs))
+	for _, p := range pairs {
+		if how == RightJoin {
+			rows = append(rows, p[1])
+		} else {
+			rows = append(rows, p[0])
+		}
+	}
+	if how == RightJoin {
+		ndf.index = right.indexRows(rows)
+	} else {
+		ndf.index = left.indexRows(rows)
+	}
+
+	return ndf, nil
+}
+
+// buildHashTable maps the keys of each row to the rows containing them.
+func buildHashTable(ctx context.Context, df *DataFrame, cols []int, matchNil bool) (map[interface{}][]int, error) {
+
+	table := make(map[interface{}][]int, df.n)
+
+	for row := 0; row < df.n; row++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		key, hasNil := df.rowKey(cols, row)
+		if hasNil && !matchNil {
+			continue
+		}
+		table[key] = append(table[key], row)
+	}
+
+	return table, nil
+}
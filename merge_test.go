@@ -0,0 +1,104 @@
+// Copyright 2018-20 PJ Engineering and Business Solutions Pty. Ltd. All rights reserved.
+
+package dataframe
+
+import (
+	"context"
+	"testing"
+)
+
+func TestMerge(t *testing.T) {
+	ctx := context.Background()
+
+	left := NewDataFrame(
+		NewSeriesInt64("id", nil, 1, 2, 3, nil),
+		NewSeriesString("name", nil, "a", "b", "c", "d"),
+	)
+
+	right := NewDataFrame(
+		NewSeriesInt64("id", nil, 2, 3, 3, 4, nil),
+		NewSeriesString("name", nil, "B", "C1", "C2", "D", "N"),
+		NewSeriesFloat64("score", nil, 2.5, 3.5, 3.6, 4.5, 0.0),
+	)
+
+	tests := []struct {
+		how      JoinType
+		opts     MergeOptions
+		expected *DataFrame
+	}{
+		{
+			InnerJoin,
+			MergeOptions{On: []interface{}{"id"}},
+			NewDataFrame(
+				NewSeriesInt64("id", nil, 2, 3, 3),
+				NewSeriesString("name_x", nil, "b", "c", "c"),
+				NewSeriesString("name_y", nil, "B", "C1", "C2"),
+				NewSeriesFloat64("score", nil, 2.5, 3.5, 3.6),
+			),
+		},
+		{
+			LeftJoin,
+			MergeOptions{On: []interface{}{0}, Suffixes: &[2]string{"", "_r"}},
+			NewDataFrame(
+				NewSeriesInt64("id", nil, 1, 2, 3, 3, nil),
+				NewSeriesString("name", nil, "a", "b", "c", "c", "d"),
+				NewSeriesString("name_r", nil, nil, "B", "C1", "C2", nil),
+				NewSeriesFloat64("score", nil, nil, 2.5, 3.5, 3.6, nil),
+			),
+		},
+		{
+			RightJoin,
+			MergeOptions{On: []interface{}{"id"}, MatchNil: true},
+			NewDataFrame(
+				NewSeriesInt64("id", nil, 2, 3, 3, 4, nil),
+				NewSeriesString("name_x", nil, "b", "c", "c", nil, "d"),
+				NewSeriesString("name_y", nil, "B", "C1", "C2", "D", "N"),
+				NewSeriesFloat64("score", nil, 2.5, 3.5, 3.6, 4.5, 0.0),
+			),
+		},
+		{
+			OuterJoin,
+			MergeOptions{On: []interface{}{"id"}},
+			NewDataFrame(
+				NewSeriesInt64("id", nil, 1, 2, 3, 3, nil, 4, nil),
+				NewSeriesString("name_x", nil, "a", "b", "c", "c", "d", nil, nil),
+				NewSeriesString("name_y", nil, nil, "B", "C1", "C2", nil, "D", "N"),
+				NewSeriesFloat64("score", nil, nil, 2.5, 3.5, 3.6, nil, 4.5, 0.0),
+			),
+		},
+		{
+			SemiJoin,
+			MergeOptions{On: []interface{}{"id"}},
+			NewDataFrame(
+				NewSeriesInt64("id", nil, 2, 3),
+				NewSeriesString("name", nil, "b", "c"),
+			),
+		},
+		{
+			AntiJoin,
+			MergeOptions{On: []interface{}{"id"}},
+			NewDataFrame(
+				NewSeriesInt64("id", nil, 1, nil),
+				NewSeriesString("name", nil, "a", "d"),
+			),
+		},
+	}
+
+	for i, tc := range tests {
+		out, err := Merge(ctx, left, right, tc.how, tc.opts)
+		if err != nil {
+			t.Errorf("%d: wrong err: expected: %v actual: %v", i, nil, err)
+			continue
+		}
+
+		eq, err := out.IsEqual(ctx, tc.expected, IsEqualOptions{CheckName: true})
+		if err != nil {
+			t.Errorf("%d: wrong err: expected: %v actual: %v", i, nil, err)
+			continue
+		}
+
+		if !eq {
+			t.Errorf("%d: wrong val: expected: %v actual: %v", i, tc.expected.Table(), out.Table())
+		}
+	}
+}
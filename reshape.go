@@ -0,0 +1,287 @@
+// Copyright 2018-20 PJ Engineering and Business Solutions Pty. Ltd. All rights reserved.
+
+package dataframe
+
+import (
+	"context"
+	"fmt"
+)
+
+// PivotOptions modifies the behavior of the Pivot function.
+type PivotOptions struct {
+
+	// Custom is required when aggFn is AggCustom.
+	Custom AggregateFn
+
+	// DontLock can be set to true if the DataFrame should not be locked.
+	DontLock bool
+}
+
+// Pivot reshapes a DataFrame from long to wide format. The returned DataFrame contains the distinct values of the
+// index Series as the first Series, followed by a Series for each distinct value of the columns Series.
+// The Series are named using the ValueString of the columns Series. The values Series populates the table.
+// Rows and Series are ordered by their first appearance.
+//
+// aggFn is used to combine the values when multiple rows share the same index and columns values.
+// It is also applied when the pair is unique. Combinations that don't exist are filled with nil.
+// index, columns and values can be an int (position of series) or string (name of series).
+//
+// Example:
+//
+//  // date | city | temp    =>    date | Sydney | Perth
+//  wide, err := dataframe.Pivot(ctx, df, "date", "city", "temp", dataframe.AggMean)
+//
+func Pivot(ctx context.Context, df *DataFrame, index, columns, values interface{}, aggFn AggregateFunc, opts ...PivotOptions) (*DataFrame, error) {
+
+	if len(opts) == 0 {
+		opts = append(opts, PivotOptions{})
+	}
+
+	if !opts[0].DontLock {
+		df.lock.RLock()
+		defer df.lock.RUnlock()
+	}
+
+	idxCol, err := df.keyToColumn(index)
+	if err != nil {
+		return nil, err
+	}
+
+	colCol, err := df.keyToColumn(columns)
+	if err != nil {
+		return nil, err
+	}
+
+	valCol, err := df.keyToColumn(values)
+	if err != nil {
+		return nil, err
+	}
+
+	g, err := GroupBy(ctx, df, []interface{}{idxCol, colCol}, GroupByOptions{DontLock: true})
+	if err != nil {
+		return nil, err
+	}
+
+	idxSeries := df.Series[idxCol]
+	colSeries := df.Series[colCol]
+	valSeries := df.Series[valCol]
+
+	// Determine the distinct index and columns values
+	idxRows := map[interface{}]int{} // key -> output row
+	colPos := map[interface{}]int{}  // key -> output series
+	idxVals := []interface{}{}
+	colNames := []string{}
+
+	cells := make([][3]int, 0, g.NGroups()) // output row, output series, group
+
+	for group, rows := range g.rows {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		row := rows[0]
+
+		iv := idxSeries.Value(row)
+		ik := hashKey(iv)
+		r, exists := idxRows[ik]
+		if !exists {
+			r = len(idxVals)
+			idxRows[ik] = r
+			idxVals = append(idxVals, iv)
+		}
+
+		ck := hashKey(colSeries.Value(row))
+		c, exists := colPos[ck]
+		if !exists {
+			c = len(colNames)
+			colPos[ck] = c
+			colNames = append(colNames, colSeries.ValueString(row))
+		}
+
+		cells = append(cells, [3]int{r, c, group})
+	}
+
+	// Aggregate each cell
+	agg := Aggregation{Key: valCol, Func: aggFn, Custom: opts[0].Custom}
+
+	table := make([][]interface{}, len(colNames))
+	for c := range table {
+		table[c] = make([]interface{}, len(idxVals))
+	}
+
+	for _, cell := range cells {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		vals := []interface{}{}
+		for _, row := range g.rows[cell[2]] {
+			if val := valSeries.Value(row); val != nil {
+				vals = append(vals, val)
+			}
+		}
+
+		out, err := aggregateValues(ctx, valSeries, vals, agg)
+		if err != nil {
+			return nil, err
+		}
+		table[cell[1]][cell[0]] = out
+	}
+
+	// Create output series
+	init := &SeriesInit{Capacity: len(idxVals)}
+	names := map[string]struct{}{idxSeries.Name(): {}}
+
+	ns := newSeriesLike(idxSeries, idxSeries.Name(), init)
+	for _, v := range idxVals {
+		ns.Append(v, dontLock)
+	}
+	seriess := []Series{ns}
+
+	for c, name := range colNames {
+		if _, exists := names[name]; exists {
+			return nil, fmt.Errorf("names of series must be unique: %s", name)
+		}
+		names[name] = struct{}{}
+
+		ns, err := newAggregateSeries(valSeries, name, init, agg)
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range table[c] {
+			ns.Append(v, dontLock)
+		}
+		seriess = append(seriess, ns)
+	}
+
+	return NewDataFrame(seriess...), nil
+}
+
+// MeltOptions modifies the behavior of the Melt function.
+type MeltOptions struct {
+
+	// VarName sets the name of the Series containing the names of the melted Series.
+	// If not set, it defaults to "variable".
+	VarName string
+
+	// ValueName sets the name of the Series containing the values of the melted Series.
+	// If not set, it defaults to "value".
+	ValueName string
+
+	// DontLock can be set to true if the DataFrame should not be locked.
+	DontLock bool
+}
+
+// Melt reshapes a DataFrame from wide to long format. For each Series in valueVars, every row is
+// output with the idVars Series repeated, the name of the Series (as a SeriesString) and its value.
+// If valueVars is nil, all Series not in idVars are used. When the valueVars Series have different types,
+// SeriesInt64 and SeriesFloat64 are combined into a SeriesFloat64 and all other combinations into a SeriesMixed.
+// idVars and valueVars can contain an int (position of series) or string (name of series).
+//
+// Example:
+//
+//  // date | Sydney | Perth    =>    date | variable | value
+//  long, err := dataframe.Melt(ctx, df, []interface{}{"date"}, nil)
+//
+func Melt(ctx context.Context, df *DataFrame, idVars, valueVars []interface{}, opts ...MeltOptions) (*DataFrame, error) {
+
+	if len(opts) == 0 {
+		opts = append(opts, MeltOptions{})
+	}
+
+	if !opts[0].DontLock {
+		df.lock.RLock()
+		defer df.lock.RUnlock()
+	}
+
+	varName := opts[0].VarName
+	if varName == "" {
+		varName = "variable"
+	}
+
+	valueName := opts[0].ValueName
+	if valueName == "" {
+		valueName = "value"
+	}
+
+	idCols := []int{}
+	ids := map[int]struct{}{}
+	for _, k := range idVars {
+		col, err := df.keyToColumn(k)
+		if err != nil {
+			return nil, err
+		}
+		idCols = append(idCols, col)
+		ids[col] = struct{}{}
+	}
+
+	valCols := []int{}
+	if valueVars == nil {
+		for col := range df.Series {
+			if _, exists := ids[col]; !exists {
+				valCols = append(valCols, col)
+			}
+		}
+	} else {
+		for _, k := range valueVars {
+			col, err := df.keyToColumn(k)
+			if err != nil {
+				return nil, err
+			}
+			valCols = append(valCols, col)
+		}
+	}
+
+	nRows := df.n * len(valCols)
+	init := &SeriesInit{Capacity: nRows}
+
+	// Create output series
+	seriess := []Series{}
+	for _, col := range idCols {
+		s := df.Series[col]
+		seriess = append(seriess, newSeriesLike(s, s.Name(), init))
+	}
+
+	varSeries := NewSeriesString(varName, init)
+
+	valSources := []Series{}
+	for _, col := range valCols {
+		valSources = append(valSources, df.Series[col])
+	}
+
+	valSeries, err := newCommonSeries(valSources, valueName, init, true)
+	if err != nil {
+		return nil, err
+	}
+
+	seriess = append(seriess, varSeries, valSeries)
+
+	names := map[string]struct{}{}
+	for _, s := range seriess {
+		name := s.Name(dontLock)
+		if _, exists := names[name]; exists {
+			return nil, fmt.Errorf("names of series must be unique: %s", name)
+		}
+		names[name] = struct{}{}
+	}
+
+	// Populate output series
+	for _, col := range valCols {
+		src := df.Series[col]
+		name := src.Name()
+
+		for row := 0; row < df.n; row++ {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+
+			for i, idCol := range idCols {
+				seriess[i].Append(df.Series[idCol].Value(row), dontLock)
+			}
+			varSeries.Append(name, dontLock)
+			valSeries.Append(src.Value(row), dontLock)
+		}
+	}
+
+	return NewDataFrame(seriess...), nil
+}
@@ -0,0 +1,50 @@
+// Copyright 2018-20 PJ Engineering and Business Solutions Pty. Ltd. All rights reserved.
+
+package dataframe
+
+import (
+	"context"
+	"testing"
+)
+
+func TestPivotAndMelt(t *testing.T) {
+	ctx := context.Background()
+
+	df := NewDataFrame(
+		NewSeriesInt64("day", nil, 1, 1, 2, 2, 2, 3),
+		NewSeriesString("city", nil, "Sydney", "Perth", "Sydney", "Sydney", "Perth", "Perth"),
+		NewSeriesFloat64("temp", nil, 20.0, 25.0, 18.0, 22.0, nil, 30.0),
+	)
+
+	// Pivot
+	wide, err := Pivot(ctx, df, "day", "city", "temp", AggMean)
+	if err != nil {
+		t.Fatalf("wrong err: expected: %v actual: %v", nil, err)
+	}
+
+	expected := NewDataFrame(
+		NewSeriesInt64("day", nil, 1, 2, 3),
+		NewSeriesFloat64("Sydney", nil, 20.0, 20.0, nil),
+		NewSeriesFloat64("Perth", nil, 25.0, nil, 30.0),
+	)
+
+	if eq, _ := wide.IsEqual(ctx, expected, IsEqualOptions{CheckName: true}); !eq {
+		t.Errorf("wrong val: expected: %v actual: %v", expected.Table(), wide.Table())
+	}
+
+	// Melt
+	long, err := Melt(ctx, wide, []interface{}{"day"}, nil, MeltOptions{VarName: "city"})
+	if err != nil {
+		t.Fatalf("wrong err: expected: %v actual: %v", nil, err)
+	}
+
+	expected = NewDataFrame(
+		NewSeriesInt64("day", nil, 1, 2, 3, 1, 2, 3),
+		NewSeriesString("city", nil, "Sydney", "Sydney", "Sydney", "Perth", "Perth", "Perth"),
+		NewSeriesFloat64("value", nil, 20.0, 20.0, nil, 25.0, nil, 30.0),
+	)
+
+	if eq, _ := long.IsEqual(ctx, expected, IsEqualOptions{CheckName: true}); !eq {
+		t.Errorf("wrong val: expected: %v actual: %v", expected.Table(), long.Table())
+	}
+}
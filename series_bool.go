@@ -0,0 +1,1025 @@
+// Copyright 2018-20 PJ Engineering and Business Solutions Pty. Ltd. All rights reserved.
+
+package dataframe
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sync"
+
+	"golang.org/x/exp/rand"
+
+	"github.com/olekukonko/tablewriter"
+)
+
+// SeriesBool is used for series containing bool data.
+// The values are stored in a bitmap, using 2 bits per row:
+// one for the value and one to record whether the row is nil.
+type SeriesBool struct {
+	valFormatter ValueToStringFormatter
+
+	lock     sync.RWMutex
+	name     string
+	values   []uint64 // bit is set if row is true
+	valid    []uint64 // bit is set if row is not nil
+	n        int
+	nilCount int
+}
+
+// NewSeriesBool creates a new series with the underlying type as bool.
+func NewSeriesBool(name string, init *SeriesInit, vals ...interface{}) *SeriesBool {
+	s := &SeriesBool{
+		name:     name,
+		nilCount: 0,
+	}
+
+	var (
+		size     int
+		capacity int
+	)
+
+	if init != nil {
+		size = init.Size
+		capacity = init.Capacity
+		if size > capacity {
+			capacity = size
+		}
+	}
+
+	s.values = make([]uint64, bitmapWords(size), bitmapWords(capacity))
+	s.valid = make([]uint64, bitmapWords(size), bitmapWords(capacity))
+	s.n = size
+	s.nilCount = size
+	s.valFormatter = DefaultValueFormatter
+
+	for idx, v := range vals {
+
+		// Special case
+		if idx == 0 {
+			if bs, ok := vals[0].([]bool); ok {
+				for idx, v := range bs {
+					if idx < size {
+						s.set(idx, v, false)
+					} else {
+						s.insert(s.n, v)
+					}
+				}
+				break
+			}
+		}
+
+		if idx < size {
+			val, isNil := s.valToBool(v)
+			s.set(idx, val, isNil)
+		} else {
+			s.insert(s.n, v)
+		}
+	}
+
+	return s
+}
+
+// NewSeries creates a new initialized SeriesBool.
+func (s *SeriesBool) NewSeries(name string, init *SeriesInit) Series {
+	return NewSeriesBool(name, init)
+}
+
+// Name returns the series name.
+func (s *SeriesBool) Name(opts ...Options) string {
+	if len(opts) == 0 || !opts[0].DontLock {
+		s.lock.RLock()
+		defer s.lock.RUnlock()
+	}
+
+	return s.name
+}
+
+// Rename renames the series.
+func (s *SeriesBool) Rename(n string, opts ...Options) {
+	if len(opts) == 0 || !opts[0].DontLock {
+		s.lock.Lock()
+		defer s.lock.Unlock()
+	}
+
+	s.name = n
+}
+
+// Type returns the type of data the series holds.
+func (s *SeriesBool) Type() string {
+	return "bool"
+}
+
+// NRows returns how many rows the series contains.
+func (s *SeriesBool) NRows(opts ...Options) int {
+	if len(opts) == 0 || !opts[0].DontLock {
+		s.lock.RLock()
+		defer s.lock.RUnlock()
+	}
+
+	return s.n
+}
+
+// Value returns the value of a particular row.
+// The return value could be nil or the concrete type
+// the data type held by the series.
+// Pointers are never returned.
+func (s *SeriesBool) Value(row int, opts ...Options) interface{} {
+	if len(opts) == 0 || !opts[0].DontLock {
+		s.lock.RLock()
+		defer s.lock.RUnlock()
+	}
+
+	s.checkRow(row)
+
+	if !bitmapGet(s.valid, row) {
+		return nil
+	}
+	return bitmapGet(s.values, row)
+}
+
+// ValueString returns a string representation of a
+// particular row. The string representation is defined
+// by the function set in SetValueToStringFormatter.
+// By default, a nil value is returned as "NaN".
+func (s *SeriesBool) ValueString(row int, opts ...Options) string {
+	return s.valFormatter(s.Value(row, opts...))
+}
+
+// Prepend is used to set a value to the beginning of the
+// series. val can be a concrete data type or nil. Nil
+// represents the absence of a value.
+func (s *SeriesBool) Prepend(val interface{}, opts ...Options) {
+	if len(opts) == 0 || !opts[0].DontLock {
+		s.lock.Lock()
+		defer s.lock.Unlock()
+	}
+
+	s.insert(0, val)
+}
+
+// Append is used to set a value to the end of the series.
+// val can be a concrete data type or nil. Nil represents
+// the absence of a value.
+func (s *SeriesBool) Append(val interface{}, opts ...Options) int {
+	if len(opts) == 0 || !opts[0].DontLock {
+		s.lock.Lock()
+		defer s.lock.Unlock()
+	}
+
+	row := s.n
+	s.insert(row, val)
+	return row
+}
+
+// Insert is used to set a value at an arbitrary row in
+// the series. All existing values from that row onwards
+// are shifted by 1. val can be a concrete data type or nil.
+// Nil represents the absence of a value.
+func (s *SeriesBool) Insert(row int, val interface{}, opts ...Options) {
+	if len(opts) == 0 || !opts[0].DontLock {
+		s.lock.Lock()
+		defer s.lock.Unlock()
+	}
+
+	s.insert(row, val)
+}
+
+func (s *SeriesBool) insert(row int, val interface{}) {
+	if row < 0 || row > s.n {
+		panic(fmt.Errorf("row %d out of range", row))
+	}
+
+	switch V := val.(type) {
+	case []bool:
+		for i, v := range V {
+			s.insert(row+i, v)
+		}
+		return
+	case []*bool:
+		for i, v := range V {
+			s.insert(row+i, v)
+		}
+		return
+	}
+
+	v, isNil := s.valToBool(val)
+
+	s.values = bitmapInsert(s.values, s.n, row)
+	s.valid = bitmapInsert(s.valid, s.n, row)
+	s.n++
+	s.nilCount++ // new row starts off as nil
+
+	s.set(row, v, isNil)
+}
+
+// set updates the value of an existing row and maintains the nil count.
+func (s *SeriesBool) set(row int, val bool, isNil bool) {
+	wasNil := !bitmapGet(s.valid, row)
+
+	if wasNil && !isNil {
+		s.nilCount--
+	} else if !wasNil && isNil {
+		s.nilCount++
+	}
+
+	bitmapSet(s.valid, row, !isNil)
+	bitmapSet(s.values, row, val && !isNil)
+}
+
+func (s *SeriesBool) checkRow(row int) {
+	if row < 0 || row >= s.n {
+		panic(fmt.Errorf("row %d out of range", row))
+	}
+}
+
+// Remove is used to delete the value of a particular row.
+func (s *SeriesBool) Remove(row int, opts ...Options) {
+	if len(opts) == 0 || !opts[0].DontLock {
+		s.lock.Lock()
+		defer s.lock.Unlock()
+	}
+
+	s.checkRow(row)
+
+	if !bitmapGet(s.valid, row) {
+		s.nilCount--
+	}
+
+	s.values = bitmapRemove(s.values, s.n, row)
+	s.valid = bitmapRemove(s.valid, s.n, row)
+	s.n--
+}
+
+// Reset is used clear all data contained in the Series.
+func (s *SeriesBool) Reset(opts ...Options) {
+	if len(opts) == 0 || !opts[0].DontLock {
+		s.lock.Lock()
+		defer s.lock.Unlock()
+	}
+
+	s.values = []uint64{}
+	s.valid = []uint64{}
+	s.n = 0
+	s.nilCount = 0
+}
+
+// Update is used to update the value of a particular row.
+// val can be a concrete data type or nil. Nil represents
+// the absence of a value.
+func (s *SeriesBool) Update(row int, val interface{}, opts ...Options) {
+	if len(opts) == 0 || !opts[0].DontLock {
+		s.lock.Lock()
+		defer s.lock.Unlock()
+	}
+
+	s.checkRow(row)
+
+	v, isNil := s.valToBool(val)
+	s.set(row, v, isNil)
+}
+
+// ValuesIterator will return an iterator that can be used to iterate through all the values.
+func (s *SeriesBool) ValuesIterator(opts ...ValuesOptions) func() (*int, interface{}, int) {
+
+	var (
+		row  int
+		step int = 1
+	)
+
+	var dontReadLock bool
+
+	if len(opts) > 0 {
+		dontReadLock = opts[0].DontReadLock
+
+		row = opts[0].InitialRow
+		step = opts[0].Step
+		if step == 0 {
+			panic("Step can not be zero")
+		}
+	}
+
+	return func() (*int, interface{}, int) {
+		// Should this be on the outside?
+		if !dontReadLock {
+			s.lock.RLock()
+			defer s.lock.RUnlock()
+		}
+
+		if row > s.n-1 || row < 0 {
+			// Don't iterate further
+			return nil, nil, 0
+		}
+
+		var out interface{}
+		if bitmapGet(s.valid, row) {
+			out = bitmapGet(s.values, row)
+		}
+		row = row + step
+		return &[]int{row - step}[0], out, s.n
+	}
+}
+
+// valToBool converts v to a bool. isNil is true if v represents a nil value.
+func (s *SeriesBool) valToBool(v interface{}) (val bool, isNil bool) {
+	switch val := v.(type) {
+	case nil:
+		return false, true
+	case *bool:
+		if val == nil {
+			return false, true
+		}
+		return *val, false
+	case bool:
+		return val, false
+	case *int:
+		if val == nil {
+			return false, true
+		}
+		return s.valToBool(*val)
+	case int:
+		return s.valToBool(int64(val))
+	case *int64:
+		if val == nil {
+			return false, true
+		}
+		return s.valToBool(*val)
+	case int64:
+		if val == 0 || val == 1 {
+			return val == 1, false
+		}
+	case *string:
+		if val == nil {
+			return false, true
+		}
+		return s.valToBool(*val)
+	case string:
+		switch val {
+		case "TRUE", "true", "True", "1":
+			return true, false
+		case "FALSE", "false", "False", "0":
+			return false, false
+		}
+	}
+
+	_ = v.(bool) // Intentionally panic
+	return false, true
+}
+
+// SetValueToStringFormatter is used to set a function
+// to convert the value of a particular row to a string
+// representation.
+func (s *SeriesBool) SetValueToStringFormatter(f ValueToStringFormatter) {
+	if f == nil {
+		s.valFormatter = DefaultValueFormatter
+		return
+	}
+	s.valFormatter = f
+}
+
+// Swap is used to swap 2 values based on their row position.
+func (s *SeriesBool) Swap(row1, row2 int, opts ...Options) {
+	if row1 == row2 {
+		return
+	}
+
+	if len(opts) == 0 || !opts[0].DontLock {
+		s.lock.Lock()
+		defer s.lock.Unlock()
+	}
+
+	s.checkRow(row1)
+	s.checkRow(row2)
+
+	v1, valid1 := bitmapGet(s.values, row1), bitmapGet(s.valid, row1)
+	v2, valid2 := bitmapGet(s.values, row2), bitmapGet(s.valid, row2)
+
+	bitmapSet(s.values, row1, v2)
+	bitmapSet(s.valid, row1, valid2)
+	bitmapSet(s.values, row2, v1)
+	bitmapSet(s.valid, row2, valid1)
+}
+
+// IsEqualFunc returns true if a is equal to b.
+func (s *SeriesBool) IsEqualFunc(a, b interface{}) bool {
+
+	if a == nil {
+		if b == nil {
+			return true
+		}
+		return false
+	}
+
+	if b == nil {
+		return false
+	}
+	t1 := a.(bool)
+	t2 := b.(bool)
+
+	return t1 == t2
+}
+
+// IsLessThanFunc returns true if a is less than b.
+// false is considered less than true.
+func (s *SeriesBool) IsLessThanFunc(a, b interface{}) bool {
+
+	if a == nil {
+		return true
+	}
+
+	if b == nil {
+		return false
+	}
+	t1 := a.(bool)
+	t2 := b.(bool)
+
+	return !t1 && t2
+}
+
+// Sort will sort the series.
+// It will return true if sorting was completed or false when the context is canceled.
+// nil values are sorted first, followed by false and then true values.
+func (s *SeriesBool) Sort(ctx context.Context, opts ...SortOptions) (completed bool) {
+
+	if len(opts) == 0 {
+		opts = append(opts, SortOptions{})
+	}
+
+	if !opts[0].DontLock {
+		s.Lock()
+		defer s.Unlock()
+	}
+
+	// Since values of the same kind are indistinguishable, a counting sort is sufficient
+	var trues int
+	for row := 0; row < s.n; row++ {
+		if err := ctx.Err(); err != nil {
+			return false
+		}
+
+		if bitmapGet(s.valid, row) && bitmapGet(s.values, row) {
+			trues++
+		}
+	}
+	falses := s.n - s.nilCount - trues
+
+	for row := 0; row < s.n; row++ {
+		var pos int
+		if opts[0].Desc {
+			pos = s.n - 1 - row
+		} else {
+			pos = row
+		}
+
+		switch {
+		case pos < s.nilCount:
+			bitmapSet(s.valid, row, false)
+			bitmapSet(s.values, row, false)
+		case pos < s.nilCount+falses:
+			bitmapSet(s.valid, row, true)
+			bitmapSet(s.values, row, false)
+		default:
+			bitmapSet(s.valid, row, true)
+			bitmapSet(s.values, row, true)
+		}
+	}
+
+	return true
+}
+
+// Lock will lock the Series allowing you to directly manipulate
+// the underlying slice with confidence.
+func (s *SeriesBool) Lock() {
+	s.lock.Lock()
+}
+
+// Unlock will unlock the Series that was previously locked.
+func (s *SeriesBool) Unlock() {
+	s.lock.Unlock()
+}
+
+// Copy will create a new copy of the series.
+// It is recommended that you lock the Series before attempting
+// to Copy.
+func (s *SeriesBool) Copy(r ...Range) Series {
+
+	if s.n == 0 {
+		return &SeriesBool{
+			valFormatter: s.valFormatter,
+			name:         s.name,
+			values:       []uint64{},
+			valid:        []uint64{},
+		}
+	}
+
+	if len(r) == 0 {
+		r = append(r, Range{})
+	}
+
+	start, end, err := r[0].Limits(s.n)
+	if err != nil {
+		panic(err)
+	}
+
+	ns := &SeriesBool{
+		valFormatter: s.valFormatter,
+		name:         s.name,
+	}
+
+	if start == 0 && end == s.n-1 {
+		ns.values = append([]uint64(nil), s.values...)
+		ns.valid = append([]uint64(nil), s.valid...)
+		ns.n = s.n
+		ns.nilCount = s.nilCount
+		return ns
+	}
+
+	n := end - start + 1
+	ns.values = make([]uint64, bitmapWords(n))
+	ns.valid = make([]uint64, bitmapWords(n))
+	ns.n = n
+
+	for row := start; row <= end; row++ {
+		if bitmapGet(s.valid, row) {
+			bitmapSet(ns.valid, row-start, true)
+			bitmapSet(ns.values, row-start, bitmapGet(s.values, row))
+		} else {
+			ns.nilCount++
+		}
+	}
+
+	return ns
+}
+
+// Table will produce the Series in a table.
+func (s *SeriesBool) Table(opts ...TableOptions) string {
+
+	if len(opts) == 0 {
+		opts = append(opts, TableOptions{R: &Range{}})
+	}
+
+	if !opts[0].DontLock {
+		s.lock.RLock()
+		defer s.lock.RUnlock()
+	}
+
+	data := [][]string{}
+
+	headers := []string{"", s.name} // row header is blank
+	footers := []string{fmt.Sprintf("%dx%d", s.n, 1), s.Type()}
+
+	if s.n > 0 {
+
+		start, end, err := opts[0].R.Limits(s.n)
+		if err != nil {
+			panic(err)
+		}
+
+		for row := start; row <= end; row++ {
+			sVals := []string{fmt.Sprintf("%d:", row), s.ValueString(row, dontLock)}
+			data = append(data, sVals)
+		}
+
+	}
+
+	var buf bytes.Buffer
+
+	table := tablewriter.NewWriter(&buf)
+	table.SetHeader(headers)
+	for _, v := range data {
+		table.Append(v)
+	}
+	table.SetFooter(footers)
+	table.SetAlignment(tablewriter.ALIGN_CENTER)
+
+	table.Render()
+
+	return buf.String()
+}
+
+// String implements the fmt.Stringer interface. It does not lock the Series.
+func (s *SeriesBool) String() string {
+
+	count := s.n
+
+	out := "[ "
+
+	if count > 6 {
+		idx := []int{0, 1, 2, count - 3, count - 2, count - 1}
+		for j, row := range idx {
+			if j == 3 {
+				out = out + "... "
+			}
+			out = out + s.ValueString(row, dontLock) + " "
+		}
+		return out + "]"
+	}
+
+	for row := 0; row < count; row++ {
+		out = out + s.ValueString(row, dontLock) + " "
+	}
+	return out + "]"
+}
+
+// ContainsNil will return whether or not the series contains any nil values.
+func (s *SeriesBool) ContainsNil(opts ...Options) bool {
+	if len(opts) == 0 || !opts[0].DontLock {
+		s.lock.RLock()
+		defer s.lock.RUnlock()
+	}
+
+	return s.nilCount > 0
+}
+
+// NilCount will return how many nil values are in the series.
+func (s *SeriesBool) NilCount(opts ...NilCountOptions) (int, error) {
+	if len(opts) == 0 {
+		s.lock.RLock()
+		defer s.lock.RUnlock()
+		return s.nilCount, nil
+	}
+
+	if !opts[0].DontLock {
+		s.lock.RLock()
+		defer s.lock.RUnlock()
+	}
+
+	var (
+		ctx context.Context
+		r   *Range
+	)
+
+	if opts[0].Ctx == nil {
+		ctx = context.Background()
+	} else {
+		ctx = opts[0].Ctx
+	}
+
+	if opts[0].R == nil {
+		r = &Range{}
+	} else {
+		r = opts[0].R
+	}
+
+	start, end, err := r.Limits(s.n)
+	if err != nil {
+		return 0, err
+	}
+
+	if start == 0 && end == s.n-1 {
+		return s.nilCount, nil
+	}
+
+	var nilCount int
+
+	for i := start; i <= end; i++ {
+		if err := ctx.Err(); err != nil {
+			return 0, err
+		}
+
+		if !bitmapGet(s.valid, i) {
+
+			if opts[0].StopAtOneNil {
+				return 1, nil
+			}
+
+			nilCount++
+		}
+	}
+
+	return nilCount, nil
+}
+
+// ToSeriesString will convert the Series to a SeriesString.
+// The operation does not lock the Series.
+func (s *SeriesBool) ToSeriesString(ctx context.Context, removeNil bool, conv ...func(interface{}) (*string, error)) (*SeriesString, error) {
+
+	ec := NewErrorCollection()
+
+	ss := NewSeriesString(s.name, &SeriesInit{Capacity: s.n})
+
+	for row := 0; row < s.n; row++ {
+
+		// Cancel operation
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		if !bitmapGet(s.valid, row) {
+			if removeNil {
+				continue
+			}
+			ss.values = append(ss.values, nil)
+			ss.nilCount++
+		} else {
+			rowVal := bitmapGet(s.values, row)
+			if len(conv) == 0 {
+				cv := fmt.Sprintf("%t", rowVal)
+				ss.values = append(ss.values, &cv)
+			} else {
+				cv, err := conv[0](rowVal)
+				if err != nil {
+					// interpret as nil
+					ss.values = append(ss.values, nil)
+					ss.nilCount++
+					ec.AddError(&RowError{Row: row, Err: err}, false)
+				} else {
+					if cv == nil {
+						ss.values = append(ss.values, nil)
+						ss.nilCount++
+					} else {
+						ss.values = append(ss.values, cv)
+					}
+				}
+			}
+		}
+	}
+
+	if !ec.IsNil(false) {
+		return ss, ec
+	}
+
+	return ss, nil
+}
+
+// ToSeriesInt64 will convert the Series to a SeriesInt64.
+// true is converted to 1 and false to 0.
+// The operation does not lock the Series.
+func (s *SeriesBool) ToSeriesInt64(ctx context.Context, removeNil bool, conv ...func(interface{}) (*int64, error)) (*SeriesInt64, error) {
+
+	ec := NewErrorCollection()
+
+	ss := NewSeriesInt64(s.name, &SeriesInit{Capacity: s.n})
+
+	for row := 0; row < s.n; row++ {
+
+		// Cancel operation
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		if !bitmapGet(s.valid, row) {
+			if removeNil {
+				continue
+			}
+			ss.values = append(ss.values, nil)
+			ss.nilCount++
+		} else {
+			rowVal := bitmapGet(s.values, row)
+			if len(conv) == 0 {
+				var cv int64
+				if rowVal {
+					cv = 1
+				}
+				ss.values = append(ss.values, &cv)
+			} else {
+				cv, err := conv[0](rowVal)
+				if err != nil {
+					// interpret as nil
+					ss.values = append(ss.values, nil)
+					ss.nilCount++
+					ec.AddError(&RowError{Row: row, Err: err}, false)
+				} else {
+					if cv == nil {
+						ss.nilCount++
+					}
+					ss.values = append(ss.values, cv)
+				}
+			}
+		}
+	}
+
+	if !ec.IsNil(false) {
+		return ss, ec
+	}
+
+	return ss, nil
+}
+
+// ToSeriesFloat64 will convert the Series to a SeriesFloat64.
+// true is converted to 1 and false to 0.
+// The operation does not lock the Series.
+func (s *SeriesBool) ToSeriesFloat64(ctx context.Context, removeNil bool, conv ...func(interface{}) (float64, error)) (*SeriesFloat64, error) {
+
+	ec := NewErrorCollection()
+
+	ss := NewSeriesFloat64(s.name, &SeriesInit{Capacity: s.n})
+
+	for row := 0; row < s.n; row++ {
+
+		// Cancel operation
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		if !bitmapGet(s.valid, row) {
+			if removeNil {
+				continue
+			}
+			ss.Values = append(ss.Values, nan())
+			ss.nilCount++
+		} else {
+			rowVal := bitmapGet(s.values, row)
+			if len(conv) == 0 {
+				var cv float64
+				if rowVal {
+					cv = 1
+				}
+				ss.Values = append(ss.Values, cv)
+			} else {
+				cv, err := conv[0](rowVal)
+				if err != nil {
+					// interpret as nil
+					ss.Values = append(ss.Values, nan())
+					ss.nilCount++
+					ec.AddError(&RowError{Row: row, Err: err}, false)
+				} else {
+					if isNaN(cv) {
+						ss.nilCount++
+					}
+					ss.Values = append(ss.Values, cv)
+				}
+			}
+		}
+	}
+
+	if !ec.IsNil(false) {
+		return ss, ec
+	}
+
+	return ss, nil
+}
+
+// ToSeriesMixed will convert the Series to a SeriesMIxed.
+// The operation does not lock the Series.
+func (s *SeriesBool) ToSeriesMixed(ctx context.Context, removeNil bool, conv ...func(interface{}) (interface{}, error)) (*SeriesMixed, error) {
+	ec := NewErrorCollection()
+
+	ss := NewSeriesMixed(s.name, &SeriesInit{Capacity: s.n})
+
+	for row := 0; row < s.n; row++ {
+
+		// Cancel operation
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		if !bitmapGet(s.valid, row) {
+			if removeNil {
+				continue
+			}
+			ss.values = append(ss.values, nil)
+			ss.nilCount++
+		} else {
+			rowVal := bitmapGet(s.values, row)
+			if len(conv) == 0 {
+				ss.values = append(ss.values, rowVal)
+			} else {
+				cv, err := conv[0](rowVal)
+				if err != nil {
+					// interpret as nil
+					ss.values = append(ss.values, nil)
+					ss.nilCount++
+					ec.AddError(&RowError{Row: row, Err: err}, false)
+				} else {
+					if cv == nil {
+						ss.nilCount++
+					}
+					ss.values = append(ss.values, cv)
+				}
+			}
+		}
+	}
+
+	if !ec.IsNil(false) {
+		return ss, ec
+	}
+
+	return ss, nil
+}
+
+// FillRand will fill a Series with random data. probNil is a value between between 0 and 1 which
+// determines if a row is given a nil value.
+// A row is set to true when rander returns a value greater than or equal to 0.5,
+// which makes distuv.Bernoulli a natural choice. If rander is nil, true and false are equally likely.
+// Only existing rows are filled. Use SeriesInit's Size to preallocate rows.
+func (s *SeriesBool) FillRand(src rand.Source, probNil float64, rander Rander, opts ...FillRandOptions) {
+
+	rng := rand.New(src)
+
+	start, end := 0, s.n-1
+	if len(opts) > 0 && opts[0].R != nil && s.n > 0 {
+		var err error
+		start, end, err = opts[0].R.Limits(s.n)
+		if err != nil {
+			panic(err)
+		}
+	}
+
+	for i := start; i <= end; i++ {
+		if rng.Float64() < probNil {
+			// nil
+			s.set(i, false, true)
+		} else if rander == nil {
+			s.set(i, rng.Float64() < 0.5, false)
+		} else {
+			s.set(i, rander.Rand() >= 0.5, false)
+		}
+	}
+}
+
+// IsEqual returns true if s2's values are equal to s.
+func (s *SeriesBool) IsEqual(ctx context.Context, s2 Series, opts ...IsEqualOptions) (bool, error) {
+	if len(opts) == 0 || !opts[0].DontLock {
+		s.lock.RLock()
+		defer s.lock.RUnlock()
+	}
+
+	// Check type
+	bs, ok := s2.(*SeriesBool)
+	if !ok {
+		return false, nil
+	}
+
+	// Check number of values
+	if s.n != bs.n || s.nilCount != bs.nilCount {
+		return false, nil
+	}
+
+	// Check name
+	if len(opts) != 0 && opts[0].CheckName {
+		if s.name != bs.name {
+			return false, nil
+		}
+	}
+
+	// Check values
+	for row := 0; row < s.n; row++ {
+		if err := ctx.Err(); err != nil {
+			return false, err
+		}
+
+		if bitmapGet(s.valid, row) != bitmapGet(bs.valid, row) || bitmapGet(s.values, row) != bitmapGet(bs.values, row) {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// bitmapWords returns the number of uint64 words required to store n bits.
+func bitmapWords(n int) int {
+	return (n + 63) / 64
+}
+
+func bitmapGet(b []uint64, i int) bool {
+	return b[i/64]&(1<<uint(i%64)) != 0
+}
+
+func bitmapSet(b []uint64, i int, v bool) {
+	if v {
+		b[i/64] |= 1 << uint(i%64)
+	} else {
+		b[i/64] &^= 1 << uint(i%64)
+	}
+}
+
+// bitmapInsert inserts an unset bit at position i of a bitmap containing n bits.
+// All bits from position i onwards are shifted by 1.
+func bitmapInsert(b []uint64, n int, i int) []uint64 {
+	if bitmapWords(n+1) > len(b) {
+		b = append(b, 0)
+	}
+
+	w := i / 64
+	offset := uint(i % 64)
+
+	// Carry the top bit of each word into the next word
+	for j := len(b) - 1; j > w; j-- {
+		b[j] = b[j]<<1 | b[j-1]>>63
+	}
+
+	low := b[w] & (1<<offset - 1)
+	high := b[w] &^ (1<<offset - 1)
+	b[w] = low | high<<1
+
+	return b
+}
+
+// bitmapRemove removes the bit at position i of a bitmap containing n bits.
+// All bits after position i are shifted back by 1.
+func bitmapRemove(b []uint64, n int, i int) []uint64 {
+	w := i / 64
+	offset := uint(i % 64)
+
+	low := b[w] & (1<<offset - 1)
+	high := b[w] >> (offset + 1) << offset
+	b[w] = low | high
+
+	for j := w; j < len(b)-1; j++ {
+		b[j] |= b[j+1] << 63
+		b[j+1] >>= 1
+	}
+
+	return b[:bitmapWords(n-1)]
+}
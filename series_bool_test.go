@@ -0,0 +1,93 @@
+// Copyright 2018-20 PJ Engineering and Business Solutions Pty. Ltd. All rights reserved.
+
+package dataframe
+
+import (
+	"context"
+	"testing"
+
+	"golang.org/x/exp/rand"
+)
+
+func TestSeriesBool(t *testing.T) {
+	ctx := context.Background()
+
+	// Span multiple words of the bitmap
+	vals := []interface{}{}
+	for i := 0; i < 150; i++ {
+		if i%7 == 0 {
+			vals = append(vals, nil)
+		} else {
+			vals = append(vals, i%3 == 0)
+		}
+	}
+
+	s := NewSeriesBool("test", nil, vals...)
+
+	if s.NRows() != 150 {
+		t.Errorf("wrong val: expected: %v actual: %v", 150, s.NRows())
+	}
+
+	if nc, _ := s.NilCount(); nc != 22 {
+		t.Errorf("wrong val: expected: %v actual: %v", 22, nc)
+	}
+
+	// Insert and Remove across word boundaries
+	s.Insert(10, true)
+	s.Prepend(nil)
+	s.Remove(64) // vals[62]
+	s.Remove(0)
+	s.Remove(10)
+
+	for row := 0; row < s.NRows(); row++ {
+		expected := vals[row]
+		if row >= 62 {
+			expected = vals[row+1]
+		}
+		if s.Value(row) != expected {
+			t.Errorf("row %d: wrong val: expected: %v actual: %v", row, expected, s.Value(row))
+		}
+	}
+
+	// Update
+	s.Update(0, "true")
+	s.Update(1, nil)
+	if s.Value(0) != true || s.Value(1) != nil {
+		t.Errorf("wrong val: expected: %v actual: %v", "[true NaN]", s.Copy(Range{End: &[]int{1}[0]}))
+	}
+
+	// Copy
+	cp := s.Copy(RangeFinite(0, 3))
+	expected := NewSeriesBool("test", nil, true, nil, false, true)
+	if eq, _ := cp.IsEqual(ctx, expected, IsEqualOptions{CheckName: true}); !eq {
+		t.Errorf("wrong val: expected: %v actual: %v", expected, cp)
+	}
+
+	// Sort
+	ss := NewSeriesBool("test", nil, true, nil, false, true, nil)
+	ss.Sort(ctx, SortOptions{Desc: true})
+	expected = NewSeriesBool("test", nil, true, true, false, nil, nil)
+	if eq, _ := ss.IsEqual(ctx, expected); !eq {
+		t.Errorf("wrong val: expected: %v actual: %v", expected, ss)
+	}
+
+	// Conversion
+	is, _ := ss.ToSeriesInt64(ctx, true)
+	expectedI := NewSeriesInt64("test", nil, 1, 1, 0)
+	if eq, _ := is.IsEqual(ctx, expectedI); !eq {
+		t.Errorf("wrong val: expected: %v actual: %v", expectedI, is)
+	}
+
+	str, _ := ss.ToSeriesString(ctx, false)
+	expectedS := NewSeriesString("test", nil, "true", "true", "false", nil, nil)
+	if eq, _ := str.IsEqual(ctx, expectedS); !eq {
+		t.Errorf("wrong val: expected: %v actual: %v", expectedS, str)
+	}
+
+	// FillRand
+	fr := NewSeriesBool("test", &SeriesInit{Size: 100})
+	fr.FillRand(rand.NewSource(1), 0.2, nil)
+	if nc, _ := fr.NilCount(); nc == 0 || nc == 100 {
+		t.Errorf("wrong val: expected nil count between 0 and 100 actual: %v", nc)
+	}
+}
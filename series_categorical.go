@@ -0,0 +1,911 @@
+// Copyright 2018-20 PJ Engineering and Business Solutions Pty. Ltd. All rights reserved.
+
+package dataframe
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/olekukonko/tablewriter"
+)
+
+// nilCode is the code used to represent a nil value in a SeriesCategorical.
+const nilCode = -1
+
+// SeriesCategorical is used for series containing string data with a small number of distinct values.
+// Each row stores an integer code that refers to a category in a dictionary, which
+// significantly reduces memory usage when values are frequently repeated.
+//
+// When the categories are ordered, values are compared by the position of their category
+// in the dictionary. Otherwise they are compared lexically.
+type SeriesCategorical struct {
+	valFormatter ValueToStringFormatter
+
+	lock       sync.RWMutex
+	name       string
+	codes      []int32
+	categories []string
+	lookup     map[string]int32
+	ordered    bool
+	nilCount   int
+}
+
+// NewSeriesCategorical creates a new series with the underlying type as string, stored as categories.
+// Categories are added to the dictionary in the order they are first encountered.
+// Use SetCategories to set an explicit (and optionally ordered) dictionary.
+func NewSeriesCategorical(name string, init *SeriesInit, vals ...interface{}) *SeriesCategorical {
+	s := &SeriesCategorical{
+		name:       name,
+		categories: []string{},
+		lookup:     map[string]int32{},
+		nilCount:   0,
+	}
+
+	var (
+		size     int
+		capacity int
+	)
+
+	if init != nil {
+		size = init.Size
+		capacity = init.Capacity
+		if size > capacity {
+			capacity = size
+		}
+	}
+
+	s.codes = make([]int32, size, capacity)
+	for i := range s.codes {
+		s.codes[i] = nilCode
+	}
+	s.nilCount = size
+	s.valFormatter = DefaultValueFormatter
+
+	for idx, v := range vals {
+
+		// Special case
+		if idx == 0 {
+			if ss, ok := vals[0].([]string); ok {
+				for idx, v := range ss {
+					if idx < size {
+						s.update(idx, v)
+					} else {
+						s.insert(len(s.codes), v)
+					}
+				}
+				break
+			}
+		}
+
+		if idx < size {
+			s.update(idx, v)
+		} else {
+			s.insert(len(s.codes), v)
+		}
+	}
+
+	return s
+}
+
+// NewSeries creates a new initialized SeriesCategorical.
+// The new Series has the same categories and ordering.
+func (s *SeriesCategorical) NewSeries(name string, init *SeriesInit) Series {
+	ns := NewSeriesCategorical(name, init)
+	ns.ordered = s.ordered
+	for _, c := range s.categories {
+		ns.category(c)
+	}
+	return ns
+}
+
+// Categories returns the categories in the dictionary.
+func (s *SeriesCategorical) Categories(opts ...Options) []string {
+	if len(opts) == 0 || !opts[0].DontLock {
+		s.lock.RLock()
+		defer s.lock.RUnlock()
+	}
+
+	return append([]string{}, s.categories...)
+}
+
+// Ordered returns true if the categories are ordered.
+func (s *SeriesCategorical) Ordered(opts ...Options) bool {
+	if len(opts) == 0 || !opts[0].DontLock {
+		s.lock.RLock()
+		defer s.lock.RUnlock()
+	}
+
+	return s.ordered
+}
+
+// SetCategories replaces the dictionary with categories. If ordered is true,
+// the order of categories determines how values are sorted and compared.
+// An error is returned if an existing value is not found in categories.
+// Values inserted in the future that are not found in categories are added to the end of the dictionary.
+func (s *SeriesCategorical) SetCategories(categories []string, ordered bool, opts ...Options) error {
+	if len(opts) == 0 || !opts[0].DontLock {
+		s.lock.Lock()
+		defer s.lock.Unlock()
+	}
+
+	lookup := map[string]int32{}
+	for i, c := range categories {
+		if _, exists := lookup[c]; exists {
+			return fmt.Errorf("duplicate category: %s", c)
+		}
+		lookup[c] = int32(i)
+	}
+
+	// Map old codes to new codes
+	remap := make([]int32, len(s.categories))
+	for i, c := range s.categories {
+		code, exists := lookup[c]
+		if !exists {
+			code = nilCode
+		}
+		remap[i] = code
+	}
+
+	for row, code := range s.codes {
+		if code == nilCode {
+			continue
+		}
+		if remap[code] == nilCode {
+			return &RowError{Row: row, Err: fmt.Errorf("value not found in categories: %s", s.categories[code])}
+		}
+	}
+
+	for row, code := range s.codes {
+		if code != nilCode {
+			s.codes[row] = remap[code]
+		}
+	}
+
+	s.categories = append([]string{}, categories...)
+	s.lookup = lookup
+	s.ordered = ordered
+
+	return nil
+}
+
+// Name returns the series name.
+func (s *SeriesCategorical) Name(opts ...Options) string {
+	if len(opts) == 0 || !opts[0].DontLock {
+		s.lock.RLock()
+		defer s.lock.RUnlock()
+	}
+
+	return s.name
+}
+
+// Rename renames the series.
+func (s *SeriesCategorical) Rename(n string, opts ...Options) {
+	if len(opts) == 0 || !opts[0].DontLock {
+		s.lock.Lock()
+		defer s.lock.Unlock()
+	}
+
+	s.name = n
+}
+
+// Type returns the type of data the series holds.
+func (s *SeriesCategorical) Type() string {
+	return "categorical"
+}
+
+// NRows returns how many rows the series contains.
+func (s *SeriesCategorical) NRows(opts ...Options) int {
+	if len(opts) == 0 || !opts[0].DontLock {
+		s.lock.RLock()
+		defer s.lock.RUnlock()
+	}
+
+	return len(s.codes)
+}
+
+// Value returns the value of a particular row.
+// The return value could be nil or the concrete type
+// the data type held by the series.
+// Pointers are never returned.
+func (s *SeriesCategorical) Value(row int, opts ...Options) interface{} {
+	if len(opts) == 0 || !opts[0].DontLock {
+		s.lock.RLock()
+		defer s.lock.RUnlock()
+	}
+
+	code := s.codes[row]
+	if code == nilCode {
+		return nil
+	}
+	return s.categories[code]
+}
+
+// Code returns the code of a particular row, which is the position of the
+// value's category in the dictionary. -1 is returned for nil values.
+func (s *SeriesCategorical) Code(row int, opts ...Options) int {
+	if len(opts) == 0 || !opts[0].DontLock {
+		s.lock.RLock()
+		defer s.lock.RUnlock()
+	}
+
+	return int(s.codes[row])
+}
+
+// ValueString returns a string representation of a
+// particular row. The string representation is defined
+// by the function set in SetValueToStringFormatter.
+// By default, a nil value is returned as "NaN".
+func (s *SeriesCategorical) ValueString(row int, opts ...Options) string {
+	return s.valFormatter(s.Value(row, opts...))
+}
+
+// Prepend is used to set a value to the beginning of the
+// series. val can be a concrete data type or nil. Nil
+// represents the absence of a value.
+func (s *SeriesCategorical) Prepend(val interface{}, opts ...Options) {
+	if len(opts) == 0 || !opts[0].DontLock {
+		s.lock.Lock()
+		defer s.lock.Unlock()
+	}
+
+	s.insert(0, val)
+}
+
+// Append is used to set a value to the end of the series.
+// val can be a concrete data type or nil. Nil represents
+// the absence of a value.
+func (s *SeriesCategorical) Append(val interface{}, opts ...Options) int {
+	if len(opts) == 0 || !opts[0].DontLock {
+		s.lock.Lock()
+		defer s.lock.Unlock()
+	}
+
+	row := len(s.codes)
+	s.insert(row, val)
+	return row
+}
+
+// Insert is used to set a value at an arbitrary row in
+// the series. All existing values from that row onwards
+// are shifted by 1. val can be a concrete data type or nil.
+// Nil represents the absence of a value.
+func (s *SeriesCategorical) Insert(row int, val interface{}, opts ...Options) {
+	if len(opts) == 0 || !opts[0].DontLock {
+		s.lock.Lock()
+		defer s.lock.Unlock()
+	}
+
+	s.insert(row, val)
+}
+
+func (s *SeriesCategorical) insert(row int, val interface{}) {
+	switch V := val.(type) {
+	case []string:
+		codes := make([]int32, 0, len(V))
+		for _, v := range V {
+			codes = append(codes, s.category(v))
+		}
+		s.codes = append(s.codes[:row], append(codes, s.codes[row:]...)...)
+		return
+	case []*string:
+		codes := make([]int32, 0, len(V))
+		for _, v := range V {
+			code := s.valToCode(v)
+			if code == nilCode {
+				s.nilCount++
+			}
+			codes = append(codes, code)
+		}
+		s.codes = append(s.codes[:row], append(codes, s.codes[row:]...)...)
+		return
+	}
+
+	s.codes = append(s.codes, nilCode)
+	copy(s.codes[row+1:], s.codes[row:])
+
+	code := s.valToCode(val)
+	if code == nilCode {
+		s.nilCount++
+	}
+
+	s.codes[row] = code
+}
+
+// Remove is used to delete the value of a particular row.
+// The category remains in the dictionary.
+func (s *SeriesCategorical) Remove(row int, opts ...Options) {
+	if len(opts) == 0 || !opts[0].DontLock {
+		s.lock.Lock()
+		defer s.lock.Unlock()
+	}
+
+	if s.codes[row] == nilCode {
+		s.nilCount--
+	}
+
+	s.codes = append(s.codes[:row], s.codes[row+1:]...)
+}
+
+// Reset is used clear all data contained in the Series.
+// The categories remain in the dictionary.
+func (s *SeriesCategorical) Reset(opts ...Options) {
+	if len(opts) == 0 || !opts[0].DontLock {
+		s.lock.Lock()
+		defer s.lock.Unlock()
+	}
+
+	s.codes = []int32{}
+	s.nilCount = 0
+}
+
+// Update is used to update the value of a particular row.
+// val can be a concrete data type or nil. Nil represents
+// the absence of a value.
+func (s *SeriesCategorical) Update(row int, val interface{}, opts ...Options) {
+	if len(opts) == 0 || !opts[0].DontLock {
+		s.lock.Lock()
+		defer s.lock.Unlock()
+	}
+
+	s.update(row, val)
+}
+
+func (s *SeriesCategorical) update(row int, val interface{}) {
+	newCode := s.valToCode(val)
+
+	if s.codes[row] == nilCode && newCode != nilCode {
+		s.nilCount--
+	} else if s.codes[row] != nilCode && newCode == nilCode {
+		s.nilCount++
+	}
+
+	s.codes[row] = newCode
+}
+
+// ValuesIterator will return an iterator that can be used to iterate through all the values.
+func (s *SeriesCategorical) ValuesIterator(opts ...ValuesOptions) func() (*int, interface{}, int) {
+
+	var (
+		row  int
+		step int = 1
+	)
+
+	var dontReadLock bool
+
+	if len(opts) > 0 {
+		dontReadLock = opts[0].DontReadLock
+
+		row = opts[0].InitialRow
+		step = opts[0].Step
+		if step == 0 {
+			panic("Step can not be zero")
+		}
+	}
+
+	return func() (*int, interface{}, int) {
+		// Should this be on the outside?
+		if !dontReadLock {
+			s.lock.RLock()
+			defer s.lock.RUnlock()
+		}
+
+		if row > len(s.codes)-1 || row < 0 {
+			// Don't iterate further
+			return nil, nil, 0
+		}
+
+		code := s.codes[row]
+		var out interface{}
+		if code != nilCode {
+			out = s.categories[code]
+		}
+		row = row + step
+		return &[]int{row - step}[0], out, len(s.codes)
+	}
+}
+
+// category returns the code for c, adding it to the dictionary if required.
+func (s *SeriesCategorical) category(c string) int32 {
+	if s.lookup == nil {
+		s.lookup = map[string]int32{}
+	}
+
+	code, exists := s.lookup[c]
+	if !exists {
+		code = int32(len(s.categories))
+		s.categories = append(s.categories, c)
+		s.lookup[c] = code
+	}
+	return code
+}
+
+func (s *SeriesCategorical) valToCode(v interface{}) int32 {
+	switch val := v.(type) {
+	case nil:
+		return nilCode
+	case *string:
+		if val == nil {
+			return nilCode
+		}
+		return s.category(*val)
+	case string:
+		return s.category(val)
+	default:
+		_ = v.(string) // Intentionally panic
+		return nilCode
+	}
+}
+
+// SetValueToStringFormatter is used to set a function
+// to convert the value of a particular row to a string
+// representation.
+func (s *SeriesCategorical) SetValueToStringFormatter(f ValueToStringFormatter) {
+	if f == nil {
+		s.valFormatter = DefaultValueFormatter
+		return
+	}
+	s.valFormatter = f
+}
+
+// Swap is used to swap 2 values based on their row position.
+func (s *SeriesCategorical) Swap(row1, row2 int, opts ...Options) {
+	if row1 == row2 {
+		return
+	}
+
+	if len(opts) == 0 || !opts[0].DontLock {
+		s.lock.Lock()
+		defer s.lock.Unlock()
+	}
+
+	s.codes[row1], s.codes[row2] = s.codes[row2], s.codes[row1]
+}
+
+// IsEqualFunc returns true if a is equal to b.
+func (s *SeriesCategorical) IsEqualFunc(a, b interface{}) bool {
+
+	if a == nil {
+		if b == nil {
+			return true
+		}
+		return false
+	}
+
+	if b == nil {
+		return false
+	}
+	s1 := a.(string)
+	s2 := b.(string)
+
+	return s1 == s2
+}
+
+// IsLessThanFunc returns true if a is less than b.
+// If the categories are ordered, a is less than b if its category appears earlier in the dictionary.
+// Otherwise, a and b are compared lexically.
+func (s *SeriesCategorical) IsLessThanFunc(a, b interface{}) bool {
+
+	if a == nil {
+		if b == nil {
+			return true
+		}
+		return true
+	}
+
+	if b == nil {
+		return false
+	}
+	s1 := a.(string)
+	s2 := b.(string)
+
+	if s.ordered {
+		c1, exists1 := s.lookup[s1]
+		c2, exists2 := s.lookup[s2]
+		if exists1 && exists2 {
+			return c1 < c2
+		}
+	}
+
+	return s1 < s2
+}
+
+// Sort will sort the series.
+// It will return true if sorting was completed or false when the context is canceled.
+func (s *SeriesCategorical) Sort(ctx context.Context, opts ...SortOptions) (completed bool) {
+
+	defer func() {
+		if x := recover(); x != nil {
+			completed = false
+		}
+	}()
+
+	if len(opts) == 0 {
+		opts = append(opts, SortOptions{})
+	}
+
+	if !opts[0].DontLock {
+		s.Lock()
+		defer s.Unlock()
+	}
+
+	sortFunc := func(i, j int) (ret bool) {
+		if err := ctx.Err(); err != nil {
+			panic(err)
+		}
+
+		defer func() {
+			if opts[0].Desc {
+				ret = !ret
+			}
+		}()
+
+		if s.codes[i] == nilCode {
+			if s.codes[j] == nilCode {
+				// both are nil
+				return true
+			}
+			return true
+		}
+
+		if s.codes[j] == nilCode {
+			// i has value and j is nil
+			return false
+		}
+		// Both are not nil
+		if s.ordered {
+			return s.codes[i] < s.codes[j]
+		}
+		return s.categories[s.codes[i]] < s.categories[s.codes[j]]
+	}
+
+	if opts[0].Stable {
+		sort.SliceStable(s.codes, sortFunc)
+	} else {
+		sort.Slice(s.codes, sortFunc)
+	}
+
+	return true
+}
+
+// Lock will lock the Series allowing you to directly manipulate
+// the underlying slice with confidence.
+func (s *SeriesCategorical) Lock() {
+	s.lock.Lock()
+}
+
+// Unlock will unlock the Series that was previously locked.
+func (s *SeriesCategorical) Unlock() {
+	s.lock.Unlock()
+}
+
+// Copy will create a new copy of the series.
+// It is recommended that you lock the Series before attempting
+// to Copy.
+func (s *SeriesCategorical) Copy(r ...Range) Series {
+
+	ns := &SeriesCategorical{
+		valFormatter: s.valFormatter,
+		name:         s.name,
+		categories:   append([]string{}, s.categories...),
+		lookup:       make(map[string]int32, len(s.lookup)),
+		ordered:      s.ordered,
+	}
+
+	for k, v := range s.lookup {
+		ns.lookup[k] = v
+	}
+
+	if len(s.codes) == 0 {
+		ns.codes = []int32{}
+		return ns
+	}
+
+	if len(r) == 0 {
+		r = append(r, Range{})
+	}
+
+	start, end, err := r[0].Limits(len(s.codes))
+	if err != nil {
+		panic(err)
+	}
+
+	// Copy slice
+	x := s.codes[start : end+1]
+	ns.codes = append(x[:0:0], x...)
+
+	for _, code := range ns.codes {
+		if code == nilCode {
+			ns.nilCount++
+		}
+	}
+
+	return ns
+}
+
+// Table will produce the Series in a table.
+func (s *SeriesCategorical) Table(opts ...TableOptions) string {
+
+	if len(opts) == 0 {
+		opts = append(opts, TableOptions{R: &Range{}})
+	}
+
+	if !opts[0].DontLock {
+		s.lock.RLock()
+		defer s.lock.RUnlock()
+	}
+
+	data := [][]string{}
+
+	headers := []string{"", s.name} // row header is blank
+	footers := []string{fmt.Sprintf("%dx%d", len(s.codes), 1), s.Type()}
+
+	if len(s.codes) > 0 {
+
+		start, end, err := opts[0].R.Limits(len(s.codes))
+		if err != nil {
+			panic(err)
+		}
+
+		for row := start; row <= end; row++ {
+			sVals := []string{fmt.Sprintf("%d:", row), s.ValueString(row, dontLock)}
+			data = append(data, sVals)
+		}
+
+	}
+
+	var buf bytes.Buffer
+
+	table := tablewriter.NewWriter(&buf)
+	table.SetHeader(headers)
+	for _, v := range data {
+		table.Append(v)
+	}
+	table.SetFooter(footers)
+	table.SetAlignment(tablewriter.ALIGN_CENTER)
+
+	table.Render()
+
+	return buf.String()
+}
+
+// String implements the fmt.Stringer interface. It does not lock the Series.
+func (s *SeriesCategorical) String() string {
+
+	count := len(s.codes)
+
+	out := "[ "
+
+	if count > 6 {
+		idx := []int{0, 1, 2, count - 3, count - 2, count - 1}
+		for j, row := range idx {
+			if j == 3 {
+				out = out + "... "
+			}
+			out = out + s.ValueString(row, dontLock) + " "
+		}
+		return out + "]"
+	}
+
+	for row := range s.codes {
+		out = out + s.ValueString(row, dontLock) + " "
+	}
+	return out + "]"
+}
+
+// ContainsNil will return whether or not the series contains any nil values.
+func (s *SeriesCategorical) ContainsNil(opts ...Options) bool {
+	if len(opts) == 0 || !opts[0].DontLock {
+		s.lock.RLock()
+		defer s.lock.RUnlock()
+	}
+
+	return s.nilCount > 0
+}
+
+// NilCount will return how many nil values are in the series.
+func (s *SeriesCategorical) NilCount(opts ...NilCountOptions) (int, error) {
+	if len(opts) == 0 {
+		s.lock.RLock()
+		defer s.lock.RUnlock()
+		return s.nilCount, nil
+	}
+
+	if !opts[0].DontLock {
+		s.lock.RLock()
+		defer s.lock.RUnlock()
+	}
+
+	var (
+		ctx context.Context
+		r   *Range
+	)
+
+	if opts[0].Ctx == nil {
+		ctx = context.Background()
+	} else {
+		ctx = opts[0].Ctx
+	}
+
+	if opts[0].R == nil {
+		r = &Range{}
+	} else {
+		r = opts[0].R
+	}
+
+	start, end, err := r.Limits(len(s.codes))
+	if err != nil {
+		return 0, err
+	}
+
+	if start == 0 && end == len(s.codes)-1 {
+		return s.nilCount, nil
+	}
+
+	var nilCount int
+
+	for i := start; i <= end; i++ {
+		if err := ctx.Err(); err != nil {
+			return 0, err
+		}
+
+		if s.codes[i] == nilCode {
+
+			if opts[0].StopAtOneNil {
+				return 1, nil
+			}
+
+			nilCount++
+		}
+	}
+
+	return nilCount, nil
+}
+
+// ToSeriesString will convert the Series to a SeriesString.
+// The operation does not lock the Series.
+func (s *SeriesCategorical) ToSeriesString(ctx context.Context, removeNil bool, conv ...func(interface{}) (*string, error)) (*SeriesString, error) {
+
+	ec := NewErrorCollection()
+
+	ss := NewSeriesString(s.name, &SeriesInit{Capacity: s.NRows(dontLock)})
+
+	for row, code := range s.codes {
+
+		// Cancel operation
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		if code == nilCode {
+			if removeNil {
+				continue
+			}
+			ss.values = append(ss.values, nil)
+			ss.nilCount++
+		} else {
+			rowVal := s.categories[code]
+			if len(conv) == 0 {
+				ss.values = append(ss.values, &rowVal)
+			} else {
+				cv, err := conv[0](rowVal)
+				if err != nil {
+					// interpret as nil
+					ss.values = append(ss.values, nil)
+					ss.nilCount++
+					ec.AddError(&RowError{Row: row, Err: err}, false)
+				} else {
+					if cv == nil {
+						ss.values = append(ss.values, nil)
+						ss.nilCount++
+					} else {
+						ss.values = append(ss.values, cv)
+					}
+				}
+			}
+		}
+	}
+
+	if !ec.IsNil(false) {
+		return ss, ec
+	}
+
+	return ss, nil
+}
+
+// ToSeriesMixed will convert the Series to a SeriesMIxed.
+// The operation does not lock the Series.
+func (s *SeriesCategorical) ToSeriesMixed(ctx context.Context, removeNil bool, conv ...func(interface{}) (interface{}, error)) (*SeriesMixed, error) {
+	ec := NewErrorCollection()
+
+	ss := NewSeriesMixed(s.name, &SeriesInit{Capacity: s.NRows(dontLock)})
+
+	for row, code := range s.codes {
+
+		// Cancel operation
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		if code == nilCode {
+			if removeNil {
+				continue
+			}
+			ss.values = append(ss.values, nil)
+			ss.nilCount++
+		} else {
+			rowVal := s.categories[code]
+			if len(conv) == 0 {
+				ss.values = append(ss.values, rowVal)
+			} else {
+				cv, err := conv[0](rowVal)
+				if err != nil {
+					// interpret as nil
+					ss.values = append(ss.values, nil)
+					ss.nilCount++
+					ec.AddError(&RowError{Row: row, Err: err}, false)
+				} else {
+					if cv == nil {
+						ss.nilCount++
+					}
+					ss.values = append(ss.values, cv)
+				}
+			}
+		}
+	}
+
+	if !ec.IsNil(false) {
+		return ss, ec
+	}
+
+	return ss, nil
+}
+
+// IsEqual returns true if s2's values are equal to s.
+// The categories themselves are not compared.
+func (s *SeriesCategorical) IsEqual(ctx context.Context, s2 Series, opts ...IsEqualOptions) (bool, error) {
+	if len(opts) == 0 || !opts[0].DontLock {
+		s.lock.RLock()
+		defer s.lock.RUnlock()
+	}
+
+	// Check type
+	cs, ok := s2.(*SeriesCategorical)
+	if !ok {
+		return false, nil
+	}
+
+	// Check number of values
+	if len(s.codes) != len(cs.codes) {
+		return false, nil
+	}
+
+	// Check name
+	if len(opts) != 0 && opts[0].CheckName {
+		if s.name != cs.name {
+			return false, nil
+		}
+	}
+
+	// Check values
+	for i, code := range s.codes {
+		if err := ctx.Err(); err != nil {
+			return false, err
+		}
+
+		if code == nilCode || cs.codes[i] == nilCode {
+			if code != cs.codes[i] {
+				return false, nil
+			}
+			continue
+		}
+
+		if s.categories[code] != cs.categories[cs.codes[i]] {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
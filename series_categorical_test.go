@@ -0,0 +1,70 @@
+// Copyright 2018-20 PJ Engineering and Business Solutions Pty. Ltd. All rights reserved.
+
+package dataframe
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestSeriesCategorical(t *testing.T) {
+	ctx := context.Background()
+
+	s := NewSeriesCategorical("size", nil, "medium", "small", nil, "large", "small")
+
+	if !cmp.Equal(s.Categories(), []string{"medium", "small", "large"}) {
+		t.Errorf("wrong val: expected: %v actual: %v", []string{"medium", "small", "large"}, s.Categories())
+	}
+
+	// Unordered categories are sorted lexically
+	cp := s.Copy().(*SeriesCategorical)
+	cp.Sort(ctx)
+	expected := NewSeriesCategorical("size", nil, nil, "large", "medium", "small", "small")
+	if eq, _ := cp.IsEqual(ctx, expected, IsEqualOptions{CheckName: true}); !eq {
+		t.Errorf("wrong val: expected: %v actual: %v", expected, cp)
+	}
+
+	// Ordered categories are sorted by position
+	err := s.SetCategories([]string{"small", "medium", "large"}, true)
+	if err != nil {
+		t.Fatalf("wrong err: expected: %v actual: %v", nil, err)
+	}
+
+	if !s.IsLessThanFunc("medium", "large") || s.IsLessThanFunc("large", "small") {
+		t.Errorf("wrong val: expected ordered comparison")
+	}
+
+	s.Sort(ctx, SortOptions{Desc: true})
+	expected = NewSeriesCategorical("size", nil, "large", "medium", "small", "small", nil)
+	if eq, _ := s.IsEqual(ctx, expected, IsEqualOptions{CheckName: true}); !eq {
+		t.Errorf("wrong val: expected: %v actual: %v", expected, s)
+	}
+
+	if s.Code(0) != 2 || s.Code(4) != -1 {
+		t.Errorf("wrong val: expected: %v actual: %v", []int{2, -1}, []int{s.Code(0), s.Code(4)})
+	}
+
+	// Missing category
+	if err := s.SetCategories([]string{"small", "large"}, false); err == nil {
+		t.Errorf("wrong err: expected: %v actual: %v", "error", err)
+	}
+
+	// Conversion
+	ss, _ := s.ToSeriesString(ctx, true)
+	expectedS := NewSeriesString("size", nil, "large", "medium", "small", "small")
+	if eq, _ := ss.IsEqual(ctx, expectedS); !eq {
+		t.Errorf("wrong val: expected: %v actual: %v", expectedS, ss)
+	}
+
+	cs, _ := NewSeriesString("size", nil, "a", nil, "b", "a").ToSeriesCategorical(ctx, false)
+	expected = NewSeriesCategorical("size", nil, "a", nil, "b", "a")
+	if eq, _ := cs.IsEqual(ctx, expected); !eq {
+		t.Errorf("wrong val: expected: %v actual: %v", expected, cs)
+	}
+
+	if nc, _ := cs.NilCount(); nc != 1 || len(cs.Categories()) != 2 {
+		t.Errorf("wrong val: expected: %v actual: %v", 1, nc)
+	}
+}
@@ -4,6 +4,9 @@ package dataframe
 
 import (
 	"context"
+	"errors"
+	"math"
+	"sort"
 )
 
 // Mean returns the mean. All non-nil values are ignored.
@@ -111,3 +114,750 @@ func (s *SeriesInt64) Sum(ctx context.Context) (float64, error) {
 
 	return float64(sum), nil
 }
+
+// StatsOptions modifies the behavior of the statistical functions.
+type StatsOptions struct {
+
+	// R is used to limit the range.
+	R *Range
+
+	// NoSkipNil can be set so that nil values are not ignored.
+	// If a nil value is encountered, the result is nil (NaN).
+	NoSkipNil bool
+
+	// DontLock can be set to true if the Series should not be locked.
+	DontLock bool
+}
+
+// statsValues returns a copy of the values within the range and the starting row.
+// nil values are represented as NaN.
+func (s *SeriesFloat64) statsValues(opts []StatsOptions) ([]float64, int, StatsOptions, error) {
+
+	if len(opts) == 0 {
+		opts = append(opts, StatsOptions{})
+	}
+
+	if !opts[0].DontLock {
+		s.lock.RLock()
+		defer s.lock.RUnlock()
+	}
+
+	start, end, err := statsLimits(len(s.Values), opts[0].R)
+	if err != nil {
+		return nil, 0, opts[0], err
+	}
+
+	return append([]float64(nil), s.Values[start:end]...), start, opts[0], nil
+}
+
+// statsValues returns a copy of the values within the range and the starting row.
+// nil values are represented as NaN.
+func (s *SeriesInt64) statsValues(opts []StatsOptions) ([]float64, int, StatsOptions, error) {
+
+	if len(opts) == 0 {
+		opts = append(opts, StatsOptions{})
+	}
+
+	if !opts[0].DontLock {
+		s.lock.RLock()
+		defer s.lock.RUnlock()
+	}
+
+	start, end, err := statsLimits(len(s.values), opts[0].R)
+	if err != nil {
+		return nil, 0, opts[0], err
+	}
+
+	vals := make([]float64, 0, end-start)
+	for _, v := range s.values[start:end] {
+		if v == nil {
+			vals = append(vals, nan())
+		} else {
+			vals = append(vals, float64(*v))
+		}
+	}
+
+	return vals, start, opts[0], nil
+}
+
+// intValues returns a copy of the values within the range.
+func (s *SeriesInt64) intValues(opts []StatsOptions) ([]*int64, StatsOptions, error) {
+
+	if len(opts) == 0 {
+		opts = append(opts, StatsOptions{})
+	}
+
+	if !opts[0].DontLock {
+		s.lock.RLock()
+		defer s.lock.RUnlock()
+	}
+
+	start, end, err := statsLimits(len(s.values), opts[0].R)
+	if err != nil {
+		return nil, opts[0], err
+	}
+
+	return append([]*int64(nil), s.values[start:end]...), opts[0], nil
+}
+
+// Min returns the smallest value. If there are no values, a NaN is returned.
+func (s *SeriesFloat64) Min(ctx context.Context, opts ...StatsOptions) (float64, error) {
+	vals, _, o, err := s.statsValues(opts)
+	if err != nil {
+		return 0, err
+	}
+	return statsMin(ctx, vals, o.NoSkipNil)
+}
+
+// Max returns the largest value. If there are no values, a NaN is returned.
+func (s *SeriesFloat64) Max(ctx context.Context, opts ...StatsOptions) (float64, error) {
+	vals, _, o, err := s.statsValues(opts)
+	if err != nil {
+		return 0, err
+	}
+	return statsMax(ctx, vals, o.NoSkipNil)
+}
+
+// ArgMin returns the row of the smallest value. If there are no values, -1 is returned.
+func (s *SeriesFloat64) ArgMin(ctx context.Context, opts ...StatsOptions) (int, error) {
+	vals, start, o, err := s.statsValues(opts)
+	if err != nil {
+		return 0, err
+	}
+	return statsArg(ctx, vals, start, o.NoSkipNil, func(a, b float64) bool { return a < b })
+}
+
+// ArgMax returns the row of the largest value. If there are no values, -1 is returned.
+func (s *SeriesFloat64) ArgMax(ctx context.Context, opts ...StatsOptions) (int, error) {
+	vals, start, o, err := s.statsValues(opts)
+	if err != nil {
+		return 0, err
+	}
+	return statsArg(ctx, vals, start, o.NoSkipNil, func(a, b float64) bool { return a > b })
+}
+
+// Median returns the median. If there are no values, a NaN is returned.
+func (s *SeriesFloat64) Median(ctx context.Context, opts ...StatsOptions) (float64, error) {
+	return s.Quantile(ctx, 0.5, opts...)
+}
+
+// Quantile returns the q-th quantile using linear interpolation. q must be between 0 and 1.
+// If there are no values, a NaN is returned.
+func (s *SeriesFloat64) Quantile(ctx context.Context, q float64, opts ...StatsOptions) (float64, error) {
+	vals, _, o, err := s.statsValues(opts)
+	if err != nil {
+		return 0, err
+	}
+	return statsQuantile(ctx, vals, q, o.NoSkipNil)
+}
+
+// Variance returns the variance. The divisor used is N - ddof, where N is the number of values.
+// For the sample variance, set ddof to 1.
+func (s *SeriesFloat64) Variance(ctx context.Context, ddof int, opts ...StatsOptions) (float64, error) {
+	vals, _, o, err := s.statsValues(opts)
+	if err != nil {
+		return 0, err
+	}
+	return statsVariance(ctx, vals, ddof, o.NoSkipNil)
+}
+
+// StdDev returns the standard deviation. The divisor used is N - ddof, where N is the number of values.
+// For the sample standard deviation, set ddof to 1.
+func (s *SeriesFloat64) StdDev(ctx context.Context, ddof int, opts ...StatsOptions) (float64, error) {
+	v, err := s.Variance(ctx, ddof, opts...)
+	if err != nil {
+		return 0, err
+	}
+	return math.Sqrt(v), nil
+}
+
+// Skew returns the unbiased skewness. At least 3 values are required, otherwise a NaN is returned.
+func (s *SeriesFloat64) Skew(ctx context.Context, opts ...StatsOptions) (float64, error) {
+	vals, _, o, err := s.statsValues(opts)
+	if err != nil {
+		return 0, err
+	}
+	return statsSkew(ctx, vals, o.NoSkipNil)
+}
+
+// Kurtosis returns the unbiased excess kurtosis. At least 4 values are required, otherwise a NaN is returned.
+func (s *SeriesFloat64) Kurtosis(ctx context.Context, opts ...StatsOptions) (float64, error) {
+	vals, _, o, err := s.statsValues(opts)
+	if err != nil {
+		return 0, err
+	}
+	return statsKurtosis(ctx, vals, o.NoSkipNil)
+}
+
+// Mode returns the most frequently occurring values in ascending order.
+func (s *SeriesFloat64) Mode(ctx context.Context, opts ...StatsOptions) ([]float64, error) {
+	vals, _, o, err := s.statsValues(opts)
+	if err != nil {
+		return nil, err
+	}
+	return statsMode(ctx, vals, o.NoSkipNil)
+}
+
+// CumSum returns a new Series containing the cumulative sum.
+// nil values remain nil unless NoSkipNil is set, in which case all subsequent values are nil.
+func (s *SeriesFloat64) CumSum(ctx context.Context, opts ...StatsOptions) (*SeriesFloat64, error) {
+	vals, _, o, err := s.statsValues(opts)
+	if err != nil {
+		return nil, err
+	}
+	return statsCumulative(ctx, s.name, vals, o.NoSkipNil, func(acc, v float64) float64 { return acc + v })
+}
+
+// CumProd returns a new Series containing the cumulative product.
+// nil values remain nil unless NoSkipNil is set, in which case all subsequent values are nil.
+func (s *SeriesFloat64) CumProd(ctx context.Context, opts ...StatsOptions) (*SeriesFloat64, error) {
+	vals, _, o, err := s.statsValues(opts)
+	if err != nil {
+		return nil, err
+	}
+	return statsCumulative(ctx, s.name, vals, o.NoSkipNil, func(acc, v float64) float64 { return acc * v })
+}
+
+// CumMin returns a new Series containing the cumulative minimum.
+// nil values remain nil unless NoSkipNil is set, in which case all subsequent values are nil.
+func (s *SeriesFloat64) CumMin(ctx context.Context, opts ...StatsOptions) (*SeriesFloat64, error) {
+	vals, _, o, err := s.statsValues(opts)
+	if err != nil {
+		return nil, err
+	}
+	return statsCumulative(ctx, s.name, vals, o.NoSkipNil, math.Min)
+}
+
+// CumMax returns a new Series containing the cumulative maximum.
+// nil values remain nil unless NoSkipNil is set, in which case all subsequent values are nil.
+func (s *SeriesFloat64) CumMax(ctx context.Context, opts ...StatsOptions) (*SeriesFloat64, error) {
+	vals, _, o, err := s.statsValues(opts)
+	if err != nil {
+		return nil, err
+	}
+	return statsCumulative(ctx, s.name, vals, o.NoSkipNil, math.Max)
+}
+
+// Diff returns a new Series containing the difference between each value and the value periods rows earlier.
+// periods can be negative. Rows without a corresponding earlier value are nil.
+func (s *SeriesFloat64) Diff(ctx context.Context, periods int, opts ...StatsOptions) (*SeriesFloat64, error) {
+	vals, _, _, err := s.statsValues(opts)
+	if err != nil {
+		return nil, err
+	}
+	return statsShifted(ctx, s.name, vals, periods, func(curr, prev float64) float64 { return curr - prev })
+}
+
+// PctChange returns a new Series containing the fractional change between each value and the value periods rows earlier.
+// periods can be negative. Rows without a corresponding earlier value are nil.
+func (s *SeriesFloat64) PctChange(ctx context.Context, periods int, opts ...StatsOptions) (*SeriesFloat64, error) {
+	vals, _, _, err := s.statsValues(opts)
+	if err != nil {
+		return nil, err
+	}
+	return statsShifted(ctx, s.name, vals, periods, func(curr, prev float64) float64 { return curr/prev - 1 })
+}
+
+// Min returns the smallest value. If there are no values, a NaN is returned.
+func (s *SeriesInt64) Min(ctx context.Context, opts ...StatsOptions) (float64, error) {
+	vals, _, o, err := s.statsValues(opts)
+	if err != nil {
+		return 0, err
+	}
+	return statsMin(ctx, vals, o.NoSkipNil)
+}
+
+// Max returns the largest value. If there are no values, a NaN is returned.
+func (s *SeriesInt64) Max(ctx context.Context, opts ...StatsOptions) (float64, error) {
+	vals, _, o, err := s.statsValues(opts)
+	if err != nil {
+		return 0, err
+	}
+	return statsMax(ctx, vals, o.NoSkipNil)
+}
+
+// ArgMin returns the row of the smallest value. If there are no values, -1 is returned.
+func (s *SeriesInt64) ArgMin(ctx context.Context, opts ...StatsOptions) (int, error) {
+	vals, start, o, err := s.statsValues(opts)
+	if err != nil {
+		return 0, err
+	}
+	return statsArg(ctx, vals, start, o.NoSkipNil, func(a, b float64) bool { return a < b })
+}
+
+// ArgMax returns the row of the largest value. If there are no values, -1 is returned.
+func (s *SeriesInt64) ArgMax(ctx context.Context, opts ...StatsOptions) (int, error) {
+	vals, start, o, err := s.statsValues(opts)
+	if err != nil {
+		return 0, err
+	}
+	return statsArg(ctx, vals, start, o.NoSkipNil, func(a, b float64) bool { return a > b })
+}
+
+// Median returns the median. If there are no values, a NaN is returned.
+func (s *SeriesInt64) Median(ctx context.Context, opts ...StatsOptions) (float64, error) {
+	return s.Quantile(ctx, 0.5, opts...)
+}
+
+// Quantile returns the q-th quantile using linear interpolation. q must be between 0 and 1.
+// If there are no values, a NaN is returned.
+func (s *SeriesInt64) Quantile(ctx context.Context, q float64, opts ...StatsOptions) (float64, error) {
+	vals, _, o, err := s.statsValues(opts)
+	if err != nil {
+		return 0, err
+	}
+	return statsQuantile(ctx, vals, q, o.NoSkipNil)
+}
+
+// Variance returns the variance. The divisor used is N - ddof, where N is the number of values.
+// For the sample variance, set ddof to 1.
+func (s *SeriesInt64) Variance(ctx context.Context, ddof int, opts ...StatsOptions) (float64, error) {
+	vals, _, o, err := s.statsValues(opts)
+	if err != nil {
+		return 0, err
+	}
+	return statsVariance(ctx, vals, ddof, o.NoSkipNil)
+}
+
+// StdDev returns the standard deviation. The divisor used is N - ddof, where N is the number of values.
+// For the sample standard deviation, set ddof to 1.
+func (s *SeriesInt64) StdDev(ctx context.Context, ddof int, opts ...StatsOptions) (float64, error) {
+	v, err := s.Variance(ctx, ddof, opts...)
+	if err != nil {
+		return 0, err
+	}
+	return math.Sqrt(v), nil
+}
+
+// Skew returns the unbiased skewness. At least 3 values are required, otherwise a NaN is returned.
+func (s *SeriesInt64) Skew(ctx context.Context, opts ...StatsOptions) (float64, error) {
+	vals, _, o, err := s.statsValues(opts)
+	if err != nil {
+		return 0, err
+	}
+	return statsSkew(ctx, vals, o.NoSkipNil)
+}
+
+// Kurtosis returns the unbiased excess kurtosis. At least 4 values are required, otherwise a NaN is returned.
+func (s *SeriesInt64) Kurtosis(ctx context.Context, opts ...StatsOptions) (float64, error) {
+	vals, _, o, err := s.statsValues(opts)
+	if err != nil {
+		return 0, err
+	}
+	return statsKurtosis(ctx, vals, o.NoSkipNil)
+}
+
+// Mode returns the most frequently occurring values in ascending order.
+func (s *SeriesInt64) Mode(ctx context.Context, opts ...StatsOptions) ([]int64, error) {
+	vals, o, err := s.intValues(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	counts := map[int64]int{}
+	var max int
+	for _, v := range vals {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		if v == nil {
+			if o.NoSkipNil {
+				return []int64{}, nil
+			}
+			continue
+		}
+
+		counts[*v]++
+		if counts[*v] > max {
+			max = counts[*v]
+		}
+	}
+
+	out := []int64{}
+	for v, c := range counts {
+		if c == max {
+			out = append(out, v)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i] < out[j] })
+
+	return out, nil
+}
+
+// CumSum returns a new Series containing the cumulative sum.
+// nil values remain nil unless NoSkipNil is set, in which case all subsequent values are nil.
+func (s *SeriesInt64) CumSum(ctx context.Context, opts ...StatsOptions) (*SeriesInt64, error) {
+	return s.cumulative(ctx, opts, func(acc, v int64) int64 { return acc + v })
+}
+
+// CumProd returns a new Series containing the cumulative product.
+// nil values remain nil unless NoSkipNil is set, in which case all subsequent values are nil.
+func (s *SeriesInt64) CumProd(ctx context.Context, opts ...StatsOptions) (*SeriesInt64, error) {
+	return s.cumulative(ctx, opts, func(acc, v int64) int64 { return acc * v })
+}
+
+// CumMin returns a new Series containing the cumulative minimum.
+// nil values remain nil unless NoSkipNil is set, in which case all subsequent values are nil.
+func (s *SeriesInt64) CumMin(ctx context.Context, opts ...StatsOptions) (*SeriesInt64, error) {
+	return s.cumulative(ctx, opts, func(acc, v int64) int64 {
+		if v < acc {
+			return v
+		}
+		return acc
+	})
+}
+
+// CumMax returns a new Series containing the cumulative maximum.
+// nil values remain nil unless NoSkipNil is set, in which case all subsequent values are nil.
+func (s *SeriesInt64) CumMax(ctx context.Context, opts ...StatsOptions) (*SeriesInt64, error) {
+	return s.cumulative(ctx, opts, func(acc, v int64) int64 {
+		if v > acc {
+			return v
+		}
+		return acc
+	})
+}
+
+func (s *SeriesInt64) cumulative(ctx context.Context, opts []StatsOptions, fn func(acc, v int64) int64) (*SeriesInt64, error) {
+	vals, o, err := s.intValues(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	out := NewSeriesInt64(s.name, &SeriesInit{Capacity: len(vals)})
+
+	var (
+		acc     *int64
+		nilSeen bool
+	)
+
+	for _, v := range vals {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		if v == nil || nilSeen {
+			if o.NoSkipNil {
+				nilSeen = true
+			}
+			out.Append(nil, dontLock)
+			continue
+		}
+
+		if acc == nil {
+			acc = &[]int64{*v}[0]
+		} else {
+			*acc = fn(*acc, *v)
+		}
+		out.Append(*acc, dontLock)
+	}
+
+	return out, nil
+}
+
+// Diff returns a new Series containing the difference between each value and the value periods rows earlier.
+// periods can be negative. Rows without a corresponding earlier value are nil.
+func (s *SeriesInt64) Diff(ctx context.Context, periods int, opts ...StatsOptions) (*SeriesInt64, error) {
+	vals, _, err := s.intValues(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	out := NewSeriesInt64(s.name, &SeriesInit{Capacity: len(vals)})
+
+	for i, v := range vals {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		j := i - periods
+		if v == nil || j < 0 || j >= len(vals) || vals[j] == nil {
+			out.Append(nil, dontLock)
+			continue
+		}
+		out.Append(*v-*vals[j], dontLock)
+	}
+
+	return out, nil
+}
+
+// PctChange returns a new Series containing the fractional change between each value and the value periods rows earlier.
+// periods can be negative. Rows without a corresponding earlier value are nil.
+func (s *SeriesInt64) PctChange(ctx context.Context, periods int, opts ...StatsOptions) (*SeriesFloat64, error) {
+	vals, _, _, err := s.statsValues(opts)
+	if err != nil {
+		return nil, err
+	}
+	return statsShifted(ctx, s.name, vals, periods, func(curr, prev float64) float64 { return curr/prev - 1 })
+}
+
+// statsLimits returns the start (inclusive) and end (exclusive) rows.
+// A Series with no rows returns an empty range.
+func statsLimits(length int, r *Range) (int, int, error) {
+	if length == 0 {
+		return 0, 0, nil
+	}
+
+	if r == nil {
+		r = &Range{}
+	}
+
+	start, end, err := r.Limits(length)
+	if err != nil {
+		return 0, 0, err
+	}
+	return start, end + 1, nil
+}
+
+// statsNonNil returns the values that are not NaN. If noSkipNil is set and a NaN
+// is found, ok is false.
+func statsNonNil(ctx context.Context, vals []float64, noSkipNil bool) (_ []float64, ok bool, _ error) {
+	out := make([]float64, 0, len(vals))
+	for _, v := range vals {
+		if err := ctx.Err(); err != nil {
+			return nil, false, err
+		}
+
+		if isNaN(v) {
+			if noSkipNil {
+				return nil, false, nil
+			}
+			continue
+		}
+		out = append(out, v)
+	}
+	return out, true, nil
+}
+
+func statsMin(ctx context.Context, vals []float64, noSkipNil bool) (float64, error) {
+	vals, ok, err := statsNonNil(ctx, vals, noSkipNil)
+	if err != nil {
+		return 0, err
+	}
+	if !ok || len(vals) == 0 {
+		return nan(), nil
+	}
+
+	min := vals[0]
+	for _, v := range vals[1:] {
+		if v < min {
+			min = v
+		}
+	}
+	return min, nil
+}
+
+func statsMax(ctx context.Context, vals []float64, noSkipNil bool) (float64, error) {
+	vals, ok, err := statsNonNil(ctx, vals, noSkipNil)
+	if err != nil {
+		return 0, err
+	}
+	if !ok || len(vals) == 0 {
+		return nan(), nil
+	}
+
+	max := vals[0]
+	for _, v := range vals[1:] {
+		if v > max {
+			max = v
+		}
+	}
+	return max, nil
+}
+
+// statsArg returns the row of the first value that is better than all other values.
+func statsArg(ctx context.Context, vals []float64, start int, noSkipNil bool, better func(a, b float64) bool) (int, error) {
+	arg := -1
+	for i, v := range vals {
+		if err := ctx.Err(); err != nil {
+			return 0, err
+		}
+
+		if isNaN(v) {
+			if noSkipNil {
+				return -1, nil
+			}
+			continue
+		}
+
+		if arg == -1 || better(v, vals[arg-start]) {
+			arg = start + i
+		}
+	}
+	return arg, nil
+}
+
+func statsQuantile(ctx context.Context, vals []float64, q float64, noSkipNil bool) (float64, error) {
+	if q < 0 || q > 1 {
+		return 0, errors.New("q must be between 0 and 1")
+	}
+
+	vals, ok, err := statsNonNil(ctx, vals, noSkipNil)
+	if err != nil {
+		return 0, err
+	}
+	if !ok || len(vals) == 0 {
+		return nan(), nil
+	}
+
+	sort.Float64s(vals)
+	return quantile(vals, q), nil
+}
+
+func statsVariance(ctx context.Context, vals []float64, ddof int, noSkipNil bool) (float64, error) {
+	vals, ok, err := statsNonNil(ctx, vals, noSkipNil)
+	if err != nil {
+		return 0, err
+	}
+
+	n := len(vals)
+	if !ok || n-ddof <= 0 {
+		return nan(), nil
+	}
+
+	_, m2, _, _ := statsMoments(vals)
+	return m2 / float64(n-ddof), nil
+}
+
+func statsSkew(ctx context.Context, vals []float64, noSkipNil bool) (float64, error) {
+	vals, ok, err := statsNonNil(ctx, vals, noSkipNil)
+	if err != nil {
+		return 0, err
+	}
+
+	n := float64(len(vals))
+	if !ok || n < 3 {
+		return nan(), nil
+	}
+
+	_, m2, m3, _ := statsMoments(vals)
+	if m2 == 0 {
+		return 0, nil
+	}
+
+	m2, m3 = m2/n, m3/n
+	g1 := m3 / math.Pow(m2, 1.5)
+	return g1 * math.Sqrt(n*(n-1)) / (n - 2), nil
+}
+
+func statsKurtosis(ctx context.Context, vals []float64, noSkipNil bool) (float64, error) {
+	vals, ok, err := statsNonNil(ctx, vals, noSkipNil)
+	if err != nil {
+		return 0, err
+	}
+
+	n := float64(len(vals))
+	if !ok || n < 4 {
+		return nan(), nil
+	}
+
+	_, m2, _, m4 := statsMoments(vals)
+	if m2 == 0 {
+		return 0, nil
+	}
+
+	adj := 3 * (n - 1) * (n - 1) / ((n - 2) * (n - 3))
+	numer := n * (n + 1) * (n - 1) * m4
+	denom := (n - 2) * (n - 3) * m2 * m2
+	return numer/denom - adj, nil
+}
+
+// statsMoments returns the mean and the sum of the 2nd, 3rd and 4th powers of the deviations from the mean.
+func statsMoments(vals []float64) (mean, m2, m3, m4 float64) {
+	for _, v := range vals {
+		mean = mean + v
+	}
+	mean = mean / float64(len(vals))
+
+	for _, v := range vals {
+		d := v - mean
+		m2 = m2 + d*d
+		m3 = m3 + d*d*d
+		m4 = m4 + d*d*d*d
+	}
+	return
+}
+
+func statsMode(ctx context.Context, vals []float64, noSkipNil bool) ([]float64, error) {
+	vals, ok, err := statsNonNil(ctx, vals, noSkipNil)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return []float64{}, nil
+	}
+
+	counts := map[float64]int{}
+	var max int
+	for _, v := range vals {
+		counts[v]++
+		if counts[v] > max {
+			max = counts[v]
+		}
+	}
+
+	out := []float64{}
+	for v, c := range counts {
+		if c == max {
+			out = append(out, v)
+		}
+	}
+	sort.Float64s(out)
+
+	return out, nil
+}
+
+func statsCumulative(ctx context.Context, name string, vals []float64, noSkipNil bool, fn func(acc, v float64) float64) (*SeriesFloat64, error) {
+	out := NewSeriesFloat64(name, &SeriesInit{Capacity: len(vals)})
+
+	var (
+		acc     float64
+		started bool
+		nilSeen bool
+	)
+
+	for _, v := range vals {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		if isNaN(v) || nilSeen {
+			if noSkipNil {
+				nilSeen = true
+			}
+			out.Append(nil, dontLock)
+			continue
+		}
+
+		if !started {
+			acc = v
+			started = true
+		} else {
+			acc = fn(acc, v)
+		}
+		out.Append(acc, dontLock)
+	}
+
+	return out, nil
+}
+
+// statsShifted returns fn applied to each value and the value periods rows earlier.
+func statsShifted(ctx context.Context, name string, vals []float64, periods int, fn func(curr, prev float64) float64) (*SeriesFloat64, error) {
+	out := NewSeriesFloat64(name, &SeriesInit{Capacity: len(vals)})
+
+	for i, v := range vals {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		j := i - periods
+		if j < 0 || j >= len(vals) || isNaN(v) || isNaN(vals[j]) {
+			out.Append(nil, dontLock)
+			continue
+		}
+		out.Append(fn(v, vals[j]), dontLock)
+	}
+
+	return out, nil
+}
@@ -0,0 +1,121 @@
+// Copyright 2018-20 PJ Engineering and Business Solutions Pty. Ltd. All rights reserved.
+
+package dataframe
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestSeriesStats(t *testing.T) {
+	ctx := context.Background()
+
+	sf := NewSeriesFloat64("", nil, 4.0, nil, 2.0, 8.0, 2.0, 6.0)
+	si := NewSeriesInt64("", nil, 4, nil, 2, 8, 2, 6)
+
+	r := RangeFinite(2, -1)
+
+	floatTests := []struct {
+		name     string
+		fn       func(StatsOptions) (float64, error)
+		opts     StatsOptions
+		expected float64
+	}{
+		{"min", func(o StatsOptions) (float64, error) { return sf.Min(ctx, o) }, StatsOptions{}, 2},
+		{"max", func(o StatsOptions) (float64, error) { return si.Max(ctx, o) }, StatsOptions{}, 8},
+		{"max nil", func(o StatsOptions) (float64, error) { return sf.Max(ctx, o) }, StatsOptions{NoSkipNil: true}, math.NaN()},
+		{"median", func(o StatsOptions) (float64, error) { return sf.Median(ctx, o) }, StatsOptions{}, 4},
+		{"quantile", func(o StatsOptions) (float64, error) { return si.Quantile(ctx, 0.25, o) }, StatsOptions{R: &r}, 2},
+		{"variance", func(o StatsOptions) (float64, error) { return sf.Variance(ctx, 1, o) }, StatsOptions{}, 6.8},
+		{"std dev", func(o StatsOptions) (float64, error) { return si.StdDev(ctx, 0, o) }, StatsOptions{R: &r}, math.Sqrt(6.75)},
+		{"skew", func(o StatsOptions) (float64, error) { return sf.Skew(ctx, o) }, StatsOptions{}, 0.5413871},
+		{"kurtosis", func(o StatsOptions) (float64, error) { return si.Kurtosis(ctx, o) }, StatsOptions{}, -1.4878893},
+	}
+
+	for _, tc := range floatTests {
+		actual, err := tc.fn(tc.opts)
+		if err != nil {
+			t.Errorf("%s: wrong err: expected: %v actual: %v", tc.name, nil, err)
+			continue
+		}
+
+		if math.IsNaN(tc.expected) && math.IsNaN(actual) {
+			continue
+		}
+
+		if math.Abs(actual-tc.expected) > 1e-6 {
+			t.Errorf("%s: wrong val: expected: %v actual: %v", tc.name, tc.expected, actual)
+		}
+	}
+
+	// Arg
+	if row, _ := sf.ArgMin(ctx); row != 2 {
+		t.Errorf("wrong val: expected: %v actual: %v", 2, row)
+	}
+
+	if row, _ := si.ArgMax(ctx, StatsOptions{R: &r}); row != 3 {
+		t.Errorf("wrong val: expected: %v actual: %v", 3, row)
+	}
+
+	// Mode
+	if modes, _ := si.Mode(ctx); !cmp.Equal(modes, []int64{2}) {
+		t.Errorf("wrong val: expected: %v actual: %v", []int64{2}, modes)
+	}
+
+	// Cumulative
+	cs, _ := si.CumSum(ctx)
+	expected := NewSeriesInt64("", nil, 4, nil, 6, 14, 16, 22)
+	if eq, _ := cs.IsEqual(ctx, expected); !eq {
+		t.Errorf("wrong val: expected: %v actual: %v", expected, cs)
+	}
+
+	cm, _ := sf.CumMax(ctx, StatsOptions{NoSkipNil: true})
+	expectedF := NewSeriesFloat64("", nil, 4.0, nil, nil, nil, nil, nil)
+	if eq, _ := cm.IsEqual(ctx, expectedF); !eq {
+		t.Errorf("wrong val: expected: %v actual: %v", expectedF, cm)
+	}
+
+	// Diff
+	d, _ := sf.Diff(ctx, 1)
+	expectedF = NewSeriesFloat64("", nil, nil, nil, nil, 6.0, -6.0, 4.0)
+	if eq, _ := d.IsEqual(ctx, expectedF); !eq {
+		t.Errorf("wrong val: expected: %v actual: %v", expectedF, d)
+	}
+
+	pc, _ := si.PctChange(ctx, 2)
+	expectedF = NewSeriesFloat64("", nil, nil, nil, -0.5, nil, 0.0, -0.25)
+	if eq, _ := pc.IsEqual(ctx, expectedF); !eq {
+		t.Errorf("wrong val: expected: %v actual: %v", expectedF, pc)
+	}
+
+	// Time
+	base := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	st := NewSeriesTime("", nil, base.Add(time.Hour), nil, base, base.Add(3*time.Hour))
+
+	if min, _ := st.Min(ctx); min == nil || !min.Equal(base) {
+		t.Errorf("wrong val: expected: %v actual: %v", base, min)
+	}
+
+	if med, _ := st.Median(ctx); med == nil || !med.Equal(base.Add(time.Hour)) {
+		t.Errorf("wrong val: expected: %v actual: %v", base.Add(time.Hour), med)
+	}
+
+	// Nanoseconds are retained when the middle times are averaged
+	sn := NewSeriesTime("", nil, base.Add(4*time.Nanosecond), base.Add(time.Nanosecond))
+	if med, _ := sn.Median(ctx); med == nil || !med.Equal(base.Add(2*time.Nanosecond)) {
+		t.Errorf("wrong val: expected: %v actual: %v", base.Add(2*time.Nanosecond), med)
+	}
+
+	if max, _ := st.Max(ctx); max == nil || !max.Equal(base.Add(3*time.Hour)) {
+		t.Errorf("wrong val: expected: %v actual: %v", base.Add(3*time.Hour), max)
+	}
+
+	td, _ := st.Diff(ctx, 1)
+	if td.Value(3) != 3*time.Hour || td.Value(1) != nil {
+		t.Errorf("wrong val: expected: %v actual: %v", 3*time.Hour, td)
+	}
+}
@@ -0,0 +1,246 @@
+// Copyright 2018-20 PJ Engineering and Business Solutions Pty. Ltd. All rights reserved.
+
+package dataframe
+
+import (
+	"context"
+	"sort"
+	"time"
+)
+
+// statsValues returns a copy of the values within the range and the starting row.
+func (s *SeriesTime) statsValues(opts []StatsOptions) ([]*time.Time, int, StatsOptions, error) {
+
+	if len(opts) == 0 {
+		opts = append(opts, StatsOptions{})
+	}
+
+	if !opts[0].DontLock {
+		s.lock.RLock()
+		defer s.lock.RUnlock()
+	}
+
+	start, end, err := statsLimits(len(s.Values), opts[0].R)
+	if err != nil {
+		return nil, 0, opts[0], err
+	}
+
+	return append([]*time.Time(nil), s.Values[start:end]...), start, opts[0], nil
+}
+
+// Min returns the earliest time. If there are no values, nil is returned.
+func (s *SeriesTime) Min(ctx context.Context, opts ...StatsOptions) (*time.Time, error) {
+	_, t, err := s.arg(ctx, opts, func(a, b time.Time) bool { return a.Before(b) })
+	return t, err
+}
+
+// Max returns the latest time. If there are no values, nil is returned.
+func (s *SeriesTime) Max(ctx context.Context, opts ...StatsOptions) (*time.Time, error) {
+	_, t, err := s.arg(ctx, opts, func(a, b time.Time) bool { return a.After(b) })
+	return t, err
+}
+
+// ArgMin returns the row of the earliest time. If there are no values, -1 is returned.
+func (s *SeriesTime) ArgMin(ctx context.Context, opts ...StatsOptions) (int, error) {
+	row, _, err := s.arg(ctx, opts, func(a, b time.Time) bool { return a.Before(b) })
+	return row, err
+}
+
+// ArgMax returns the row of the latest time. If there are no values, -1 is returned.
+func (s *SeriesTime) ArgMax(ctx context.Context, opts ...StatsOptions) (int, error) {
+	row, _, err := s.arg(ctx, opts, func(a, b time.Time) bool { return a.After(b) })
+	return row, err
+}
+
+// arg returns the row and a copy of the best time. The values are read under a single lock by statsValues.
+func (s *SeriesTime) arg(ctx context.Context, opts []StatsOptions, better func(a, b time.Time) bool) (int, *time.Time, error) {
+	vals, start, o, err := s.statsValues(opts)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	arg := -1
+	for i, v := range vals {
+		if err := ctx.Err(); err != nil {
+			return 0, nil, err
+		}
+
+		if v == nil {
+			if o.NoSkipNil {
+				return -1, nil, nil
+			}
+			continue
+		}
+
+		if arg == -1 || better(*v, *vals[arg-start]) {
+			arg = start + i
+		}
+	}
+
+	if arg == -1 {
+		return -1, nil, nil
+	}
+
+	t := *vals[arg-start]
+	return arg, &t, nil
+}
+
+// Median returns the median time. If there are no values, nil is returned.
+func (s *SeriesTime) Median(ctx context.Context, opts ...StatsOptions) (*time.Time, error) {
+	vals, _, o, err := s.statsValues(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	times := make([]time.Time, 0, len(vals))
+
+	for _, v := range vals {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		if v == nil {
+			if o.NoSkipNil {
+				return nil, nil
+			}
+			continue
+		}
+
+		times = append(times, *v)
+	}
+
+	if len(times) == 0 {
+		return nil, nil
+	}
+
+	loc := times[0].Location()
+
+	sort.Slice(times, func(i, j int) bool { return times[i].Before(times[j]) })
+
+	// The two middle times are averaged by their difference, which retains nanosecond precision
+	mid := len(times) / 2
+	t := times[mid]
+	if len(times)%2 == 0 {
+		a := times[mid-1]
+		t = a.Add(t.Sub(a) / 2)
+	}
+	t = t.In(loc)
+	return &t, nil
+}
+
+// Mode returns the most frequently occurring times in ascending order.
+func (s *SeriesTime) Mode(ctx context.Context, opts ...StatsOptions) ([]time.Time, error) {
+	vals, _, o, err := s.statsValues(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	counts := map[interface{}]int{}
+	firsts := map[interface{}]time.Time{}
+	var max int
+
+	for _, v := range vals {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		if v == nil {
+			if o.NoSkipNil {
+				return []time.Time{}, nil
+			}
+			continue
+		}
+
+		key := hashKey(*v)
+		if _, exists := firsts[key]; !exists {
+			firsts[key] = *v
+		}
+		counts[key]++
+		if counts[key] > max {
+			max = counts[key]
+		}
+	}
+
+	out := []time.Time{}
+	for key, c := range counts {
+		if c == max {
+			out = append(out, firsts[key])
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Before(out[j]) })
+
+	return out, nil
+}
+
+// CumMin returns a new Series containing the cumulative earliest time.
+// nil values remain nil unless NoSkipNil is set, in which case all subsequent values are nil.
+func (s *SeriesTime) CumMin(ctx context.Context, opts ...StatsOptions) (*SeriesTime, error) {
+	return s.cumulative(ctx, opts, func(acc, v time.Time) bool { return v.Before(acc) })
+}
+
+// CumMax returns a new Series containing the cumulative latest time.
+// nil values remain nil unless NoSkipNil is set, in which case all subsequent values are nil.
+func (s *SeriesTime) CumMax(ctx context.Context, opts ...StatsOptions) (*SeriesTime, error) {
+	return s.cumulative(ctx, opts, func(acc, v time.Time) bool { return v.After(acc) })
+}
+
+func (s *SeriesTime) cumulative(ctx context.Context, opts []StatsOptions, replace func(acc, v time.Time) bool) (*SeriesTime, error) {
+	vals, _, o, err := s.statsValues(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	out := NewSeriesTime(s.name, &SeriesInit{Capacity: len(vals)})
+
+	var (
+		acc     *time.Time
+		nilSeen bool
+	)
+
+	for _, v := range vals {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		if v == nil || nilSeen {
+			if o.NoSkipNil {
+				nilSeen = true
+			}
+			out.Append(nil, dontLock)
+			continue
+		}
+
+		if acc == nil || replace(*acc, *v) {
+			acc = v
+		}
+		out.Append(*acc, dontLock)
+	}
+
+	return out, nil
+}
+
+// Diff returns a new Series containing the time.Duration between each time and the time periods rows earlier.
+// periods can be negative. Rows without a corresponding earlier value are nil.
+func (s *SeriesTime) Diff(ctx context.Context, periods int, opts ...StatsOptions) (*SeriesGeneric, error) {
+	vals, _, _, err := s.statsValues(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	out := NewSeriesGeneric(s.name, time.Duration(0), &SeriesInit{Capacity: len(vals)})
+
+	for i, v := range vals {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		j := i - periods
+		if v == nil || j < 0 || j >= len(vals) || vals[j] == nil {
+			out.Append(nil, dontLock)
+			continue
+		}
+		out.Append(v.Sub(*vals[j]), dontLock)
+	}
+
+	return out, nil
+}
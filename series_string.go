@@ -783,6 +783,57 @@ func (s *SeriesString) ToSeriesFloat64(ctx context.Context, removeNil bool, conv
 	return ss, nil
 }
 
+// ToSeriesCategorical will convert the Series to a SeriesCategorical.
+// Categories are added in the order they are first encountered.
+// The operation does not lock the Series.
+func (s *SeriesString) ToSeriesCategorical(ctx context.Context, removeNil bool, conv ...func(interface{}) (*string, error)) (*SeriesCategorical, error) {
+
+	ec := NewErrorCollection()
+
+	cs := NewSeriesCategorical(s.name, &SeriesInit{Capacity: s.NRows(dontLock)})
+
+	for row, rowVal := range s.values {
+
+		// Cancel operation
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		if rowVal == nil {
+			if removeNil {
+				continue
+			}
+			cs.codes = append(cs.codes, nilCode)
+			cs.nilCount++
+		} else {
+			if len(conv) == 0 {
+				cs.codes = append(cs.codes, cs.category(*rowVal))
+			} else {
+				cv, err := conv[0](rowVal)
+				if err != nil {
+					// interpret as nil
+					cs.codes = append(cs.codes, nilCode)
+					cs.nilCount++
+					ec.AddError(&RowError{Row: row, Err: err}, false)
+				} else {
+					if cv == nil {
+						cs.codes = append(cs.codes, nilCode)
+						cs.nilCount++
+					} else {
+						cs.codes = append(cs.codes, cs.category(*cv))
+					}
+				}
+			}
+		}
+	}
+
+	if !ec.IsNil(false) {
+		return cs, ec
+	}
+
+	return cs, nil
+}
+
 // ToSeriesMixed will convert the Series to a SeriesMIxed.
 // The operation does not lock the Series.
 func (s *SeriesString) ToSeriesMixed(ctx context.Context, removeNil bool, conv ...func(interface{}) (interface{}, error)) (*SeriesMixed, error) {
@@ -889,7 +940,7 @@ func (s *SeriesString) IsEqual(ctx context.Context, s2 Series, opts ...IsEqualOp
 	}
 
 	// Check number of values
-	if len(s.values) != len(s.values) {
+	if len(s.values) != len(ss.values) {
 		return false, nil
 	}
 
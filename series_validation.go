@@ -20,7 +20,7 @@ func checkConcreteType(ct interface{}) error {
 	case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
 		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
 		reflect.Float32, reflect.Float64, reflect.Complex64, reflect.Complex128,
-		reflect.String, reflect.Struct:
+		reflect.String, reflect.Struct, reflect.Slice:
 
 		// Make sure concrete type is zero value
 		if !reflect.DeepEqual(ct, reflect.Zero(reflect.TypeOf(ct)).Interface()) {
@@ -0,0 +1,517 @@
+// Copyright 2018-20 PJ Engineering and Business Solutions Pty. Ltd. All rights reserved.
+
+package dataframe
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sort"
+	"time"
+)
+
+// WindowOptions modifies the behavior of Rolling, RollingTime and Expanding.
+type WindowOptions struct {
+
+	// MinPeriods sets the minimum number of non-nil values required in a window,
+	// otherwise the result is nil. When set to 0, it defaults to the window size for Rolling
+	// and 1 for RollingTime and Expanding.
+	MinPeriods int
+
+	// DontLock can be set to true if the Series should not be locked.
+	DontLock bool
+}
+
+// WindowFn is a custom reducer used by Window's Apply function. vals contains the non-nil values in the window.
+// A NaN return value is interpreted as nil.
+type WindowFn func(ctx context.Context, vals []float64) (float64, error)
+
+// Window is used to perform calculations over a moving window of a numerical Series.
+// The values of the Series are copied when the Window is created.
+type Window struct {
+	name       string
+	vals       []float64 // nil values are stored as NaN
+	starts     []int     // start of window (inclusive) for each row
+	minPeriods int
+}
+
+// Rolling returns a Window containing the current row and the n-1 previous rows.
+func (s *SeriesFloat64) Rolling(n int, opts ...WindowOptions) (*Window, error) {
+	return newRowWindow(s, n, opts...)
+}
+
+// RollingTime returns a Window containing all rows whose time in ts is within d of the current row's time.
+// ts must contain no nil values and be sorted in ascending order.
+func (s *SeriesFloat64) RollingTime(d time.Duration, ts *SeriesTime, opts ...WindowOptions) (*Window, error) {
+	return newTimeWindow(s, d, ts, opts...)
+}
+
+// Expanding returns a Window containing the current row and all previous rows.
+func (s *SeriesFloat64) Expanding(opts ...WindowOptions) (*Window, error) {
+	return newRowWindow(s, 0, opts...)
+}
+
+// Rolling returns a Window containing the current row and the n-1 previous rows.
+func (s *SeriesInt64) Rolling(n int, opts ...WindowOptions) (*Window, error) {
+	return newRowWindow(s, n, opts...)
+}
+
+// RollingTime returns a Window containing all rows whose time in ts is within d of the current row's time.
+// ts must contain no nil values and be sorted in ascending order.
+func (s *SeriesInt64) RollingTime(d time.Duration, ts *SeriesTime, opts ...WindowOptions) (*Window, error) {
+	return newTimeWindow(s, d, ts, opts...)
+}
+
+// Expanding returns a Window containing the current row and all previous rows.
+func (s *SeriesInt64) Expanding(opts ...WindowOptions) (*Window, error) {
+	return newRowWindow(s, 0, opts...)
+}
+
+// windowValues copies the values of a numerical Series. It does not lock the Series.
+func windowValues(s Series) (string, []float64) {
+	switch typ := s.(type) {
+	case *SeriesFloat64:
+		return typ.name, append([]float64(nil), typ.Values...)
+	case *SeriesInt64:
+		vals := make([]float64, 0, len(typ.values))
+		for _, v := range typ.values {
+			if v == nil {
+				vals = append(vals, nan())
+			} else {
+				vals = append(vals, float64(*v))
+			}
+		}
+		return typ.name, vals
+	}
+	panic("s must be a SeriesFloat64 or SeriesInt64")
+}
+
+// newRowWindow creates a Window with a fixed number of rows. If n is 0, an expanding Window is created.
+func newRowWindow(s Series, n int, opts ...WindowOptions) (*Window, error) {
+
+	if n < 0 {
+		return nil, errors.New("window size must not be negative")
+	}
+
+	if len(opts) == 0 {
+		opts = append(opts, WindowOptions{})
+	}
+
+	if !opts[0].DontLock {
+		s.Lock()
+		defer s.Unlock()
+	}
+
+	name, vals := windowValues(s)
+
+	w := &Window{
+		name:       name,
+		vals:       vals,
+		starts:     make([]int, len(vals)),
+		minPeriods: opts[0].MinPeriods,
+	}
+
+	if w.minPeriods == 0 {
+		if n == 0 {
+			w.minPeriods = 1
+		} else {
+			w.minPeriods = n
+		}
+	}
+
+	if n != 0 {
+		for i := range w.starts {
+			if start := i - n + 1; start > 0 {
+				w.starts[i] = start
+			}
+		}
+	}
+
+	return w, nil
+}
+
+// newTimeWindow creates a Window spanning a duration.
+func newTimeWindow(s Series, d time.Duration, ts *SeriesTime, opts ...WindowOptions) (*Window, error) {
+
+	if d <= 0 {
+		return nil, errors.New("duration must be positive")
+	}
+
+	if len(opts) == 0 {
+		opts = append(opts, WindowOptions{})
+	}
+
+	if !opts[0].DontLock {
+		s.Lock()
+		defer s.Unlock()
+		ts.lock.RLock()
+		defer ts.lock.RUnlock()
+	}
+
+	name, vals := windowValues(s)
+
+	if len(ts.Values) != len(vals) {
+		return nil, errors.New("different number of rows in series")
+	}
+
+	w := &Window{
+		name:       name,
+		vals:       vals,
+		starts:     make([]int, len(vals)),
+		minPeriods: opts[0].MinPeriods,
+	}
+
+	if w.minPeriods == 0 {
+		w.minPeriods = 1
+	}
+
+	var start int
+	for i, t := range ts.Values {
+		if t == nil {
+			return nil, &RowError{Row: i, Err: errors.New("time must not be nil")}
+		}
+		if i > 0 && t.Before(*ts.Values[i-1]) {
+			return nil, &RowError{Row: i, Err: errors.New("times must be sorted in ascending order")}
+		}
+
+		for t.Sub(*ts.Values[start]) >= d {
+			start++
+		}
+		w.starts[i] = start
+	}
+
+	return w, nil
+}
+
+// apply calls fn for each window with the non-nil values. The values are passed in row order.
+// Each window is scanned in full, so it is only used where the result can't be updated incrementally.
+func (w *Window) apply(ctx context.Context, fn func(vals []float64) float64) (*SeriesFloat64, error) {
+
+	out := NewSeriesFloat64(w.name, &SeriesInit{Size: len(w.vals)})
+
+	buf := []float64{}
+
+	for i := range w.vals {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		buf = buf[:0]
+		for _, v := range w.vals[w.starts[i] : i+1] {
+			if !isNaN(v) {
+				buf = append(buf, v)
+			}
+		}
+
+		if len(buf) == 0 || len(buf) < w.minPeriods {
+			continue
+		}
+
+		val := fn(buf)
+		if !isNaN(val) {
+			out.Values[i] = val
+			out.nilCount--
+		}
+	}
+
+	return out, nil
+}
+
+// slide calls add for each non-nil value entering a window and remove for each non-nil value leaving it.
+// result is then called with the number of non-nil values in the window to obtain the window's value.
+// It relies on the windows only moving forward (ie. starts is non-decreasing), so each value is added and removed once.
+func (w *Window) slide(ctx context.Context, add func(i int), remove func(i int), result func(count int) float64) (*SeriesFloat64, error) {
+
+	out := NewSeriesFloat64(w.name, &SeriesInit{Size: len(w.vals)})
+
+	var start, count int
+
+	for i, v := range w.vals {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		if !isNaN(v) {
+			add(i)
+			count++
+		}
+
+		for ; start < w.starts[i]; start++ {
+			if !isNaN(w.vals[start]) {
+				remove(start)
+				count--
+			}
+		}
+
+		if count == 0 || count < w.minPeriods {
+			continue
+		}
+
+		val := result(count)
+		if !isNaN(val) {
+			out.Values[i] = val
+			out.nilCount--
+		}
+	}
+
+	return out, nil
+}
+
+// runningSum is a compensated (Neumaier) sum of the finite values in a window that values can be added to and removed from.
+// Infinities are counted separately so that the sum is not NaN once they leave the window.
+type runningSum struct {
+	sum, c         float64
+	posInf, negInf int
+	removed        int // number of values removed since the sum was recomputed
+}
+
+func (r *runningSum) add(v float64) {
+	switch {
+	case math.IsInf(v, 1):
+		r.posInf++
+	case math.IsInf(v, -1):
+		r.negInf++
+	default:
+		r.addFinite(v)
+	}
+}
+
+func (r *runningSum) addFinite(v float64) {
+	t := r.sum + v
+	if math.Abs(r.sum) >= math.Abs(v) {
+		r.c += (r.sum - t) + v
+	} else {
+		r.c += (v - t) + r.sum
+	}
+	r.sum = t
+}
+
+// remove removes v from the sum. window contains the values that remain in the window.
+func (r *runningSum) remove(v float64, window []float64) {
+	switch {
+	case math.IsInf(v, 1):
+		r.posInf--
+	case math.IsInf(v, -1):
+		r.negInf--
+	default:
+		r.addFinite(-v)
+
+		// Removing values accumulates rounding errors, so the sum is recomputed once per window length of removals.
+		r.removed++
+		if r.removed >= len(window) {
+			r.reset(window)
+		}
+	}
+}
+
+// reset recomputes the sum of the finite values in window.
+func (r *runningSum) reset(window []float64) {
+	r.sum, r.c, r.removed = 0, 0, 0
+	for _, v := range window {
+		if !isNaN(v) && !math.IsInf(v, 0) {
+			r.addFinite(v)
+		}
+	}
+}
+
+func (r *runningSum) value() float64 {
+	switch {
+	case r.posInf > 0 && r.negInf > 0:
+		return nan()
+	case r.posInf > 0:
+		return math.Inf(1)
+	case r.negInf > 0:
+		return math.Inf(-1)
+	}
+	return r.sum + r.c
+}
+
+// Sum returns the sum of each window.
+func (w *Window) Sum(ctx context.Context) (*SeriesFloat64, error) {
+	var (
+		r   runningSum
+		end int // last row added
+	)
+	return w.slide(ctx,
+		func(i int) { r.add(w.vals[i]); end = i },
+		func(i int) { r.remove(w.vals[i], w.vals[i+1:end+1]) },
+		func(count int) float64 { return r.value() },
+	)
+}
+
+// Mean returns the mean of each window.
+func (w *Window) Mean(ctx context.Context) (*SeriesFloat64, error) {
+	var (
+		r   runningSum
+		end int // last row added
+	)
+	return w.slide(ctx,
+		func(i int) { r.add(w.vals[i]); end = i },
+		func(i int) { r.remove(w.vals[i], w.vals[i+1:end+1]) },
+		func(count int) float64 { return r.value() / float64(count) },
+	)
+}
+
+// extreme returns the minimum (or maximum) of each window using a monotonic queue of row numbers.
+func (w *Window) extreme(ctx context.Context, min bool) (*SeriesFloat64, error) {
+
+	// dominates reports if a makes b redundant (b can never be the extreme while a is in the window)
+	dominates := func(a, b float64) bool {
+		if min {
+			return a <= b
+		}
+		return a >= b
+	}
+
+	queue := []int{}
+	return w.slide(ctx,
+		func(i int) {
+			for len(queue) > 0 && dominates(w.vals[i], w.vals[queue[len(queue)-1]]) {
+				queue = queue[:len(queue)-1]
+			}
+			queue = append(queue, i)
+		},
+		func(i int) {
+			if queue[0] == i {
+				queue = queue[1:]
+			}
+		},
+		func(count int) float64 { return w.vals[queue[0]] },
+	)
+}
+
+// Min returns the minimum of each window.
+func (w *Window) Min(ctx context.Context) (*SeriesFloat64, error) {
+	return w.extreme(ctx, true)
+}
+
+// Max returns the maximum of each window.
+func (w *Window) Max(ctx context.Context) (*SeriesFloat64, error) {
+	return w.extreme(ctx, false)
+}
+
+// Std returns the sample standard deviation of each window.
+// Windows with less than 2 values return nil.
+func (w *Window) Std(ctx context.Context) (*SeriesFloat64, error) {
+
+	// Welford's algorithm over the finite values. Infinities are counted separately.
+	var (
+		n, mean, m2 float64
+		inf         int
+		end         int // last row added
+	)
+
+	add := func(v float64) {
+		n++
+		delta := v - mean
+		mean = mean + delta/n
+		m2 = m2 + delta*(v-mean)
+	}
+
+	return w.slide(ctx,
+		func(i int) {
+			end = i
+			if math.IsInf(w.vals[i], 0) {
+				inf++
+				return
+			}
+			add(w.vals[i])
+		},
+		func(i int) {
+			v := w.vals[i]
+			if math.IsInf(v, 0) {
+				inf--
+				return
+			}
+
+			prev := m2
+			n--
+			if n > 0 {
+				delta := v - mean
+				mean = mean - delta/n
+				m2 = m2 - delta*(v-mean)
+			}
+
+			// Recompute the window when most of m2 cancels out, since the rounding errors of v would dominate
+			if n <= 1 || m2 < prev*stdCancellation {
+				n, mean, m2 = 0, 0, 0
+				for _, v := range w.vals[i+1 : end+1] {
+					if !isNaN(v) && !math.IsInf(v, 0) {
+						add(v)
+					}
+				}
+			}
+		},
+		func(count int) float64 {
+			if count < 2 || inf > 0 {
+				return nan()
+			}
+			if m2 < 0 {
+				// Rounding errors
+				return 0
+			}
+			return math.Sqrt(m2 / (n - 1))
+		},
+	)
+}
+
+// stdCancellation is the fraction of m2 below which Std recomputes a window after a removal.
+const stdCancellation = 1e-6
+
+// Median returns the median of each window.
+func (w *Window) Median(ctx context.Context) (*SeriesFloat64, error) {
+	return w.Quantile(ctx, 0.5)
+}
+
+// Quantile returns the q-th quantile of each window using linear interpolation.
+// q must be between 0 and 1.
+func (w *Window) Quantile(ctx context.Context, q float64) (*SeriesFloat64, error) {
+
+	if q < 0 || q > 1 {
+		return nil, errors.New("q must be between 0 and 1")
+	}
+
+	return w.apply(ctx, func(vals []float64) float64 {
+		sort.Float64s(vals)
+		return quantile(vals, q)
+	})
+}
+
+// Apply returns the result of fn for each window.
+func (w *Window) Apply(ctx context.Context, fn WindowFn) (*SeriesFloat64, error) {
+
+	var fnErr error
+
+	out, err := w.apply(ctx, func(vals []float64) float64 {
+		if fnErr != nil {
+			return nan()
+		}
+		val, err := fn(ctx, vals)
+		if err != nil {
+			fnErr = err
+			return nan()
+		}
+		return val
+	})
+	if err != nil {
+		return nil, err
+	}
+	if fnErr != nil {
+		return nil, fnErr
+	}
+
+	return out, nil
+}
+
+// quantile returns the q-th quantile of sorted using linear interpolation.
+// sorted must not be empty.
+func quantile(sorted []float64, q float64) float64 {
+	pos := q * float64(len(sorted)-1)
+	lower := int(math.Floor(pos))
+	upper := int(math.Ceil(pos))
+	if lower == upper {
+		return sorted[lower]
+	}
+	frac := pos - float64(lower)
+	return sorted[lower] + frac*(sorted[upper]-sorted[lower])
+}
@@ -0,0 +1,255 @@
+// Copyright 2018-20 PJ Engineering and Business Solutions Pty. Ltd. All rights reserved.
+
+package dataframe
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+	"time"
+)
+
+func TestWindow(t *testing.T) {
+	ctx := context.Background()
+
+	s := NewSeriesFloat64("sales", nil, 1.0, 2.0, nil, 4.0, 5.0)
+	si := NewSeriesInt64("sales", nil, 1, 2, nil, 4, 5)
+
+	base := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	ts := NewSeriesTime("date", nil, base, base.Add(time.Hour), base.Add(2*time.Hour), base.Add(5*time.Hour), base.Add(6*time.Hour))
+
+	mustWindow := func(w *Window, err error) *Window {
+		if err != nil {
+			t.Fatalf("wrong err: expected: %v actual: %v", nil, err)
+		}
+		return w
+	}
+
+	tests := []struct {
+		w        *Window
+		fn       func(*Window) (*SeriesFloat64, error)
+		expected *SeriesFloat64
+	}{
+		{
+			mustWindow(s.Rolling(2)),
+			func(w *Window) (*SeriesFloat64, error) { return w.Sum(ctx) },
+			NewSeriesFloat64("sales", nil, nil, 3.0, nil, nil, 9.0),
+		},
+		{
+			mustWindow(si.Rolling(2, WindowOptions{MinPeriods: 1})),
+			func(w *Window) (*SeriesFloat64, error) { return w.Mean(ctx) },
+			NewSeriesFloat64("sales", nil, 1.0, 1.5, 2.0, 4.0, 4.5),
+		},
+		{
+			mustWindow(s.Expanding()),
+			func(w *Window) (*SeriesFloat64, error) { return w.Max(ctx) },
+			NewSeriesFloat64("sales", nil, 1.0, 2.0, 2.0, 4.0, 5.0),
+		},
+		{
+			mustWindow(s.Expanding(WindowOptions{MinPeriods: 3})),
+			func(w *Window) (*SeriesFloat64, error) { return w.Median(ctx) },
+			NewSeriesFloat64("sales", nil, nil, nil, nil, 2.0, 3.0),
+		},
+		{
+			mustWindow(s.RollingTime(2*time.Hour, ts)),
+			func(w *Window) (*SeriesFloat64, error) { return w.Min(ctx) },
+			NewSeriesFloat64("sales", nil, 1.0, 1.0, 2.0, 4.0, 4.0),
+		},
+		{
+			mustWindow(s.Rolling(3)),
+			func(w *Window) (*SeriesFloat64, error) {
+				return w.Apply(ctx, func(ctx context.Context, vals []float64) (float64, error) {
+					return float64(len(vals)), nil
+				})
+			},
+			NewSeriesFloat64("sales", nil, nil, nil, nil, nil, nil),
+		},
+	}
+
+	for i, tc := range tests {
+		out, err := tc.fn(tc.w)
+		if err != nil {
+			t.Errorf("%d: wrong err: expected: %v actual: %v", i, nil, err)
+			continue
+		}
+
+		if eq, _ := out.IsEqual(ctx, tc.expected, IsEqualOptions{CheckName: true}); !eq {
+			t.Errorf("%d: wrong val: expected: %v actual: %v", i, tc.expected, out)
+		}
+	}
+}
+
+func TestWindowIncremental(t *testing.T) {
+	ctx := context.Background()
+
+	r := rand.New(rand.NewSource(1))
+
+	base := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	s := NewSeriesFloat64("sales", nil)
+	ts := NewSeriesTime("date", nil)
+	for i := 0; i < 300; i++ {
+		if r.Intn(10) == 0 {
+			s.Append(nil)
+		} else {
+			s.Append(float64(r.Intn(1000)) / 10)
+		}
+		base = base.Add(time.Duration(r.Intn(3)) * time.Hour)
+		ts.Append(base)
+	}
+
+	windows := []func() (*Window, error){
+		func() (*Window, error) { return s.Rolling(7, WindowOptions{MinPeriods: 2}) },
+		func() (*Window, error) { return s.Expanding() },
+		func() (*Window, error) { return s.RollingTime(5*time.Hour, ts) },
+	}
+
+	// Reference implementations that scan each window
+	naive := map[string]WindowFn{
+		"sum": func(ctx context.Context, vals []float64) (float64, error) {
+			var sum float64
+			for _, v := range vals {
+				sum = sum + v
+			}
+			return sum, nil
+		},
+		"mean": func(ctx context.Context, vals []float64) (float64, error) {
+			var sum float64
+			for _, v := range vals {
+				sum = sum + v
+			}
+			return sum / float64(len(vals)), nil
+		},
+		"min": func(ctx context.Context, vals []float64) (float64, error) {
+			sort.Float64s(vals)
+			return vals[0], nil
+		},
+		"max": func(ctx context.Context, vals []float64) (float64, error) {
+			sort.Float64s(vals)
+			return vals[len(vals)-1], nil
+		},
+		"std": func(ctx context.Context, vals []float64) (float64, error) {
+			if len(vals) < 2 {
+				return math.NaN(), nil
+			}
+			var sum float64
+			for _, v := range vals {
+				sum = sum + v
+			}
+			mean := sum / float64(len(vals))
+
+			var ss float64
+			for _, v := range vals {
+				ss = ss + (v-mean)*(v-mean)
+			}
+			return math.Sqrt(ss / float64(len(vals)-1)), nil
+		},
+	}
+
+	for i, newWindow := range windows {
+		w, err := newWindow()
+		if err != nil {
+			t.Fatalf("wrong err: expected: %v actual: %v", nil, err)
+		}
+
+		fns := map[string]func(context.Context) (*SeriesFloat64, error){
+			"sum":  w.Sum,
+			"mean": w.Mean,
+			"min":  w.Min,
+			"max":  w.Max,
+			"std":  w.Std,
+		}
+
+		for name, fn := range fns {
+			out, err := fn(ctx)
+			if err != nil {
+				t.Fatalf("wrong err: expected: %v actual: %v", nil, err)
+			}
+
+			expected, err := w.Apply(ctx, naive[name])
+			if err != nil {
+				t.Fatalf("wrong err: expected: %v actual: %v", nil, err)
+			}
+
+			for row := range out.Values {
+				e, a := expected.Values[row], out.Values[row]
+				if isNaN(e) != isNaN(a) || (!isNaN(e) && math.Abs(e-a) > 1e-9*math.Max(1, math.Abs(e))) {
+					t.Errorf("window %d %s row %d: wrong val: expected: %v actual: %v", i, name, row, e, a)
+					break
+				}
+			}
+		}
+	}
+}
+
+func TestWindowCancellation(t *testing.T) {
+	ctx := context.Background()
+
+	nan := math.NaN()
+	inf := math.Inf(1)
+
+	tests := []struct {
+		s        *SeriesFloat64
+		fn       func(*Window) (*SeriesFloat64, error)
+		expected []float64
+	}{
+		// Large value followed by small ones
+		{
+			NewSeriesFloat64("sales", nil, 1e17, 1.0, 1.0, 1.0),
+			func(w *Window) (*SeriesFloat64, error) { return w.Sum(ctx) },
+			[]float64{nan, 1e17 + 1, 2, 2},
+		},
+		{
+			NewSeriesFloat64("sales", nil, 1e17, 1.0, 1.0, 1.0),
+			func(w *Window) (*SeriesFloat64, error) { return w.Mean(ctx) },
+			[]float64{nan, (1e17 + 1) / 2, 1, 1},
+		},
+		{
+			NewSeriesFloat64("sales", nil, 1e17, 1.0, 2.0, 4.0),
+			func(w *Window) (*SeriesFloat64, error) { return w.Std(ctx) },
+			[]float64{nan, math.Sqrt(5e33), math.Sqrt(0.5), math.Sqrt(2)},
+		},
+		// Infinity in the window
+		{
+			NewSeriesFloat64("sales", nil, 1.0, inf, 2.0, 3.0, 4.0),
+			func(w *Window) (*SeriesFloat64, error) { return w.Sum(ctx) },
+			[]float64{nan, inf, inf, 5, 7},
+		},
+		{
+			NewSeriesFloat64("sales", nil, 1.0, inf, 2.0, 3.0, 4.0),
+			func(w *Window) (*SeriesFloat64, error) { return w.Mean(ctx) },
+			[]float64{nan, inf, inf, 2.5, 3.5},
+		},
+		{
+			NewSeriesFloat64("sales", nil, 1.0, inf, 2.0, 3.0, 4.0),
+			func(w *Window) (*SeriesFloat64, error) { return w.Std(ctx) },
+			[]float64{nan, nan, nan, math.Sqrt(0.5), math.Sqrt(0.5)},
+		},
+		{
+			NewSeriesFloat64("sales", nil, inf, -inf, 1.0, 2.0),
+			func(w *Window) (*SeriesFloat64, error) { return w.Sum(ctx) },
+			[]float64{nan, nan, -inf, 3},
+		},
+	}
+
+	for i, tc := range tests {
+		w, err := tc.s.Rolling(2)
+		if err != nil {
+			t.Fatalf("wrong err: expected: %v actual: %v", nil, err)
+		}
+
+		out, err := tc.fn(w)
+		if err != nil {
+			t.Fatalf("wrong err: expected: %v actual: %v", nil, err)
+		}
+
+		for row, e := range tc.expected {
+			a := out.Values[row]
+			if isNaN(e) != isNaN(a) || (!isNaN(e) && !math.IsInf(e, 0) && math.Abs(e-a) > 1e-9*math.Max(1, math.Abs(e))) || (math.IsInf(e, 0) && e != a) {
+				t.Errorf("%d row %d: wrong val: expected: %v actual: %v", i, row, e, a)
+			}
+		}
+	}
+}
@@ -0,0 +1,172 @@
+// Copyright 2019-20 PJ Engineering and Business Solutions Pty. Ltd. All rights reserved.
+
+package xseries
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/cmplx"
+
+	dataframe "github.com/rocketlaunchr/dataframe-go"
+)
+
+var dontLock = dataframe.Options{DontLock: true}
+
+// Arith implements the dataframe.Arither interface so that SeriesComplex128 can be used with
+// dataframe.Add, dataframe.Sub, dataframe.Mul, dataframe.Div and dataframe.Pow.
+// other can be a SeriesComplex128, SeriesFloat64, SeriesInt64 or a scalar (int, int64, float64 or complex128),
+// which is promoted to complex128. If reverse is set, s is the second operand.
+// A new SeriesComplex128 is returned. dataframe.OpMod is not supported.
+func (s *SeriesComplex128) Arith(ctx context.Context, op dataframe.ArithOp, other interface{}, reverse bool, opts ...dataframe.ArithOptions) (dataframe.Series, error) {
+
+	if op == dataframe.OpMod {
+		return nil, errors.New("modulo is not supported for complex128")
+	}
+
+	o, unlock, err := s.operand(other, opts...)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	n := len(s.Values)
+	out := NewSeriesComplex128(s.name, &dataframe.SeriesInit{Capacity: n})
+
+	for row := 0; row < n; row++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		x, y := s.Values[row], o(row)
+		if reverse {
+			x, y = y, x
+		}
+
+		var val complex128
+		if cmplx.IsNaN(x) || cmplx.IsNaN(y) {
+			val = cmplx.NaN()
+		} else {
+			switch op {
+			case dataframe.OpAdd:
+				val = x + y
+			case dataframe.OpSub:
+				val = x - y
+			case dataframe.OpMul:
+				val = x * y
+			case dataframe.OpDiv:
+				val = x / y
+			case dataframe.OpPow:
+				val = cmplx.Pow(x, y)
+			default:
+				return nil, fmt.Errorf("unknown operation: %d", op)
+			}
+		}
+
+		if cmplx.IsNaN(val) {
+			out.nilCount++
+		}
+		out.Values = append(out.Values, val)
+	}
+
+	return out, nil
+}
+
+// Compare implements the dataframe.Arither interface so that SeriesComplex128 can be used with
+// dataframe.Eq and dataframe.Ne. Complex numbers are not ordered, so other comparisons return an error.
+func (s *SeriesComplex128) Compare(ctx context.Context, op dataframe.CompareOp, other interface{}, reverse bool, opts ...dataframe.ArithOptions) (*dataframe.SeriesBool, error) {
+
+	if op != dataframe.OpEq && op != dataframe.OpNe {
+		return nil, errors.New("complex128 values can only be compared for equality")
+	}
+
+	o, unlock, err := s.operand(other, opts...)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	n := len(s.Values)
+	out := dataframe.NewSeriesBool(s.name, &dataframe.SeriesInit{Capacity: n})
+
+	for row := 0; row < n; row++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		x, y := s.Values[row], o(row)
+		if cmplx.IsNaN(x) || cmplx.IsNaN(y) {
+			out.Append(nil, dontLock)
+			continue
+		}
+		out.Append((x == y) == (op == dataframe.OpEq), dontLock)
+	}
+
+	return out, nil
+}
+
+// operand returns a function that provides the value of other at each row, promoted to complex128.
+// A nil value is returned as NaN. The returned unlock function must be called when finished.
+func (s *SeriesComplex128) operand(other interface{}, opts ...dataframe.ArithOptions) (func(row int) complex128, func(), error) {
+
+	if len(opts) == 0 {
+		opts = append(opts, dataframe.ArithOptions{})
+	}
+
+	var unlock = func() {}
+
+	if !opts[0].DontLock {
+		s.lock.RLock()
+		unlock = s.lock.RUnlock
+	}
+
+	var scalar complex128
+	switch x := other.(type) {
+	case int:
+		scalar = complex(float64(x), 0)
+	case int64:
+		scalar = complex(float64(x), 0)
+	case float64:
+		scalar = complex(x, 0)
+	case complex128:
+		scalar = x
+	case dataframe.Series:
+		if x.NRows(dontLock) != len(s.Values) {
+			unlock()
+			return nil, nil, errors.New("different number of rows in series")
+		}
+
+		if !opts[0].DontLock && x != dataframe.Series(s) {
+			x.Lock()
+			sUnlock := unlock
+			unlock = func() {
+				x.Unlock()
+				sUnlock()
+			}
+		}
+
+		switch xs := x.(type) {
+		case *SeriesComplex128:
+			return func(row int) complex128 { return xs.Values[row] }, unlock, nil
+		case *dataframe.SeriesFloat64:
+			return func(row int) complex128 { return complex(xs.Values[row], 0) }, unlock, nil
+		}
+
+		return func(row int) complex128 {
+			switch v := x.Value(row, dontLock).(type) {
+			case int64:
+				return complex(float64(v), 0)
+			case float64:
+				return complex(v, 0)
+			case complex128:
+				return v
+			}
+			return cmplx.NaN()
+		}, unlock, nil
+	default:
+		unlock()
+		return nil, nil, fmt.Errorf("unsupported operand type: %T", other)
+	}
+
+	return func(row int) complex128 { return scalar }, unlock, nil
+}
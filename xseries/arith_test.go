@@ -0,0 +1,60 @@
+// Copyright 2019-20 PJ Engineering and Business Solutions Pty. Ltd. All rights reserved.
+
+package xseries
+
+import (
+	"context"
+	"testing"
+
+	dataframe "github.com/rocketlaunchr/dataframe-go"
+)
+
+func TestArith(t *testing.T) {
+	ctx := context.Background()
+
+	sc := NewSeriesComplex128("c", nil, 1+2i, nil, 3)
+	si := dataframe.NewSeriesInt64("i", nil, 1, 2, nil)
+	sf := dataframe.NewSeriesFloat64("f", nil, 2.0, 1.0, 4.0)
+
+	tests := []struct {
+		op       dataframe.ArithOp
+		a, b     interface{}
+		expected dataframe.Series
+	}{
+		{dataframe.OpAdd, sc, si, NewSeriesComplex128("c", nil, 2+2i, nil, nil)},
+		{dataframe.OpSub, sf, sc, NewSeriesComplex128("c", nil, 1-2i, nil, 1)},
+		{dataframe.OpMul, sc, 2i, NewSeriesComplex128("c", nil, -4+2i, nil, 6i)},
+		{dataframe.OpDiv, 6, sc, NewSeriesComplex128("c", nil, 1.2-2.4i, nil, 2)},
+		{dataframe.OpPow, sc, 0, NewSeriesComplex128("c", nil, 1, nil, 1)},
+	}
+
+	for i, tc := range tests {
+		s, err := dataframe.Arith(ctx, tc.op, tc.a, tc.b)
+		if err != nil {
+			t.Fatalf("%d: wrong err: expected: %v actual: %v", i, nil, err)
+		}
+
+		if eq, _ := s.IsEqual(ctx, tc.expected, dataframe.IsEqualOptions{CheckName: true}); !eq {
+			t.Errorf("%d: wrong val: expected: %v actual: %v", i, tc.expected, s)
+		}
+	}
+
+	// Comparison
+	mask, err := dataframe.Eq(ctx, sc, NewSeriesComplex128("d", nil, 1+2i, 1, 4))
+	if err != nil {
+		t.Fatalf("wrong err: expected: %v actual: %v", nil, err)
+	}
+
+	expected := dataframe.NewSeriesBool("c", nil, true, nil, false)
+	if eq, _ := mask.IsEqual(ctx, expected, dataframe.IsEqualOptions{CheckName: true}); !eq {
+		t.Errorf("wrong val: expected: %v actual: %v", expected, mask)
+	}
+
+	if _, err := dataframe.Lt(ctx, sc, 1); err == nil {
+		t.Errorf("wrong err: expected: %v actual: %v", "error", err)
+	}
+
+	if _, err := dataframe.Mod(ctx, sc, 2); err == nil {
+		t.Errorf("wrong err: expected: %v actual: %v", "error", err)
+	}
+}